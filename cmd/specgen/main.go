@@ -1,11 +1,15 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/wontaeyang/go-specgen/pkg/config"
+	"github.com/wontaeyang/go-specgen/pkg/diagnostic"
 	"github.com/wontaeyang/go-specgen/pkg/generator"
 	"github.com/wontaeyang/go-specgen/pkg/parser"
 	"github.com/wontaeyang/go-specgen/pkg/resolver"
@@ -16,12 +20,30 @@ const (
 	version = "1.0.0"
 )
 
+// stringList is a flag.Value that collects repeated string flags
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
 func main() {
 	// Define flags
-	packagePath := flag.String("package", ".", "Path to the Go package to parse")
+	var packagePaths stringList
+	flag.Var(&packagePaths, "package", "Go package path or pattern to parse (repeatable, e.g. ./api/...)")
+	apiPackage := flag.String("api-package", "", "Package holding the @api annotation when several packages are parsed")
+	configPath := flag.String("config", "", "Config file with type mappings (YAML or JSON)")
 	outputPath := flag.String("output", "openapi.yaml", "Output file path")
 	format := flag.String("format", "yaml", "Output format: json or yaml")
 	openapiVersion := flag.String("openapi", "3.0", "OpenAPI version: 3.0, 3.1, or 3.2")
+	order := flag.String("order", "source", "Order of paths and schemas: source, alpha, or tag")
+	werror := flag.Bool("Werror", false, "Treat warnings as errors")
+	diagnosticsFormat := flag.String("diagnostics-format", "text", "Format of errors and warnings: text, json, or sarif")
 	showVersion := flag.Bool("version", false, "Show version")
 	showHelp := flag.Bool("help", false, "Show help")
 
@@ -57,60 +79,119 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Validate order
+	outputOrder := generator.Order(*order)
+	switch outputOrder {
+	case generator.OrderSource, generator.OrderAlpha, generator.OrderTag:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid order '%s'. Must be 'source', 'alpha', or 'tag'\n", *order)
+		os.Exit(1)
+	}
+
+	// Validate diagnostics format
+	rep := &reporter{format: diagnostic.Format(*diagnosticsFormat)}
+	switch rep.format {
+	case diagnostic.FormatText, diagnostic.FormatJSON, diagnostic.FormatSARIF:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid diagnostics format '%s'. Must be 'text', 'json', or 'sarif'\n", *diagnosticsFormat)
+		os.Exit(1)
+	}
+
+	if len(packagePaths) == 0 {
+		packagePaths = stringList{"."}
+	}
+
 	// Run the generation
-	if err := generate(*packagePath, *outputPath, outputFormat, *openapiVersion); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	err := generate(packagePaths, *apiPackage, *configPath, *outputPath, outputFormat, *openapiVersion, outputOrder, *werror, rep)
+	if err != nil {
+		rep.reportError(err)
+	}
+	if err := rep.flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write diagnostics: %v\n", err)
+		os.Exit(1)
+	}
+	if err != nil {
 		os.Exit(1)
 	}
 
-	fmt.Printf("Successfully generated OpenAPI spec: %s\n", *outputPath)
+	rep.progress("Successfully generated OpenAPI spec: %s\n", *outputPath)
 }
 
-func generate(packagePath, outputPath string, format generator.OutputFormat, openapiVersion string) error {
-	// Step 1: Parse the package
-	fmt.Println("Parsing package...")
-	p := parser.NewParser(packagePath)
-	parsed, err := p.Parse()
-	if err != nil {
-		return fmt.Errorf("failed to parse package: %w", err)
+func generate(packagePaths []string, apiPackage, configPath, outputPath string, format generator.OutputFormat, openapiVersion string, order generator.Order, werror bool, rep *reporter) error {
+	// Load the config file
+	var cfg *config.Config
+	if configPath != "" {
+		var err error
+		cfg, err = config.Load(configPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Step 1: Parse the packages
+	rep.progress("Parsing package...\n")
+	p := parser.NewMultiParser(packagePaths, apiPackage)
+	parsed, parseErr := p.Parse()
+	if parsed == nil {
+		return fmt.Errorf("failed to parse package: %w", parseErr)
+	}
+
+	// Warnings are silenced by specgen:ignore comments and the ignore list of the config
+	filter := &diagnostic.Filter{
+		Suppressions: p.Suppressions(),
+		Werror:       werror,
+	}
+	if cfg != nil {
+		filter.Ignore = cfg.Ignore
 	}
 
 	// Step 2: Resolve types
-	fmt.Println("Resolving types...")
-	r, err := resolver.NewResolver(packagePath, p.Comments())
+	rep.progress("Resolving types...\n")
+	r, err := resolver.NewResolverFromComments(p.AllComments()...)
 	if err != nil {
 		return fmt.Errorf("failed to create resolver: %w", err)
 	}
 
-	resolved, err := r.Resolve(parsed)
-	if err != nil {
-		return fmt.Errorf("failed to resolve types: %w", err)
+	if cfg != nil {
+		if err := cfg.Apply(r); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
+	// Declarations with errors are skipped, so the remaining ones are resolved
+	// and the problems of both stages are reported at once
+	resolved, resolveErr := r.Resolve(parsed)
+	warningErr := rep.reportWarnings(filter.Apply(r.Warnings()))
+	if err := diagnostic.Join(parseErr, resolveErr, warningErr); err != nil {
+		return err
 	}
 
 	// Step 3: Validate
-	fmt.Println("Validating...")
+	rep.progress("Validating...\n")
 	v := validator.NewValidator()
+	v.SetVersion(openapiVersion)
 	if err := v.Validate(resolved); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
 	// Step 4: Generate OpenAPI spec
-	fmt.Println("Generating OpenAPI spec...")
+	rep.progress("Generating OpenAPI spec...\n")
 	gen := generator.NewGenerator(openapiVersion)
+	gen.SetOrder(order)
 	spec, err := gen.Generate(resolved)
 	if err != nil {
 		return fmt.Errorf("failed to generate spec: %w", err)
 	}
 
 	// Step 5: Render to output format
-	fmt.Println("Rendering output...")
+	rep.progress("Rendering output...\n")
 	data, err := gen.Render(spec, format)
 	if err != nil {
 		return fmt.Errorf("failed to render spec: %w", err)
 	}
 
 	// Step 6: Write to file
-	fmt.Printf("Writing to %s...\n", outputPath)
+	rep.progress("Writing to %s...\n", outputPath)
 
 	// Create output directory if it doesn't exist
 	outputDir := filepath.Dir(outputPath)
@@ -127,6 +208,89 @@ func generate(packagePath, outputPath string, format generator.OutputFormat, ope
 	return nil
 }
 
+// reporter reports diagnostics: printed to stderr as they are found in text format, or collected
+// and written to stdout as one document when generation ends in json and sarif formats
+type reporter struct {
+	format      diagnostic.Format
+	diagnostics []*diagnostic.Diagnostic
+}
+
+// progress prints a progress message in text format, so that stdout only holds the document otherwise
+func (r *reporter) progress(format string, args ...any) {
+	if r.format == diagnostic.FormatText {
+		fmt.Printf(format, args...)
+	}
+}
+
+// report reports diagnostics, with their file names relative to the working directory
+func (r *reporter) report(diagnostics ...*diagnostic.Diagnostic) {
+	for _, d := range diagnostics {
+		d = relativeDiagnostic(d)
+		if r.format == diagnostic.FormatText {
+			fmt.Fprintln(os.Stderr, d.String())
+			continue
+		}
+		r.diagnostics = append(r.diagnostics, d)
+	}
+}
+
+// reportWarnings reports the warnings and returns the diagnostics promoted to errors by -Werror
+func (r *reporter) reportWarnings(diagnostics []*diagnostic.Diagnostic) error {
+	var errs []error
+	for _, d := range diagnostics {
+		if d.Severity == diagnostic.SeverityError {
+			errs = append(errs, d)
+			continue
+		}
+		r.report(d)
+	}
+	return diagnostic.Join(errs...)
+}
+
+// reportError reports the diagnostics of an error, and the errors without source position as they are
+func (r *reporter) reportError(err error) {
+	errs := []error{err}
+	var multi *diagnostic.MultiError
+	if errors.As(err, &multi) {
+		errs = multi.Errors
+	}
+
+	for _, err := range errs {
+		diagnostics := diagnostic.List(err)
+		if len(diagnostics) == 0 {
+			if r.format == diagnostic.FormatText {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				continue
+			}
+			diagnostics = []*diagnostic.Diagnostic{{Severity: diagnostic.SeverityError, Message: err.Error()}}
+		}
+		r.report(diagnostics...)
+	}
+}
+
+// flush writes the collected diagnostics to stdout in json or sarif format
+func (r *reporter) flush() error {
+	if r.format == diagnostic.FormatText {
+		return nil
+	}
+	return diagnostic.Write(os.Stdout, r.format, r.diagnostics, diagnostic.Tool{
+		Name:           "specgen",
+		Version:        version,
+		InformationURI: "https://github.com/wontaeyang/go-specgen",
+	})
+}
+
+// relativeDiagnostic returns a diagnostic with its file name relative to the working directory
+func relativeDiagnostic(d *diagnostic.Diagnostic) *diagnostic.Diagnostic {
+	relative := *d
+	if wd, err := os.Getwd(); err == nil && relative.Pos.Filename != "" {
+		if name, err := filepath.Rel(wd, relative.Pos.Filename); err == nil && !strings.HasPrefix(name, "..") {
+			relative.Pos.Filename = name
+		}
+	}
+	return &relative
+}
+
 func printHelp() {
 	fmt.Println("specgen - Generate OpenAPI specifications from Go code")
 	fmt.Println()
@@ -135,13 +299,24 @@ func printHelp() {
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  -package string")
-	fmt.Println("        Path to the Go package to parse (default \".\")")
+	fmt.Println("        Go package path or pattern to parse, repeatable (default \".\")")
+	fmt.Println("  -api-package string")
+	fmt.Println("        Package holding the @api annotation when several packages are parsed")
+	fmt.Println("  -config string")
+	fmt.Println("        Config file with type mappings (YAML or JSON)")
 	fmt.Println("  -output string")
 	fmt.Println("        Output file path (default \"openapi.yaml\")")
 	fmt.Println("  -format string")
 	fmt.Println("        Output format: json or yaml (default \"yaml\")")
 	fmt.Println("  -openapi string")
 	fmt.Println("        OpenAPI version: 3.0, 3.1, or 3.2 (default \"3.0\")")
+	fmt.Println("  -order string")
+	fmt.Println("        Order of paths and schemas: source, alpha, or tag (default \"source\")")
+	fmt.Println("  -Werror")
+	fmt.Println("        Treat warnings as errors")
+	fmt.Println("  -diagnostics-format string")
+	fmt.Println("        Format of errors and warnings: text, json, or sarif (default \"text\")")
+	fmt.Println("        json and sarif write one document to stdout")
 	fmt.Println("  -version")
 	fmt.Println("        Show version")
 	fmt.Println("  -help")
@@ -154,6 +329,12 @@ func printHelp() {
 	fmt.Println("  # Generate JSON spec from a specific package")
 	fmt.Println("  specgen -package ./api/handlers -format json -output openapi.json")
 	fmt.Println()
+	fmt.Println("  # Generate a single spec from every package under ./api")
+	fmt.Println("  specgen -package ./api/... -api-package ./api")
+	fmt.Println()
 	fmt.Println("  # Generate OpenAPI 3.1 spec")
 	fmt.Println("  specgen -openapi 3.1 -output openapi-3.1.yaml")
+	fmt.Println()
+	fmt.Println("  # Report findings as SARIF for code scanning")
+	fmt.Println("  specgen -diagnostics-format sarif > specgen.sarif")
 }
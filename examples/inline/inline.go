@@ -219,7 +219,7 @@ func CreateOrder(w http.ResponseWriter, r *http.Request) {
 			// @field { @description Postal code }
 			PostalCode string `json:"postal_code"`
 
-			// @field { @description Country code @pattern ^[A-Z]{2}$ }
+			// @field { @description Country code @pattern ^[A-Z]\{2\}$ }
 			Country string `json:"country"`
 		} `json:"shipping_address"`
 	}
@@ -0,0 +1,67 @@
+// Package config loads the specgen configuration file
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/wontaeyang/go-specgen/pkg/resolver"
+)
+
+// Config is the specgen configuration file (YAML or JSON)
+//
+//	typeMappings:
+//	  github.com/google/uuid.UUID:
+//	    type: string
+//	    format: uuid
+//	  github.com/shopspring/decimal.Decimal:
+//	    type: string
+//	    pattern: ^-?[0-9]+(\.[0-9]+)?$
+//	  example.com/types.Money:
+//	    schema:
+//	      type: object
+//	      properties:
+//	        amount: {type: integer}
+//	        currency: {type: string}
+//	ignore:
+//	  - json-marshaler
+type Config struct {
+	// TypeMappings map package-qualified Go types to OpenAPI schemas
+	TypeMappings map[string]*resolver.TypeMapping `yaml:"typeMappings"`
+
+	// Ignore lists the rules whose warnings are not reported (e.g. unsupported-validate-rule)
+	Ignore []string `yaml:"ignore"`
+}
+
+// Load reads and parses a configuration file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Apply registers the configuration with a resolver
+func (c *Config) Apply(r *resolver.Resolver) error {
+	goTypes := make([]string, 0, len(c.TypeMappings))
+	for goType := range c.TypeMappings {
+		goTypes = append(goTypes, goType)
+	}
+	sort.Strings(goTypes)
+
+	for _, goType := range goTypes {
+		if err := r.AddTypeMapping(goType, c.TypeMappings[goType]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
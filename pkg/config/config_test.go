@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wontaeyang/go-specgen/pkg/resolver"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "specgen.yaml")
+	data := `typeMappings:
+  github.com/google/uuid.UUID:
+    type: string
+    format: uuid
+  decimal.Decimal:
+    type: string
+    pattern: ^-?[0-9]+$
+  example.com/types.Money:
+    schema:
+      type: object
+      properties:
+        amount:
+          type: integer
+ignore:
+  - json-marshaler
+  - unsupported-validate-rule
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.TypeMappings) != 3 {
+		t.Fatalf("TypeMappings = %d, want 3", len(cfg.TypeMappings))
+	}
+	if m := cfg.TypeMappings["github.com/google/uuid.UUID"]; m.Type != "string" || m.Format != "uuid" {
+		t.Errorf("uuid mapping = %+v", m)
+	}
+	if m := cfg.TypeMappings["decimal.Decimal"]; m.Pattern != "^-?[0-9]+$" {
+		t.Errorf("decimal pattern = %q", m.Pattern)
+	}
+	money := cfg.TypeMappings["example.com/types.Money"]
+	if money.Schema["type"] != "object" {
+		t.Errorf("money schema = %v", money.Schema)
+	}
+	if _, ok := money.Schema["properties"].(map[string]any); !ok {
+		t.Errorf("money properties = %T, want map", money.Schema["properties"])
+	}
+
+	if len(cfg.Ignore) != 2 || cfg.Ignore[0] != "json-marshaler" || cfg.Ignore[1] != "unsupported-validate-rule" {
+		t.Errorf("Ignore = %v, want [json-marshaler unsupported-validate-rule]", cfg.Ignore)
+	}
+
+	r, err := resolver.NewResolver("../parser/testdata/typemap")
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+	if err := cfg.Apply(r); err != nil {
+		t.Errorf("Apply() error = %v", err)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load() should fail for a missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "invalid.yaml")
+	if err := os.WriteFile(path, []byte("typeMappings: [1, 2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() should fail for invalid YAML")
+	}
+
+	cfg := &Config{TypeMappings: map[string]*resolver.TypeMapping{"UUID": {Type: "string"}}}
+	r, err := resolver.NewResolver("../parser/testdata/typemap")
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+	if err := cfg.Apply(r); err == nil {
+		t.Error("Apply() should fail for an unqualified type")
+	}
+}
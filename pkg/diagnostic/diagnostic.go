@@ -0,0 +1,241 @@
+// Package diagnostic defines the problems reported by the parser, resolver and validator,
+// located by source position and annotation path
+package diagnostic
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Severity is the severity of a diagnostic
+type Severity string
+
+const (
+	// SeverityError fails the generation
+	SeverityError Severity = "error"
+	// SeverityWarning is reported without failing the generation
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found in annotated Go source
+type Diagnostic struct {
+	// Pos is the source position (file:line:col); invalid when unknown
+	Pos token.Position
+
+	// Severity is error or warning
+	Severity Severity
+
+	// Rule identifies the kind of problem (e.g. unknown-schema)
+	Rule string
+
+	// Path is the annotation path (e.g. @endpoint[GET /users].@response[200]); "" when unknown
+	Path string
+
+	// Message describes the problem
+	Message string
+}
+
+// Errorf returns an error diagnostic
+func Errorf(pos token.Position, rule, path, format string, args ...any) *Diagnostic {
+	return &Diagnostic{
+		Pos:      pos,
+		Severity: SeverityError,
+		Rule:     rule,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// Warningf returns a warning diagnostic
+func Warningf(pos token.Position, rule, path, format string, args ...any) *Diagnostic {
+	d := Errorf(pos, rule, path, format, args...)
+	d.Severity = SeverityWarning
+	return d
+}
+
+// Error returns the position, annotation path and message of the diagnostic
+func (d *Diagnostic) Error() string {
+	var sb strings.Builder
+	if d.Pos.IsValid() {
+		sb.WriteString(d.Pos.String())
+		sb.WriteString(": ")
+	}
+	if d.Path != "" {
+		sb.WriteString(d.Path)
+		sb.WriteString(": ")
+	}
+	sb.WriteString(d.Message)
+	return sb.String()
+}
+
+// String formats the diagnostic like a compiler message, which editors can jump to:
+//
+//	api/users.go:12:4: error: @endpoint[GET /users]: references unknown schema: Usr [unknown-schema]
+func (d *Diagnostic) String() string {
+	var sb strings.Builder
+	if d.Pos.IsValid() {
+		sb.WriteString(d.Pos.String())
+		sb.WriteString(": ")
+	}
+	sb.WriteString(string(d.Severity))
+	sb.WriteString(": ")
+	if d.Path != "" {
+		sb.WriteString(d.Path)
+		sb.WriteString(": ")
+	}
+	sb.WriteString(d.Message)
+	if d.Rule != "" {
+		sb.WriteString(" [")
+		sb.WriteString(d.Rule)
+		sb.WriteString("]")
+	}
+	return sb.String()
+}
+
+// List returns the diagnostics in an error, looking through wrapped and joined errors
+// Returns nil when the error holds no diagnostic.
+func List(err error) []*Diagnostic {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case *Diagnostic:
+		return []*Diagnostic{e}
+	case interface{ Unwrap() []error }:
+		var result []*Diagnostic
+		for _, inner := range e.Unwrap() {
+			result = append(result, List(inner)...)
+		}
+		return result
+	case interface{ Unwrap() error }:
+		return List(e.Unwrap())
+	}
+	return nil
+}
+
+// Wrap returns err as an error diagnostic at pos with the annotation path
+// Diagnostics already held by err are kept, and given the path when they have none.
+func Wrap(err error, pos token.Position, rule, path string) error {
+	if err == nil {
+		return nil
+	}
+	if diagnostics := List(err); len(diagnostics) > 0 {
+		for _, d := range diagnostics {
+			if d.Path == "" {
+				d.Path = path
+			}
+			if !d.Pos.IsValid() {
+				d.Pos = pos
+			}
+		}
+		return err
+	}
+	return Errorf(pos, rule, path, "%v", err)
+}
+
+// MultiError contains every error found by a stage (parser, resolver or validator)
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d errors:\n", len(e.Errors)))
+	for i, err := range e.Errors {
+		sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, err.Error()))
+	}
+	return sb.String()
+}
+
+// Unwrap returns the errors, so that errors.As and List see each of them
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// Join returns the errors as a MultiError, or nil when there are none
+// Nested MultiErrors are flattened so that errors are numbered once.
+func Join(errs ...error) error {
+	var flat []error
+	for _, err := range errs {
+		if multi, ok := err.(*MultiError); ok {
+			flat = append(flat, multi.Errors...)
+		} else if err != nil {
+			flat = append(flat, err)
+		}
+	}
+	if len(flat) == 0 {
+		return nil
+	}
+	return &MultiError{Errors: flat}
+}
+
+// Suppression silences warnings within a range of source lines
+// It is read from a specgen:ignore comment.
+type Suppression struct {
+	// Filename and the first and last lines of the range
+	Filename string
+	FromLine int
+	ToLine   int
+
+	// Rules are the silenced rules; every rule when empty
+	Rules []string
+}
+
+// Suppresses reports whether the suppression silences a diagnostic
+func (s *Suppression) Suppresses(d *Diagnostic) bool {
+	if d.Pos.Filename != s.Filename || d.Pos.Line < s.FromLine || d.Pos.Line > s.ToLine {
+		return false
+	}
+	return len(s.Rules) == 0 || slices.Contains(s.Rules, d.Rule)
+}
+
+// Filter selects the warnings to report
+type Filter struct {
+	// Ignore lists the rules whose warnings are never reported
+	Ignore []string
+
+	// Suppressions silence warnings within parts of the source
+	Suppressions []*Suppression
+
+	// Werror reports the remaining warnings as errors
+	Werror bool
+}
+
+// Apply returns the diagnostics that are not suppressed, with warnings promoted to errors when Werror is set
+// Errors are never suppressed.
+func (f *Filter) Apply(diagnostics []*Diagnostic) []*Diagnostic {
+	var result []*Diagnostic
+	for _, d := range diagnostics {
+		if d.Severity == SeverityWarning {
+			if f.suppressed(d) {
+				continue
+			}
+			if f.Werror {
+				promoted := *d
+				promoted.Severity = SeverityError
+				d = &promoted
+			}
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+// suppressed reports whether a warning is ignored by the config or a specgen:ignore comment
+func (f *Filter) suppressed(d *Diagnostic) bool {
+	if slices.Contains(f.Ignore, d.Rule) {
+		return true
+	}
+	for _, s := range f.Suppressions {
+		if s.Suppresses(d) {
+			return true
+		}
+	}
+	return false
+}
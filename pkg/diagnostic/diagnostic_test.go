@@ -0,0 +1,229 @@
+package diagnostic
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"testing"
+)
+
+var testPos = token.Position{Filename: "api/users.go", Line: 12, Column: 4}
+
+func TestDiagnostic_Format(t *testing.T) {
+	tests := []struct {
+		name       string
+		diagnostic *Diagnostic
+		wantError  string
+		wantString string
+	}{
+		{
+			name:       "position, path and rule",
+			diagnostic: Errorf(testPos, "unknown-schema", "@endpoint[GET /users]", "references unknown schema: %s", "Usr"),
+			wantError:  "api/users.go:12:4: @endpoint[GET /users]: references unknown schema: Usr",
+			wantString: "api/users.go:12:4: error: @endpoint[GET /users]: references unknown schema: Usr [unknown-schema]",
+		},
+		{
+			name:       "warning",
+			diagnostic: Warningf(testPos, "json-marshaler", "", "type %s implements json.Marshaler", "Blob"),
+			wantError:  "api/users.go:12:4: type Blob implements json.Marshaler",
+			wantString: "api/users.go:12:4: warning: type Blob implements json.Marshaler [json-marshaler]",
+		},
+		{
+			name:       "unknown position",
+			diagnostic: Errorf(token.Position{}, "missing-api", "", "missing @api annotation"),
+			wantError:  "missing @api annotation",
+			wantString: "error: missing @api annotation [missing-api]",
+		},
+		{
+			name:       "without rule",
+			diagnostic: &Diagnostic{Pos: testPos, Severity: SeverityError, Path: "@api", Message: "missing required @title"},
+			wantError:  "api/users.go:12:4: @api: missing required @title",
+			wantString: "api/users.go:12:4: error: @api: missing required @title",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diagnostic.Error(); got != tt.wantError {
+				t.Errorf("Error() = %q, want %q", got, tt.wantError)
+			}
+			if got := tt.diagnostic.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	first := Errorf(testPos, "unknown-schema", "", "first")
+	second := Errorf(testPos, "unknown-tag", "", "second")
+
+	tests := []struct {
+		name string
+		err  error
+		want []*Diagnostic
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "plain error", err: errors.New("failed"), want: nil},
+		{name: "diagnostic", err: first, want: []*Diagnostic{first}},
+		{name: "wrapped", err: fmt.Errorf("failed to parse: %w", first), want: []*Diagnostic{first}},
+		{name: "joined", err: errors.Join(first, errors.New("plain"), second), want: []*Diagnostic{first, second}},
+		{name: "wrapped join", err: fmt.Errorf("failed: %w", errors.Join(first, second)), want: []*Diagnostic{first, second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := List(tt.err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("List() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("List()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("plain error", func(t *testing.T) {
+		err := Wrap(errors.New("type User not found in package"), testPos, "unresolved-type", "@schema[User]")
+
+		got := List(err)
+		if len(got) != 1 {
+			t.Fatalf("List() = %v, want 1 diagnostic", got)
+		}
+		if got[0].Rule != "unresolved-type" || got[0].Path != "@schema[User]" || got[0].Pos != testPos {
+			t.Errorf("diagnostic = %s", got[0])
+		}
+	})
+
+	t.Run("keeps inner diagnostics", func(t *testing.T) {
+		fieldPos := token.Position{Filename: "api/users.go", Line: 14, Column: 2}
+		inner := Errorf(fieldPos, "unknown-annotation", "", "unknown annotation @foo in @field")
+		err := Wrap(fmt.Errorf("failed to parse annotation of field Name: %w", inner), testPos, "invalid-field", "@schema[User].Name")
+
+		got := List(err)
+		if len(got) != 1 || got[0] != inner {
+			t.Fatalf("List() = %v, want the inner diagnostic", got)
+		}
+		if inner.Rule != "unknown-annotation" || inner.Pos != fieldPos || inner.Path != "@schema[User].Name" {
+			t.Errorf("diagnostic = %s, want its position and rule kept and the path filled", inner)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		if err := Wrap(nil, testPos, "unresolved-type", ""); err != nil {
+			t.Errorf("Wrap(nil) = %v, want nil", err)
+		}
+	})
+}
+
+func TestJoin(t *testing.T) {
+	first := Errorf(testPos, "unknown-schema", "", "first")
+	second := Errorf(testPos, "unknown-tag", "", "second")
+	plain := errors.New("plain")
+
+	tests := []struct {
+		name string
+		errs []error
+		want []error
+	}{
+		{name: "no errors", errs: nil, want: nil},
+		{name: "only nil", errs: []error{nil, nil}, want: nil},
+		{name: "skips nil", errs: []error{nil, first, nil, plain}, want: []error{first, plain}},
+		{name: "flattens", errs: []error{Join(first, plain), nil, Join(second)}, want: []error{first, plain, second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Join(tt.errs...)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Join() = %v, want nil", err)
+				}
+				return
+			}
+
+			multi, ok := err.(*MultiError)
+			if !ok {
+				t.Fatalf("Join() = %T, want *MultiError", err)
+			}
+			if len(multi.Errors) != len(tt.want) {
+				t.Fatalf("Join().Errors = %v, want %v", multi.Errors, tt.want)
+			}
+			for i := range tt.want {
+				if multi.Errors[i] != tt.want[i] {
+					t.Errorf("Join().Errors[%d] = %v, want %v", i, multi.Errors[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMultiError_Error(t *testing.T) {
+	single := &MultiError{Errors: []error{errors.New("test error")}}
+	if got := single.Error(); got != "test error" {
+		t.Errorf("Error() = %q, want %q", got, "test error")
+	}
+
+	multi := &MultiError{Errors: []error{errors.New("error 1"), errors.New("error 2")}}
+	want := "2 errors:\n  1. error 1\n  2. error 2\n"
+	if got := multi.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestFilter_Apply(t *testing.T) {
+	at := func(line int) token.Position {
+		return token.Position{Filename: "api/users.go", Line: line, Column: 2}
+	}
+	suppressions := []*Suppression{
+		{Filename: "api/users.go", FromLine: 10, ToLine: 12, Rules: []string{"unsupported-validate-rule"}},
+		{Filename: "api/users.go", FromLine: 20, ToLine: 20},
+	}
+
+	tests := []struct {
+		name       string
+		diagnostic *Diagnostic
+		filter     *Filter
+		want       Severity // "" when suppressed
+	}{
+		{name: "reported", diagnostic: Warningf(at(5), "unsupported-validate-rule", "", "w"), filter: &Filter{Suppressions: suppressions}, want: SeverityWarning},
+		{name: "ignored by config", diagnostic: Warningf(at(5), "json-marshaler", "", "w"), filter: &Filter{Ignore: []string{"json-marshaler"}}, want: ""},
+		{name: "suppressed rule", diagnostic: Warningf(at(11), "unsupported-validate-rule", "", "w"), filter: &Filter{Suppressions: suppressions}, want: ""},
+		{name: "other rule in range", diagnostic: Warningf(at(11), "json-marshaler", "", "w"), filter: &Filter{Suppressions: suppressions}, want: SeverityWarning},
+		{name: "after range", diagnostic: Warningf(at(13), "unsupported-validate-rule", "", "w"), filter: &Filter{Suppressions: suppressions}, want: SeverityWarning},
+		{name: "other file", diagnostic: Warningf(token.Position{Filename: "api/orders.go", Line: 11}, "unsupported-validate-rule", "", "w"), filter: &Filter{Suppressions: suppressions}, want: SeverityWarning},
+		{name: "every rule suppressed", diagnostic: Warningf(at(20), "shadowed-response", "", "w"), filter: &Filter{Suppressions: suppressions}, want: ""},
+		{name: "errors are kept", diagnostic: Errorf(at(20), "unknown-schema", "", "e"), filter: &Filter{Ignore: []string{"unknown-schema"}, Suppressions: suppressions}, want: SeverityError},
+		{name: "werror", diagnostic: Warningf(at(5), "json-marshaler", "", "w"), filter: &Filter{Werror: true}, want: SeverityError},
+		{name: "werror suppressed", diagnostic: Warningf(at(11), "unsupported-validate-rule", "", "w"), filter: &Filter{Suppressions: suppressions, Werror: true}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Apply([]*Diagnostic{tt.diagnostic})
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("Apply() = %v, want suppressed", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].Severity != tt.want {
+				t.Fatalf("Apply() = %v, want one %s", got, tt.want)
+			}
+			if got[0].Rule != tt.diagnostic.Rule || got[0].Pos != tt.diagnostic.Pos {
+				t.Errorf("Apply() = %s, want %s", got[0], tt.diagnostic)
+			}
+		})
+	}
+
+	// Promoting a warning leaves the original untouched
+	warning := Warningf(at(5), "json-marshaler", "", "w")
+	(&Filter{Werror: true}).Apply([]*Diagnostic{warning})
+	if warning.Severity != SeverityWarning {
+		t.Errorf("Severity = %s, want the warning unchanged", warning.Severity)
+	}
+}
@@ -0,0 +1,249 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Format is an output format of diagnostics
+type Format string
+
+const (
+	// FormatText writes one compiler-style line per diagnostic
+	FormatText Format = "text"
+	// FormatJSON writes one JSON object per line
+	FormatJSON Format = "json"
+	// FormatSARIF writes a SARIF 2.1.0 log
+	FormatSARIF Format = "sarif"
+)
+
+// Tool identifies the program reporting the diagnostics in SARIF output
+type Tool struct {
+	Name           string
+	Version        string
+	InformationURI string
+}
+
+// Write writes diagnostics in a format
+func Write(w io.Writer, format Format, diagnostics []*Diagnostic, tool Tool) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, diagnostics)
+	case FormatJSON:
+		return WriteJSON(w, diagnostics)
+	case FormatSARIF:
+		return WriteSARIF(w, diagnostics, tool)
+	default:
+		return fmt.Errorf("unsupported diagnostics format: %s", format)
+	}
+}
+
+// WriteText writes diagnostics like compiler messages, one per line
+func WriteText(w io.Writer, diagnostics []*Diagnostic) error {
+	for _, d := range diagnostics {
+		if _, err := fmt.Fprintln(w, d.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes diagnostics as line-delimited JSON, one object per line:
+//
+//	{"file":"api/users.go","line":12,"column":4,"severity":"error","rule":"unknown-schema","path":"@endpoint[GET /users]","message":"references unknown schema: Usr"}
+func WriteJSON(w io.Writer, diagnostics []*Diagnostic) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, d := range diagnostics {
+		if err := encoder.Encode(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the diagnostic with its position flattened and unknown parts left out
+func (d *Diagnostic) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		File     string   `json:"file,omitempty"`
+		Line     int      `json:"line,omitempty"`
+		Column   int      `json:"column,omitempty"`
+		Severity Severity `json:"severity"`
+		Rule     string   `json:"rule,omitempty"`
+		Path     string   `json:"path,omitempty"`
+		Message  string   `json:"message"`
+	}{
+		File:     d.Pos.Filename,
+		Line:     d.Pos.Line,
+		Column:   d.Pos.Column,
+		Severity: d.Severity,
+		Rule:     d.Rule,
+		Path:     d.Path,
+		Message:  d.Message,
+	})
+}
+
+// SARIF 2.1.0 log, reduced to the properties specgen reports
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	RuleIndex *int            `json:"ruleIndex,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// WriteSARIF writes diagnostics as a SARIF 2.1.0 log with a single run
+// The rules of the diagnostics are described with the metadata of Rules. Relative file
+// names are resolved against the %SRCROOT% base, so that they match the repository paths.
+func WriteSARIF(w io.Writer, diagnostics []*Diagnostic, tool Tool) error {
+	// Rules are listed once, sorted by ID, and results refer to them by index
+	var ruleIDs []string
+	for _, d := range diagnostics {
+		if d.Rule != "" && !slices.Contains(ruleIDs, d.Rule) {
+			ruleIDs = append(ruleIDs, d.Rule)
+		}
+	}
+	slices.Sort(ruleIDs)
+
+	rules := make([]sarifRule, len(ruleIDs))
+	for i, id := range ruleIDs {
+		rules[i] = sarifRule{ID: id}
+		if description, ok := Rules[id]; ok {
+			rules[i].ShortDescription = &sarifMessage{Text: description}
+		}
+	}
+
+	results := make([]sarifResult, len(diagnostics))
+	for i, d := range diagnostics {
+		text := d.Message
+		if d.Path != "" {
+			text = d.Path + ": " + text
+		}
+		results[i] = sarifResult{
+			RuleID:  d.Rule,
+			Level:   sarifLevel(d.Severity),
+			Message: sarifMessage{Text: text},
+		}
+		if d.Rule != "" {
+			index := slices.Index(ruleIDs, d.Rule)
+			results[i].RuleIndex = &index
+		}
+
+		var location sarifLocation
+		if d.Pos.Filename != "" {
+			location.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifact(d.Pos.Filename),
+			}
+			if d.Pos.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: d.Pos.Line, StartColumn: d.Pos.Column}
+			}
+		}
+		if d.Path != "" {
+			location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: d.Path}}
+		}
+		if location.PhysicalLocation != nil || location.LogicalLocations != nil {
+			results[i].Locations = []sarifLocation{location}
+		}
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           tool.Name,
+				Version:        tool.Version,
+				InformationURI: tool.InformationURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// sarifLevel returns the SARIF level of a severity
+func sarifLevel(severity Severity) string {
+	if severity == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// sarifArtifact returns the location of a file: a file URI when absolute,
+// otherwise a path relative to the source root
+func sarifArtifact(filename string) sarifArtifactLocation {
+	path := filepath.ToSlash(filename)
+	if filepath.IsAbs(filename) {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path // Windows drive letter
+		}
+		return sarifArtifactLocation{URI: (&url.URL{Scheme: "file", Path: path}).String()}
+	}
+	return sarifArtifactLocation{URI: (&url.URL{Path: path}).String(), URIBaseID: "%SRCROOT%"}
+}
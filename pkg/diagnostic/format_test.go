@@ -0,0 +1,163 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func testDiagnostics() []*Diagnostic {
+	return []*Diagnostic{
+		Errorf(testPos, "unknown-schema", "@endpoint[GET /users]", "references unknown schema: %s", "Usr"),
+		Warningf(token.Position{Filename: "api/models.go", Line: 18, Column: 2}, "json-marshaler", "", "type Blob implements json.Marshaler"),
+		Errorf(token.Position{}, "missing-api", "", "missing @api annotation"),
+		Errorf(testPos, "unknown-schema", "@endpoint[POST /users]", "references unknown schema: Usr"),
+		{Severity: SeverityError, Message: "failed to write output file"},
+	}
+}
+
+func TestWrite_Text(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatText, testDiagnostics()[:2], Tool{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := "api/users.go:12:4: error: @endpoint[GET /users]: references unknown schema: Usr [unknown-schema]\n" +
+		"api/models.go:18:2: warning: type Blob implements json.Marshaler [json-marshaler]\n"
+	if buf.String() != want {
+		t.Errorf("Write() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, testDiagnostics(), Tool{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := []string{
+		`{"file":"api/users.go","line":12,"column":4,"severity":"error","rule":"unknown-schema","path":"@endpoint[GET /users]","message":"references unknown schema: Usr"}`,
+		`{"file":"api/models.go","line":18,"column":2,"severity":"warning","rule":"json-marshaler","message":"type Blob implements json.Marshaler"}`,
+		`{"severity":"error","rule":"missing-api","message":"missing @api annotation"}`,
+		`{"file":"api/users.go","line":12,"column":4,"severity":"error","rule":"unknown-schema","path":"@endpoint[POST /users]","message":"references unknown schema: Usr"}`,
+		`{"severity":"error","message":"failed to write output file"}`,
+	}
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("Write() = %d lines, want %d:\n%s", len(got), len(want), buf.String())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %s, want %s", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestWrite_SARIF(t *testing.T) {
+	var buf bytes.Buffer
+	tool := Tool{Name: "specgen", Version: "1.0.0", InformationURI: "https://github.com/wontaeyang/go-specgen"}
+	if err := Write(&buf, FormatSARIF, testDiagnostics(), tool); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("log = version %s with %d runs, want version 2.1.0 with 1 run", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+
+	// Rules are listed once, sorted, with their description
+	driver := run.Tool.Driver
+	if driver.Name != "specgen" || driver.Version != "1.0.0" {
+		t.Errorf("driver = %s %s, want specgen 1.0.0", driver.Name, driver.Version)
+	}
+	var ruleIDs []string
+	for _, rule := range driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+		if rule.ShortDescription == nil || rule.ShortDescription.Text != Rules[rule.ID] {
+			t.Errorf("rule %s description = %v, want %q", rule.ID, rule.ShortDescription, Rules[rule.ID])
+		}
+	}
+	if got := strings.Join(ruleIDs, ","); got != "json-marshaler,missing-api,unknown-schema" {
+		t.Errorf("rules = %s, want json-marshaler,missing-api,unknown-schema", got)
+	}
+
+	if len(run.Results) != 5 {
+		t.Fatalf("results = %d, want 5", len(run.Results))
+	}
+
+	located := run.Results[0]
+	if located.RuleID != "unknown-schema" || located.RuleIndex == nil || *located.RuleIndex != 2 || located.Level != "error" {
+		t.Errorf("result = rule %s (index %v) level %s, want unknown-schema (index 2) level error", located.RuleID, located.RuleIndex, located.Level)
+	}
+	if located.Message.Text != "@endpoint[GET /users]: references unknown schema: Usr" {
+		t.Errorf("message = %q", located.Message.Text)
+	}
+	if len(located.Locations) != 1 || located.Locations[0].PhysicalLocation == nil {
+		t.Fatalf("locations = %+v, want a physical location", located.Locations)
+	}
+	physical := located.Locations[0].PhysicalLocation
+	if physical.ArtifactLocation.URI != "api/users.go" || physical.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+		t.Errorf("artifact = %+v, want api/users.go under %%SRCROOT%%", physical.ArtifactLocation)
+	}
+	if physical.Region == nil || physical.Region.StartLine != 12 || physical.Region.StartColumn != 4 {
+		t.Errorf("region = %+v, want 12:4", physical.Region)
+	}
+	if logical := located.Locations[0].LogicalLocations; len(logical) != 1 || logical[0].FullyQualifiedName != "@endpoint[GET /users]" {
+		t.Errorf("logical locations = %+v, want @endpoint[GET /users]", logical)
+	}
+
+	if warning := run.Results[1]; warning.Level != "warning" || *warning.RuleIndex != 0 {
+		t.Errorf("warning = level %s, rule index %d, want level warning, rule index 0", warning.Level, *warning.RuleIndex)
+	}
+	if unlocated := run.Results[2]; len(unlocated.Locations) != 0 {
+		t.Errorf("locations = %+v, want none without position or path", unlocated.Locations)
+	}
+	if plain := run.Results[4]; plain.RuleID != "" || plain.RuleIndex != nil || plain.Message.Text != "failed to write output file" {
+		t.Errorf("result = %+v, want a result without rule", plain)
+	}
+}
+
+func TestWrite_SARIF_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSARIF, nil, Tool{Name: "specgen"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// A clean run still holds the tool, with empty rules and results
+	if !strings.Contains(buf.String(), `"rules": []`) || !strings.Contains(buf.String(), `"results": []`) {
+		t.Errorf("Write() = %s, want empty rules and results", buf.String())
+	}
+}
+
+func TestSarifArtifact(t *testing.T) {
+	tests := []struct {
+		filename string
+		wantURI  string
+		wantBase string
+	}{
+		{filename: "api/users.go", wantURI: "api/users.go", wantBase: "%SRCROOT%"},
+		{filename: "api/my handlers.go", wantURI: "api/my%20handlers.go", wantBase: "%SRCROOT%"},
+		{filename: "/src/api/users.go", wantURI: "file:///src/api/users.go", wantBase: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got := sarifArtifact(tt.filename)
+			if got.URI != tt.wantURI || got.URIBaseID != tt.wantBase {
+				t.Errorf("sarifArtifact(%q) = %+v, want %s under %q", tt.filename, got, tt.wantURI, tt.wantBase)
+			}
+		})
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, Format("xml"), nil, Tool{}); err == nil {
+		t.Error("Write() should fail for an unknown format")
+	}
+}
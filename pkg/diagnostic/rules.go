@@ -0,0 +1,72 @@
+package diagnostic
+
+// Rules describes the rules reported by the parser, resolver, validator and generator, by ID
+// The descriptions are the rule metadata of SARIF output.
+var Rules = map[string]string{
+	// Annotation syntax (parser)
+	"unbalanced-braces":   "An annotation block has unbalanced braces",
+	"unknown-annotation":  "An annotation is not valid in its parent block",
+	"empty-block":         "An annotation block is empty but has required children",
+	"not-repeatable":      "An annotation that is not repeatable appears more than once",
+	"invalid-inline":      "An inline annotation or declaration is malformed",
+	"invalid-annotation":  "An annotation cannot be parsed",
+	"missing-method-path": "An @endpoint is missing its method or path",
+	"duplicate-parameter": "Two packages declare a parameter with the same name",
+
+	// Types (resolver)
+	"invalid-type-mapping": "A @typeMapping or config type mapping is invalid",
+	"unresolved-type":      "The Go type of a declaration cannot be resolved",
+	"invalid-field":        "A struct field cannot be resolved",
+	"invalid-endpoint":     "The declarations of an endpoint cannot be resolved",
+
+	// Resolver warnings
+	"ignored-anyof":             "@anyOf is ignored because @oneOf is given",
+	"ignored-discriminator":     "@discriminator is ignored without @oneOf, @anyOf or an interface type",
+	"unsupported-json-format":   "A json format option has no OpenAPI equivalent",
+	"json-marshaler":            "A type implements json.Marshaler, so its JSON shape is unknown",
+	"unsupported-validate-rule": "A validate rule has no OpenAPI equivalent",
+	"unsupported-xml-path":      "An xml path has no OpenAPI equivalent",
+	"unknown-parameter":         "A reference names an unknown parameter struct",
+	"shadowed-response":         "An inline response is ignored because the endpoint declares the same status code",
+
+	// API (validator)
+	"missing-api":             "The package has no @api annotation",
+	"missing-title":           "@api is missing @title",
+	"missing-version":         "@api is missing @version",
+	"unknown-security-scheme": "A security requirement names an undefined security scheme",
+	"invalid-security-scheme": "A security scheme is incomplete or of an unknown type",
+	"invalid-uri":             "A URI is invalid",
+	"unknown-tag":             "A tag is not defined with @tag",
+	"circular-tags":           "Parent tags form a cycle",
+	"unsupported-version":     "A feature requires a newer OpenAPI version",
+
+	// Schemas and parameters (validator)
+	"empty-schema":          "A schema has no fields",
+	"empty-parameter":       "A parameter struct has no fields",
+	"duplicate-field":       "Two fields have the same name",
+	"empty-composition":     "@oneOf or @anyOf has no schemas",
+	"unknown-schema":        "A reference names an unknown schema",
+	"invalid-discriminator": "A @discriminator is invalid",
+	"unresolved-struct":     "A struct field type is not a schema",
+	"invalid-enum":          "An enum is used on a type that does not support it",
+	"invalid-range":         "A minimum is greater than its maximum",
+	"invalid-constraint":    "A constraint does not apply to the field type",
+	"invalid-pattern":       "A @pattern is not a valid regular expression",
+	"invalid-parameter":     "A parameter field has a type its location does not support",
+
+	// Endpoints (validator)
+	"invalid-method":         "An HTTP method is invalid",
+	"missing-path":           "An endpoint has no path",
+	"invalid-path":           "An endpoint path is invalid",
+	"missing-response":       "An endpoint has no response",
+	"missing-path-parameter": "A path variable has no @path parameter",
+	"unused-path-parameter":  "A @path parameter is not used in the path",
+	"missing-content-type":   "A request has no @contentType",
+	"missing-body":           "A request has no @body",
+	"duplicate-content-type": "A content type is declared twice",
+	"invalid-status-code":    "A response status code is invalid",
+	"invalid-stream":         "A streamed response is invalid",
+	"duplicate-event":        "A Server-Sent Events stream declares an event twice",
+	"missing-event-schema":   "A Server-Sent Events event has no schema",
+	"parameter-conflict":     "Two parameters of an endpoint have the same name",
+}
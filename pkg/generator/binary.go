@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/wontaeyang/go-specgen/pkg/resolver"
+)
+
+// generateMediaTypeSchema generates the schema of the body of a media type
+// Raw bodies (@body binary, files such as io.Reader, or []byte outside JSON) describe the bytes
+// of the media type itself, e.g. contentMediaType: application/pdf in OpenAPI 3.1+.
+func (g *Generator) generateMediaTypeSchema(mediaType *resolver.ResolvedContent, schemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
+	if !isRawBody(mediaType) {
+		return g.generateBodySchema(mediaType.Body, schemas)
+	}
+
+	schema := g.schemaBuilder.NewSchema()
+	g.schemaBuilder.SetType(schema, "string")
+	g.schemaBuilder.SetBinary(schema, mediaType.ContentType)
+	return base.CreateSchemaProxy(schema)
+}
+
+// isRawBody reports whether the body of a media type is raw bytes: a file, or []byte in media types
+// other than JSON, where encoding/json would write it as a base64 string
+func isRawBody(mediaType *resolver.ResolvedContent) bool {
+	body := mediaType.Body
+	if body.Bind != nil || body.Type == nil {
+		return false
+	}
+
+	desc := body.Type
+	if desc.Ref != "" || desc.Items != nil || desc.MapValue != nil || len(desc.OneOf) > 0 || desc.OpenAPIType != "string" {
+		return false
+	}
+	switch desc.Format {
+	case "binary":
+		return true
+	case "byte":
+		return !isJSONContentType(mediaType.ContentType)
+	}
+	return false
+}
+
+// isJSONContentType reports whether a content type is JSON (application/json or a +json suffix)
+func isJSONContentType(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
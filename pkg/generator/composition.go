@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/wontaeyang/go-specgen/pkg/resolver"
+)
+
+// generateCompositionSchema generates the oneOf or anyOf schema of a polymorphic value,
+// referencing each alternative, with the discriminator mapping values to references
+func (g *Generator) generateCompositionSchema(c *resolver.Composition) *base.Schema {
+	schema := g.schemaBuilder.NewSchema()
+
+	refs := make([]*base.SchemaProxy, 0, len(c.Schemas))
+	for _, name := range c.Schemas {
+		refs = append(refs, base.CreateSchemaProxyRef(fmt.Sprintf("#/components/schemas/%s", name)))
+	}
+	if c.Kind == "anyOf" {
+		schema.AnyOf = refs
+	} else {
+		schema.OneOf = refs
+	}
+
+	if c.Discriminator != nil {
+		mapping := orderedmap.New[string, string]()
+		for _, m := range c.Discriminator.Mapping {
+			mapping.Set(m.Value, fmt.Sprintf("#/components/schemas/%s", m.Schema))
+		}
+		schema.Discriminator = &base.Discriminator{
+			PropertyName: c.Discriminator.PropertyName,
+			Mapping:      mapping,
+		}
+	}
+
+	return schema
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/wontaeyang/go-specgen/pkg/resolver"
+)
+
+// generateTypeSchema generates the schema of a type descriptor, walking nested arrays and maps
+// Leaves that are @schema types become references; with nil schemas, only references
+// resolved from annotations (bodies) are emitted and other named types are inlined by type.
+func (g *Generator) generateTypeSchema(desc *resolver.TypeDescriptor, schemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
+	// Handle schema references
+	if desc.Ref != "" {
+		return g.schemaRef(desc.Ref, desc.Nullable)
+	}
+
+	// Handle alternatives (User | Organization)
+	if len(desc.OneOf) > 0 {
+		schema := g.schemaBuilder.NewSchema()
+		for _, alternative := range desc.OneOf {
+			schema.OneOf = append(schema.OneOf, g.generateTypeSchema(alternative, schemas))
+		}
+		return base.CreateSchemaProxy(schema)
+	}
+	if desc.Items == nil && desc.MapValue == nil && desc.InlineFields == nil {
+		if refName, ok := schemaRefName(desc.GoType, schemas); ok {
+			return g.schemaRef(refName, desc.Nullable)
+		}
+	}
+
+	// Handle anonymous structs
+	if desc.InlineFields != nil {
+		if schemas == nil {
+			return g.buildInlineObjectSchemaSimple(desc.InlineFields)
+		}
+		return g.buildInlineObjectSchema(desc.InlineFields, schemas)
+	}
+
+	var schema *base.Schema
+	switch {
+	case desc.Composition != nil:
+		schema = g.generateCompositionSchema(desc.Composition)
+	case desc.IsAnyValue:
+		// Any value (empty schema)
+		return base.CreateSchemaProxy(g.schemaBuilder.NewSchema())
+	case desc.MappedSchema != nil:
+		schema = g.schemaFromMap(desc.MappedSchema)
+	case desc.Items != nil:
+		schema = g.schemaBuilder.NewSchema()
+		g.schemaBuilder.SetType(schema, "array")
+		schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{A: g.generateTypeSchema(desc.Items, schemas)}
+		if desc.Length != nil {
+			length := int64(*desc.Length)
+			schema.MinItems = &length
+			schema.MaxItems = &length
+		}
+	case desc.MapValue != nil:
+		schema = g.schemaBuilder.NewSchema()
+		g.schemaBuilder.SetType(schema, "object")
+		schema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{A: g.generateTypeSchema(desc.MapValue, schemas)}
+	default:
+		schema = g.schemaBuilder.NewSchema()
+		g.schemaBuilder.SetType(schema, desc.OpenAPIType)
+		g.schemaBuilder.SetFormat(schema, desc.Format)
+		schema.Pattern = desc.Pattern
+	}
+
+	if desc.Nullable {
+		g.schemaBuilder.SetNullable(schema, true)
+	}
+	return base.CreateSchemaProxy(schema)
+}
+
+// schemaRef returns a reference to a component schema, allowing null for nullable leaves
+func (g *Generator) schemaRef(name string, nullable bool) *base.SchemaProxy {
+	ref := base.CreateSchemaProxyRef(fmt.Sprintf("#/components/schemas/%s", name))
+	if nullable {
+		return g.schemaBuilder.NullableRef(ref)
+	}
+	return ref
+}
+
+// isContainer reports whether a descriptor is an array or map, whose elements need a nested schema
+func isContainer(desc *resolver.TypeDescriptor) bool {
+	return desc != nil && desc.Ref == "" && (desc.Items != nil || desc.MapValue != nil)
+}
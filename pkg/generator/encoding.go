@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/wontaeyang/go-specgen/pkg/resolver"
+	"go.yaml.in/yaml/v4"
+)
+
+// isMultipart reports whether a content type sends each property of its schema as a separate part
+func isMultipart(contentType string) bool {
+	return strings.HasPrefix(contentType, "multipart/")
+}
+
+// generateEncoding generates the encoding object of a multipart or form request body from the
+// @contentType and @header annotations of its top-level fields
+// Returns nil for other content types or when every part uses the defaults.
+func (g *Generator) generateEncoding(contentType string, fields []*resolver.ResolvedField) *orderedmap.Map[string, *v3.Encoding] {
+	multipart := isMultipart(contentType)
+	if !multipart && contentType != "application/x-www-form-urlencoded" {
+		return nil
+	}
+
+	var encoding *orderedmap.Map[string, *v3.Encoding]
+	for _, field := range fields {
+		part := &v3.Encoding{}
+		if field.Encoding != nil {
+			part.ContentType = strings.Join(field.Encoding.ContentTypes, ", ")
+			// Part headers only exist in multipart bodies
+			if multipart && len(field.Encoding.Headers) > 0 {
+				part.Headers = g.generateHeaders(field.Encoding.Headers)
+			}
+		}
+		if part.ContentType == "" && part.Headers == nil {
+			continue
+		}
+
+		if encoding == nil {
+			encoding = orderedmap.New[string, *v3.Encoding]()
+		}
+		encoding.Set(field.Name, part)
+	}
+	return encoding
+}
+
+// multipartFields returns the top-level fields of a multipart body with []byte fields, base64
+// strings in JSON, described as binary parts, or nil for other content types or when the body
+// has no []byte field
+func multipartFields(contentType string, fields []*resolver.ResolvedField) []*resolver.ResolvedField {
+	if !isMultipart(contentType) || !slices.ContainsFunc(fields, isBase64Field) {
+		return nil
+	}
+
+	parts := make([]*resolver.ResolvedField, len(fields))
+	for i, field := range fields {
+		parts[i] = field
+		if !isBase64Field(field) {
+			continue
+		}
+		part := *field
+		part.Format = "binary"
+		if field.Type != nil {
+			desc := *field.Type
+			desc.Format = "binary"
+			part.Type = &desc
+		}
+		parts[i] = &part
+	}
+	return parts
+}
+
+// isBase64Field reports whether a field is a base64 string, such as []byte
+func isBase64Field(field *resolver.ResolvedField) bool {
+	return field.OpenAPIType == "string" && field.Format == "byte"
+}
+
+// addPartContentMediaType describes the contents of a file part with its @contentType
+// (e.g. contentMediaType: image/png) in OpenAPI 3.1+, for single files and arrays of files
+func (g *Generator) addPartContentMediaType(schema *base.Schema, field *resolver.ResolvedField) {
+	if !g.schemaBuilder.Is31Plus() || field.Encoding == nil || len(field.Encoding.ContentTypes) != 1 {
+		return
+	}
+
+	if schema.Items != nil && schema.Items.A != nil && !schema.Items.A.IsReference() {
+		if items, _ := schema.Items.A.BuildSchema(); items != nil {
+			schema = items
+		}
+	}
+	if schema.ContentMediaType != "" {
+		schema.ContentMediaType = field.Encoding.ContentTypes[0]
+	}
+}
+
+// bodyFields returns the top-level fields of a body referencing a struct @schema, nil otherwise
+func bodyFields(body *resolver.ResolvedBody, schemas map[string]*resolver.ResolvedSchema) []*resolver.ResolvedField {
+	if body == nil || body.Bind != nil || body.Type == nil || body.Type.Ref == "" {
+		return nil
+	}
+	if schema, ok := schemas[body.Type.Ref]; ok {
+		return schema.Fields
+	}
+	return nil
+}
+
+// generateHeaders generates the headers of a response or multipart part from @header structs
+func (g *Generator) generateHeaders(params []*resolver.ResolvedParameter) *orderedmap.Map[string, *v3.Header] {
+	headers := orderedmap.New[string, *v3.Header]()
+	for _, headerParam := range params {
+		for _, field := range headerParam.Fields {
+			headerSchema := g.schemaBuilder.NewSchema()
+			g.schemaBuilder.SetType(headerSchema, field.OpenAPIType)
+			if field.Format != "" {
+				headerSchema.Format = field.Format
+			}
+
+			header := &v3.Header{
+				Schema:      base.CreateSchemaProxy(headerSchema),
+				Description: field.Description,
+			}
+			if field.Example != "" {
+				header.Example = &yaml.Node{Kind: yaml.ScalarNode, Value: field.Example}
+			}
+			headers.Set(field.Name, header)
+		}
+	}
+	return headers
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -15,6 +16,7 @@ import (
 // Generator generates OpenAPI specifications using libopenapi's v3high models
 type Generator struct {
 	version       string // "3.0", "3.1", "3.2"
+	order         Order
 	schemaBuilder *SchemaBuilder
 }
 
@@ -30,6 +32,7 @@ const (
 func NewGenerator(version string) *Generator {
 	return &Generator{
 		version:       version,
+		order:         OrderSource,
 		schemaBuilder: NewSchemaBuilder(version),
 	}
 }
@@ -39,6 +42,7 @@ func (g *Generator) Generate(pkg *resolver.ResolvedPackage) (*v3.Document, error
 	doc := &v3.Document{
 		Version: g.getOpenAPIVersion(),
 		Info:    g.generateInfo(pkg.API),
+		Self:    pkg.API.Self,
 	}
 
 	if len(pkg.API.Servers) > 0 {
@@ -49,7 +53,7 @@ func (g *Generator) Generate(pkg *resolver.ResolvedPackage) (*v3.Document, error
 		doc.Tags = g.generateTags(pkg.API.Tags)
 	}
 
-	doc.Paths = g.generatePaths(pkg.Endpoints, pkg.Parameters, pkg.Schemas)
+	doc.Paths = g.generatePaths(g.sortEndpoints(pkg.Endpoints, pkg.API.Tags), pkg.Parameters, pkg.Schemas)
 	doc.Components = g.generateComponents(pkg)
 
 	if len(pkg.API.Security) > 0 {
@@ -137,6 +141,8 @@ func (g *Generator) generateTags(tags []*resolver.Tag) []*base.Tag {
 		result[i] = &base.Tag{
 			Name:        tag.Name,
 			Description: tag.Description,
+			Parent:      tag.Parent,
+			Kind:        tag.Kind,
 		}
 	}
 	return result
@@ -161,7 +167,8 @@ func (g *Generator) generateComponents(pkg *resolver.ResolvedPackage) *v3.Compon
 func (g *Generator) generateSchemas(schemas map[string]*resolver.ResolvedSchema) *orderedmap.Map[string, *base.SchemaProxy] {
 	result := orderedmap.New[string, *base.SchemaProxy]()
 
-	for name, schema := range schemas {
+	for _, name := range g.sortedSchemaNames(schemas) {
+		schema := schemas[name]
 		// Skip generic schemas - they are templates, not concrete types
 		if schema.IsGeneric {
 			continue
@@ -175,6 +182,10 @@ func (g *Generator) generateSchemas(schemas map[string]*resolver.ResolvedSchema)
 
 // generateSchema generates a single schema
 func (g *Generator) generateSchema(schema *resolver.ResolvedSchema, allSchemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
+	if schema.Value != nil {
+		return g.generateValueSchema(schema, allSchemas)
+	}
+
 	s := g.schemaBuilder.NewSchema()
 	g.schemaBuilder.SetType(s, "object")
 
@@ -182,11 +193,17 @@ func (g *Generator) generateSchema(schema *resolver.ResolvedSchema, allSchemas m
 		s.Description = schema.Description
 	}
 
-	if len(schema.Fields) > 0 {
+	fields := schema.Fields
+	var refs []string
+	if schema.AllOf {
+		refs, fields = embeddedSchemaRefs(schema.Fields, allSchemas)
+	}
+
+	if len(fields) > 0 {
 		props := orderedmap.New[string, *base.SchemaProxy]()
 		var required []string
 
-		for _, field := range schema.Fields {
+		for _, field := range fields {
 			props.Set(field.Name, g.generateFieldSchemaWithRefs(field, allSchemas))
 			if field.Required {
 				required = append(required, field.Name)
@@ -199,14 +216,103 @@ func (g *Generator) generateSchema(schema *resolver.ResolvedSchema, allSchemas m
 		}
 	}
 
+	// Undeclared members collected by an inlined map or unknown field
+	if schema.AdditionalProperties != nil {
+		s.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{A: g.generateTypeSchema(schema.AdditionalProperties, allSchemas)}
+	}
+
+	// Compose embedded schemas: allOf: [$ref, ..., {own properties}]
+	if len(refs) > 0 {
+		composed := g.schemaBuilder.NewSchema()
+		composed.Description = s.Description
+		s.Description = ""
+
+		for _, ref := range refs {
+			composed.AllOf = append(composed.AllOf, base.CreateSchemaProxyRef(fmt.Sprintf("#/components/schemas/%s", ref)))
+		}
+		if len(fields) > 0 || s.AdditionalProperties != nil {
+			composed.AllOf = append(composed.AllOf, base.CreateSchemaProxy(s))
+		}
+		s = composed
+	}
+
 	if schema.Deprecated {
 		t := true
 		s.Deprecated = &t
 	}
+	s.XML = g.generateXML(schema.XML)
 
 	return base.CreateSchemaProxy(s)
 }
 
+// generateValueSchema generates the schema of a named non-struct type (e.g. type Currency string)
+func (g *Generator) generateValueSchema(schema *resolver.ResolvedSchema, allSchemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
+	proxy := g.generateFieldSchemaWithRefs(schema.Value, allSchemas)
+	s, err := proxy.BuildSchema()
+	if err != nil || s == nil {
+		return proxy
+	}
+
+	if schema.Description != "" {
+		s.Description = schema.Description
+	}
+	if schema.Deprecated {
+		t := true
+		s.Deprecated = &t
+	}
+
+	return base.CreateSchemaProxy(s)
+}
+
+// embeddedSchemaRefs splits fields promoted from embedded @schema types out of a schema's fields
+// An embedded schema is referenced only when all of its fields were promoted; if the embedding
+// struct shadows any of them, its fields stay inline so the composed schema matches encoding/json.
+// Returns the component names to reference and the remaining fields.
+func embeddedSchemaRefs(fields []*resolver.ResolvedField, schemas map[string]*resolver.ResolvedSchema) ([]string, []*resolver.ResolvedField) {
+	promoted := make(map[string]map[string]bool)
+	var order []string
+	for _, field := range fields {
+		if field.EmbeddedFrom == "" {
+			continue
+		}
+		if _, ok := promoted[field.EmbeddedFrom]; !ok {
+			promoted[field.EmbeddedFrom] = make(map[string]bool)
+			order = append(order, field.EmbeddedFrom)
+		}
+		promoted[field.EmbeddedFrom][field.Name] = true
+	}
+
+	var refs []string
+	referenced := make(map[string]bool)
+	for _, embedded := range order {
+		refName, ok := schemaRefName(embedded, schemas)
+		if !ok {
+			continue
+		}
+
+		embeddedSchema := schemas[refName]
+		complete := len(embeddedSchema.Fields) == len(promoted[embedded])
+		for _, f := range embeddedSchema.Fields {
+			if !promoted[embedded][f.Name] {
+				complete = false
+			}
+		}
+		if complete {
+			refs = append(refs, refName)
+			referenced[embedded] = true
+		}
+	}
+
+	remaining := make([]*resolver.ResolvedField, 0, len(fields))
+	for _, field := range fields {
+		if !referenced[field.EmbeddedFrom] {
+			remaining = append(remaining, field)
+		}
+	}
+
+	return refs, remaining
+}
+
 // generateFieldSchemaWithRefs generates a schema for a field, using $ref for schema types
 func (g *Generator) generateFieldSchemaWithRefs(field *resolver.ResolvedField, schemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
 	// Handle anonymous structs - inline their fields
@@ -214,89 +320,33 @@ func (g *Generator) generateFieldSchemaWithRefs(field *resolver.ResolvedField, s
 		return g.buildInlineObjectSchema(field.InlineFields, schemas)
 	}
 
-	// Handle arrays of anonymous structs
-	if len(field.ItemsInlineFields) > 0 {
-		schema := g.schemaBuilder.NewSchema()
-		g.schemaBuilder.SetType(schema, "array")
-		schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
-			A: g.buildInlineObjectSchema(field.ItemsInlineFields, schemas),
-		}
+	// Handle arrays and maps of any depth ([][]string, map[string][]User, []map[string]*Item)
+	if isContainer(field.Type) {
+		schema, _ := g.generateTypeSchema(field.Type, schemas).BuildSchema()
 		g.addFieldConstraints(schema, field)
 		return base.CreateSchemaProxy(schema)
 	}
 
-	// Handle maps of anonymous structs
-	if len(field.MapValueInlineFields) > 0 {
-		schema := g.schemaBuilder.NewSchema()
-		g.schemaBuilder.SetType(schema, "object")
-		schema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{
-			A: g.buildInlineObjectSchema(field.MapValueInlineFields, schemas),
-		}
+	// Handle polymorphic values (oneOf/anyOf of schema references)
+	if field.Composition != nil {
+		schema := g.generateCompositionSchema(field.Composition)
 		g.addFieldConstraints(schema, field)
 		return base.CreateSchemaProxy(schema)
 	}
 
 	goType := field.GoType
 
-	// Handle arrays: []User or []string
-	if strings.HasPrefix(goType, "[]") {
-		elemType := strings.TrimPrefix(goType, "[]")
-		schema := g.schemaBuilder.NewSchema()
-		g.schemaBuilder.SetType(schema, "array")
-
-		if isSchemaReference(elemType, schemas) {
-			schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
-				A: base.CreateSchemaProxyRef(fmt.Sprintf("#/components/schemas/%s", extractTypeName(elemType))),
-			}
-		} else if isPrimitive(extractTypeName(elemType)) {
-			itemSchema := g.schemaBuilder.NewSchema()
-			g.schemaBuilder.SetType(itemSchema, goTypeToPrimitive(extractTypeName(elemType)))
-			schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
-				A: base.CreateSchemaProxy(itemSchema),
-			}
-		} else {
-			itemSchema := g.schemaBuilder.NewSchema()
-			g.schemaBuilder.SetType(itemSchema, field.ItemsType)
-			schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
-				A: base.CreateSchemaProxy(itemSchema),
-			}
-		}
-		g.addFieldConstraints(schema, field)
-		return base.CreateSchemaProxy(schema)
-	}
-
-	// Handle maps: map[string]User
-	if strings.HasPrefix(goType, "map[") {
-		if idx := strings.LastIndex(goType, "]"); idx > 0 && idx < len(goType)-1 {
-			valueType := goType[idx+1:]
+	// Handle schema references: User (named type that is a schema)
+	if refName, ok := schemaRefName(goType, schemas); ok {
+		ref := base.CreateSchemaProxyRef(fmt.Sprintf("#/components/schemas/%s", refName))
+		if xml := g.generateXML(field.XML); xml != nil {
+			// A $ref cannot carry an xml object in OpenAPI 3.0, so the reference is wrapped
 			schema := g.schemaBuilder.NewSchema()
-			g.schemaBuilder.SetType(schema, "object")
-
-			if isSchemaReference(valueType, schemas) {
-				schema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{
-					A: base.CreateSchemaProxyRef(fmt.Sprintf("#/components/schemas/%s", extractTypeName(valueType))),
-				}
-			} else if isPrimitive(extractTypeName(valueType)) {
-				propSchema := g.schemaBuilder.NewSchema()
-				g.schemaBuilder.SetType(propSchema, goTypeToPrimitive(extractTypeName(valueType)))
-				schema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{
-					A: base.CreateSchemaProxy(propSchema),
-				}
-			} else {
-				propSchema := g.schemaBuilder.NewSchema()
-				g.schemaBuilder.SetType(propSchema, "string")
-				schema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{
-					A: base.CreateSchemaProxy(propSchema),
-				}
-			}
-			g.addFieldConstraints(schema, field)
+			schema.AllOf = []*base.SchemaProxy{ref}
+			schema.XML = xml
 			return base.CreateSchemaProxy(schema)
 		}
-	}
-
-	// Handle schema references: User (named type that is a schema)
-	if isSchemaReference(goType, schemas) {
-		return base.CreateSchemaProxyRef(fmt.Sprintf("#/components/schemas/%s", extractTypeName(goType)))
+		return ref
 	}
 
 	// Handle any value (empty schema)
@@ -306,18 +356,16 @@ func (g *Generator) generateFieldSchemaWithRefs(field *resolver.ResolvedField, s
 		return base.CreateSchemaProxy(schema)
 	}
 
+	// Handle types mapped to a full schema
+	if field.MappedSchema != nil {
+		schema := g.schemaFromMap(field.MappedSchema)
+		g.addFieldConstraints(schema, field)
+		return base.CreateSchemaProxy(schema)
+	}
+
 	// Handle primitives and other types
 	schema := g.schemaBuilder.NewSchema()
-	if field.IsArray {
-		g.schemaBuilder.SetType(schema, "array")
-		itemSchema := g.schemaBuilder.NewSchema()
-		g.schemaBuilder.SetType(itemSchema, field.ItemsType)
-		schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
-			A: base.CreateSchemaProxy(itemSchema),
-		}
-	} else {
-		g.schemaBuilder.SetType(schema, field.OpenAPIType)
-	}
+	g.schemaBuilder.SetType(schema, field.OpenAPIType)
 
 	g.addFieldConstraints(schema, field)
 	return base.CreateSchemaProxy(schema)
@@ -352,20 +400,19 @@ func (g *Generator) addFieldConstraints(schema *base.Schema, field *resolver.Res
 		schema.Description = field.Description
 	}
 	if field.Format != "" {
-		schema.Format = field.Format
+		g.schemaBuilder.SetFormat(schema, field.Format)
 	}
 	if len(field.Enum) > 0 {
-		enumValues := convertEnumToYAMLNodes(field.Enum, field.OpenAPIType)
 		if field.IsArray {
 			// For arrays, enum goes inside items
 			if schema.Items != nil && schema.Items.A != nil {
 				itemSchema, _ := schema.Items.A.BuildSchema()
 				if itemSchema != nil {
-					itemSchema.Enum = enumValues
+					setFieldEnum(itemSchema, field, field.ItemsType)
 				}
 			}
 		} else {
-			schema.Enum = enumValues
+			setFieldEnum(schema, field, field.OpenAPIType)
 		}
 	}
 	if field.Example != "" {
@@ -402,12 +449,45 @@ func (g *Generator) addFieldConstraints(schema *base.Schema, field *resolver.Res
 	if field.Maximum != nil {
 		schema.Maximum = field.Maximum
 	}
-	if field.Nullable {
+	// Container schemas are built from the field's descriptor, which already carries its nullability
+	if field.Nullable && !isContainer(field.Type) {
 		g.schemaBuilder.SetNullable(schema, true)
 	}
 	if field.Deprecated {
 		schema.Deprecated = &field.Deprecated
 	}
+	g.addFieldXML(schema, field)
+	g.addPartContentMediaType(schema, field)
+}
+
+// setFieldEnum sets the enum of a field on a schema, along with the x-enum-varnames and
+// x-enum-descriptions extensions when the values were derived from Go constants
+func setFieldEnum(schema *base.Schema, field *resolver.ResolvedField, openAPIType string) {
+	schema.Enum = convertEnumToYAMLNodes(field.Enum, openAPIType)
+
+	if len(field.EnumVarNames) == 0 {
+		return
+	}
+	if schema.Extensions == nil {
+		schema.Extensions = orderedmap.New[string, *yaml.Node]()
+	}
+	schema.Extensions.Set("x-enum-varnames", stringSequenceNode(field.EnumVarNames))
+
+	for _, description := range field.EnumDescriptions {
+		if description != "" {
+			schema.Extensions.Set("x-enum-descriptions", stringSequenceNode(field.EnumDescriptions))
+			break
+		}
+	}
+}
+
+// stringSequenceNode converts strings to a yaml sequence node
+func stringSequenceNode(values []string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, v := range values {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v})
+	}
+	return node
 }
 
 // convertEnumToYAMLNodes converts enum values to yaml.Node slice
@@ -442,40 +522,23 @@ func (g *Generator) generateFieldSchema(field *resolver.ResolvedField) *base.Sch
 		return g.buildInlineObjectSchemaSimple(field.InlineFields)
 	}
 
-	// Handle arrays of anonymous structs
-	if len(field.ItemsInlineFields) > 0 {
-		schema := g.schemaBuilder.NewSchema()
-		g.schemaBuilder.SetType(schema, "array")
-		schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
-			A: g.buildInlineObjectSchemaSimple(field.ItemsInlineFields),
-		}
+	// Handle arrays and maps of any depth
+	if isContainer(field.Type) {
+		schema, _ := g.generateTypeSchema(field.Type, nil).BuildSchema()
 		g.addFieldConstraints(schema, field)
 		return base.CreateSchemaProxy(schema)
 	}
 
-	// Handle maps of anonymous structs
-	if len(field.MapValueInlineFields) > 0 {
-		schema := g.schemaBuilder.NewSchema()
-		g.schemaBuilder.SetType(schema, "object")
-		schema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{
-			A: g.buildInlineObjectSchemaSimple(field.MapValueInlineFields),
-		}
+	// Handle types mapped to a full schema
+	if field.MappedSchema != nil {
+		schema := g.schemaFromMap(field.MappedSchema)
 		g.addFieldConstraints(schema, field)
 		return base.CreateSchemaProxy(schema)
 	}
 
-	// Handle arrays
+	// Handle primitives and other types
 	schema := g.schemaBuilder.NewSchema()
-	if field.IsArray {
-		g.schemaBuilder.SetType(schema, "array")
-		itemSchema := g.schemaBuilder.NewSchema()
-		g.schemaBuilder.SetType(itemSchema, field.ItemsType)
-		schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
-			A: base.CreateSchemaProxy(itemSchema),
-		}
-	} else {
-		g.schemaBuilder.SetType(schema, field.OpenAPIType)
-	}
+	g.schemaBuilder.SetType(schema, field.OpenAPIType)
 
 	g.addFieldConstraints(schema, field)
 	return base.CreateSchemaProxy(schema)
@@ -515,7 +578,7 @@ func (g *Generator) generateParameterFieldSchema(field *resolver.ResolvedField)
 		g.schemaBuilder.SetType(itemSchema, field.ItemsType)
 		// Add enum to items if present
 		if len(field.Enum) > 0 {
-			itemSchema.Enum = convertEnumToYAMLNodes(field.Enum, field.ItemsType)
+			setFieldEnum(itemSchema, field, field.ItemsType)
 		}
 		schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
 			A: base.CreateSchemaProxy(itemSchema),
@@ -524,7 +587,7 @@ func (g *Generator) generateParameterFieldSchema(field *resolver.ResolvedField)
 		g.schemaBuilder.SetType(schema, field.OpenAPIType)
 		// Add enum directly
 		if len(field.Enum) > 0 {
-			schema.Enum = convertEnumToYAMLNodes(field.Enum, field.OpenAPIType)
+			setFieldEnum(schema, field, field.OpenAPIType)
 		}
 	}
 
@@ -579,7 +642,14 @@ func (g *Generator) generateParameterFieldSchema(field *resolver.ResolvedField)
 func (g *Generator) generateSecuritySchemes(schemes map[string]*resolver.SecurityScheme) *orderedmap.Map[string, *v3.SecurityScheme] {
 	result := orderedmap.New[string, *v3.SecurityScheme]()
 
-	for name, scheme := range schemes {
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		scheme := schemes[name]
 		ss := &v3.SecurityScheme{
 			Type:        scheme.Type,
 			Description: scheme.Description,
@@ -625,12 +695,13 @@ func (g *Generator) generatePaths(endpoints []*resolver.ResolvedEndpoint, parame
 		PathItems: orderedmap.New[string, *v3.PathItem](),
 	}
 
-	// Group endpoints by path
+	// Group endpoints by path, in the order of their first endpoint
 	pathMap := make(map[string]*v3.PathItem)
 
 	for _, endpoint := range endpoints {
 		if _, ok := pathMap[endpoint.Path]; !ok {
 			pathMap[endpoint.Path] = &v3.PathItem{}
+			paths.PathItems.Set(endpoint.Path, pathMap[endpoint.Path])
 		}
 
 		operation := g.generateOperation(endpoint, parameters, schemas)
@@ -653,14 +724,18 @@ func (g *Generator) generatePaths(endpoints []*resolver.ResolvedEndpoint, parame
 			pathMap[endpoint.Path].Options = operation
 		case "trace":
 			pathMap[endpoint.Path].Trace = operation
+		case "query":
+			pathMap[endpoint.Path].Query = operation
+		default:
+			// Other methods are additional operations (OpenAPI 3.2), keyed by the method as sent
+			pathItem := pathMap[endpoint.Path]
+			if pathItem.AdditionalOperations == nil {
+				pathItem.AdditionalOperations = orderedmap.New[string, *v3.Operation]()
+			}
+			pathItem.AdditionalOperations.Set(endpoint.Method, operation)
 		}
 	}
 
-	// Add to paths
-	for path, pathItem := range pathMap {
-		paths.PathItems.Set(path, pathItem)
-	}
-
 	return paths
 }
 
@@ -818,10 +893,28 @@ func (g *Generator) generateRequestBody(request *resolver.ResolvedRequestBody, s
 		return &v3.RequestBody{}
 	}
 
-	content := orderedmap.New[string, *v3.MediaType]()
-	content.Set(request.ContentType, &v3.MediaType{
-		Schema: g.generateBodySchema(request.Body, schemas),
-	})
+	mediaTypes := request.MediaTypes()
+	content := g.generateContent(mediaTypes, schemas)
+
+	// Encodings of the parts of multipart and form bodies
+	for _, mediaType := range mediaTypes {
+		if content == nil {
+			break
+		}
+		m := content.GetOrZero(mediaType.ContentType)
+		if m == nil {
+			continue
+		}
+		fields := bodyFields(mediaType.Body, schemas)
+		m.Encoding = g.generateEncoding(mediaType.ContentType, fields)
+
+		// The parts of []byte fields are binary, unlike the base64 strings of the shared component
+		if parts := multipartFields(mediaType.ContentType, fields); parts != nil {
+			schema := *schemas[mediaType.Body.Type.Ref]
+			schema.Fields = parts
+			m.Schema = g.generateSchema(&schema, schemas)
+		}
+	}
 
 	return &v3.RequestBody{
 		Content:  content,
@@ -829,14 +922,53 @@ func (g *Generator) generateRequestBody(request *resolver.ResolvedRequestBody, s
 	}
 }
 
+// generateContent generates the media types of a request body or response
+// Media types without a body schema are skipped; returns nil if none remain.
+func (g *Generator) generateContent(mediaTypes []*resolver.ResolvedContent, schemas map[string]*resolver.ResolvedSchema) *orderedmap.Map[string, *v3.MediaType] {
+	var content *orderedmap.Map[string, *v3.MediaType]
+	for _, mediaType := range mediaTypes {
+		// Server-Sent Events streams without a body still describe their events
+		hasBody := mediaType.Body != nil && mediaType.Body.Schema != ""
+		if mediaType.ContentType == "" || !hasBody && mediaType.Stream != "sse" {
+			continue
+		}
+		if content == nil {
+			content = orderedmap.New[string, *v3.MediaType]()
+		}
+		if mediaType.Stream != "" {
+			content.Set(mediaType.ContentType, g.generateStreamMediaType(mediaType, schemas))
+			continue
+		}
+		content.Set(mediaType.ContentType, &v3.MediaType{
+			Schema: g.generateMediaTypeSchema(mediaType, schemas),
+		})
+	}
+	return content
+}
+
+// generateInlineContent generates the media types of an inline body, which share one schema
+func generateInlineContent(inline *resolver.ResolvedInlineBody, schemaProxy *base.SchemaProxy) *orderedmap.Map[string, *v3.MediaType] {
+	contentTypes := inline.ContentTypes
+	if len(contentTypes) == 0 && inline.ContentType != "" {
+		contentTypes = []string{inline.ContentType}
+	}
+	if len(contentTypes) == 0 {
+		contentTypes = []string{"application/json"}
+	}
+
+	content := orderedmap.New[string, *v3.MediaType]()
+	for _, contentType := range contentTypes {
+		content.Set(contentType, &v3.MediaType{Schema: schemaProxy})
+	}
+	return content
+}
+
 // generateInlineRequestBody generates a request body from an inline struct
 func (g *Generator) generateInlineRequestBody(inline *resolver.ResolvedInlineBody, schemas map[string]*resolver.ResolvedSchema) *v3.RequestBody {
 	if inline == nil || len(inline.Fields) == 0 {
 		return &v3.RequestBody{}
 	}
 
-	content := orderedmap.New[string, *v3.MediaType]()
-
 	var schemaProxy *base.SchemaProxy
 	if inline.Bind != nil {
 		schemaProxy = g.generateInlineWrappedSchema(inline, schemas)
@@ -844,11 +976,23 @@ func (g *Generator) generateInlineRequestBody(inline *resolver.ResolvedInlineBod
 		schemaProxy = g.generateInlineSchema(inline.Fields)
 	}
 
-	contentType := inline.ContentType
-	if contentType == "" {
-		contentType = "application/json"
+	content := generateInlineContent(inline, schemaProxy)
+
+	// Encodings of the parts of multipart and form bodies (fields of wrapped bodies are not parts)
+	if inline.Bind == nil {
+		var partSchema *base.SchemaProxy
+		for contentType, mediaType := range content.FromOldest() {
+			mediaType.Encoding = g.generateEncoding(contentType, inline.Fields)
+
+			// The parts of []byte fields are binary, unlike the base64 strings of other media types
+			if parts := multipartFields(contentType, inline.Fields); parts != nil {
+				if partSchema == nil {
+					partSchema = g.generateInlineSchema(parts)
+				}
+				mediaType.Schema = partSchema
+			}
+		}
 	}
-	content.Set(contentType, &v3.MediaType{Schema: schemaProxy})
 
 	required := true
 	return &v3.RequestBody{
@@ -864,45 +1008,25 @@ func (g *Generator) generateResponsesWithInline(responses map[string]*resolver.R
 	}
 
 	// Add explicit responses
-	for statusCode, response := range responses {
+	for _, statusCode := range sortedStatusCodes(responses) {
+		response := responses[statusCode]
 		resp := &v3.Response{
 			Description: response.Description,
 		}
 
 		// Add response headers if present
 		if len(response.Headers) > 0 {
-			headers := orderedmap.New[string, *v3.Header]()
-			for _, headerParam := range response.Headers {
-				for _, field := range headerParam.Fields {
-					headerSchema := g.schemaBuilder.NewSchema()
-					g.schemaBuilder.SetType(headerSchema, field.OpenAPIType)
-					if field.Format != "" {
-						headerSchema.Format = field.Format
-					}
-
-					header := &v3.Header{
-						Schema:      base.CreateSchemaProxy(headerSchema),
-						Description: field.Description,
-					}
-					headers.Set(field.Name, header)
-				}
-			}
-			resp.Headers = headers
+			resp.Headers = g.generateHeaders(response.Headers)
 		}
 
-		if response.Body != nil && response.Body.Schema != "" && response.ContentType != "" {
-			content := orderedmap.New[string, *v3.MediaType]()
-			content.Set(response.ContentType, &v3.MediaType{
-				Schema: g.generateBodySchema(response.Body, schemas),
-			})
-			resp.Content = content
-		}
+		resp.Content = g.generateContent(response.MediaTypes(), schemas)
 
 		result.Codes.Set(statusCode, resp)
 	}
 
 	// Add inline responses (don't override explicit ones)
-	for statusCode, inline := range inlineResponses {
+	for _, statusCode := range sortedStatusCodes(inlineResponses) {
+		inline := inlineResponses[statusCode]
 		if result.Codes.GetOrZero(statusCode) != nil {
 			continue // Skip if explicit response already exists
 		}
@@ -918,41 +1042,17 @@ func (g *Generator) generateResponsesWithInline(responses map[string]*resolver.R
 
 		// Add inline response headers if present
 		if len(inline.Headers) > 0 {
-			headers := orderedmap.New[string, *v3.Header]()
-			for _, headerParam := range inline.Headers {
-				for _, field := range headerParam.Fields {
-					headerSchema := g.schemaBuilder.NewSchema()
-					g.schemaBuilder.SetType(headerSchema, field.OpenAPIType)
-					if field.Format != "" {
-						headerSchema.Format = field.Format
-					}
-
-					header := &v3.Header{
-						Schema:      base.CreateSchemaProxy(headerSchema),
-						Description: field.Description,
-					}
-					headers.Set(field.Name, header)
-				}
-			}
-			resp.Headers = headers
+			resp.Headers = g.generateHeaders(inline.Headers)
 		}
 
 		if len(inline.Fields) > 0 {
-			content := orderedmap.New[string, *v3.MediaType]()
-
 			var schemaProxy *base.SchemaProxy
 			if inline.Bind != nil {
 				schemaProxy = g.generateInlineWrappedSchema(inline, schemas)
 			} else {
 				schemaProxy = g.generateInlineSchema(inline.Fields)
 			}
-
-			contentType := inline.ContentType
-			if contentType == "" {
-				contentType = "application/json"
-			}
-			content.Set(contentType, &v3.MediaType{Schema: schemaProxy})
-			resp.Content = content
+			resp.Content = generateInlineContent(inline, schemaProxy)
 		}
 
 		result.Codes.Set(statusCode, resp)
@@ -966,6 +1066,14 @@ func (g *Generator) generateBodySchema(body *resolver.ResolvedBody, schemas map[
 	if body.Bind != nil {
 		return g.generateWrappedSchema(body, schemas)
 	}
+	return g.generateBodyTypeSchema(body, schemas)
+}
+
+// generateBodyTypeSchema generates the schema of a body type, without its wrapper
+func (g *Generator) generateBodyTypeSchema(body *resolver.ResolvedBody, schemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
+	if body.Type != nil {
+		return g.generateTypeSchema(body.Type, schemas)
+	}
 	return g.generateSchemaRef(body.Schema, body.IsArray, body.IsMap, body.ElementType)
 }
 
@@ -973,7 +1081,7 @@ func (g *Generator) generateBodySchema(body *resolver.ResolvedBody, schemas map[
 func (g *Generator) generateWrappedSchema(body *resolver.ResolvedBody, schemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
 	wrapperSchema := body.Bind.WrapperSchema
 	if wrapperSchema == nil {
-		return g.generateSchemaRef(body.Schema, body.IsArray, body.IsMap, body.ElementType)
+		return g.generateBodyTypeSchema(body, schemas)
 	}
 
 	schema := g.schemaBuilder.NewSchema()
@@ -988,7 +1096,7 @@ func (g *Generator) generateWrappedSchema(body *resolver.ResolvedBody, schemas m
 
 	for _, field := range wrapperSchema.Fields {
 		if field.GoName == body.Bind.Field {
-			props.Set(field.Name, g.generateSchemaRef(body.Schema, body.IsArray, body.IsMap, body.ElementType))
+			props.Set(field.Name, g.generateBodyTypeSchema(body, schemas))
 		} else {
 			props.Set(field.Name, g.generateFieldSchema(field))
 		}
@@ -1123,13 +1231,29 @@ func extractTypeName(goType string) string {
 	return goType
 }
 
-// isSchemaReference checks if a Go type corresponds to a known schema
-func isSchemaReference(goType string, schemas map[string]*resolver.ResolvedSchema) bool {
+// schemaRefName returns the component name of the schema a Go type refers to
+// Go type strings are package-qualified (e.g. "*github.com/acme/models.User"), so the
+// schema declared in that package is preferred over one that only shares the type name
+func schemaRefName(goType string, schemas map[string]*resolver.ResolvedSchema) (string, bool) {
+	goType = strings.TrimLeft(goType, "*")
 	typeName := extractTypeName(goType)
-	if schema, ok := schemas[typeName]; ok {
-		return !schema.IsGeneric
+	pkgPath := strings.TrimSuffix(goType, "."+typeName)
+	if pkgPath == goType {
+		pkgPath = ""
+	}
+
+	if pkgPath != "" {
+		for name, schema := range schemas {
+			if schema.PkgPath == pkgPath && schema.GoTypeName == typeName {
+				return name, !schema.IsGeneric
+			}
+		}
+	}
+
+	if schema, ok := schemas[typeName]; ok && (schema.PkgPath == "" || pkgPath == "" || schema.PkgPath == pkgPath) {
+		return typeName, !schema.IsGeneric
 	}
-	return false
+	return "", false
 }
 
 // isPrimitive checks if a type is a Go primitive
@@ -2,9 +2,12 @@ package generator
 
 import (
 	"encoding/json"
+	"go/token"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/wontaeyang/go-specgen/pkg/resolver"
 	"go.yaml.in/yaml/v4"
 )
@@ -189,6 +192,236 @@ func TestGenerator_GenerateSchemas(t *testing.T) {
 	}
 }
 
+func TestGenerator_GenerateSchema_AllOf(t *testing.T) {
+	base := &resolver.ResolvedSchema{
+		Name:       "Base",
+		GoTypeName: "Base",
+		PkgPath:    "example.com/models",
+		Fields: []*resolver.ResolvedField{
+			{Name: "id", GoName: "ID", OpenAPIType: "string", Required: true},
+			{Name: "createdAt", GoName: "CreatedAt", OpenAPIType: "string", Required: true},
+		},
+	}
+	promoted := func(name string) *resolver.ResolvedField {
+		return &resolver.ResolvedField{Name: name, OpenAPIType: "string", Required: true, EmbeddedFrom: "example.com/models.Base"}
+	}
+
+	tests := []struct {
+		name      string
+		schema    *resolver.ResolvedSchema
+		wantAllOf bool
+		wantProps []string
+	}{
+		{
+			name: "embedded schema is referenced",
+			schema: &resolver.ResolvedSchema{
+				Name:   "Admin",
+				AllOf:  true,
+				Fields: []*resolver.ResolvedField{promoted("id"), promoted("createdAt"), {Name: "level", OpenAPIType: "integer"}},
+			},
+			wantAllOf: true,
+			wantProps: []string{"level"},
+		},
+		{
+			name: "shadowed embedded schema is flattened",
+			schema: &resolver.ResolvedSchema{
+				Name:   "Override",
+				AllOf:  true,
+				Fields: []*resolver.ResolvedField{promoted("createdAt"), {Name: "id", OpenAPIType: "integer"}},
+			},
+			wantProps: []string{"createdAt", "id"},
+		},
+		{
+			name: "flattened without @allOf",
+			schema: &resolver.ResolvedSchema{
+				Name:   "User",
+				Fields: []*resolver.ResolvedField{promoted("id"), promoted("createdAt"), {Name: "name", OpenAPIType: "string"}},
+			},
+			wantProps: []string{"id", "createdAt", "name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemas := map[string]*resolver.ResolvedSchema{"Base": base, tt.schema.Name: tt.schema}
+
+			gen := NewGenerator("3.0")
+			s, err := gen.generateSchema(tt.schema, schemas).BuildSchema()
+			if err != nil {
+				t.Fatalf("BuildSchema() error = %v", err)
+			}
+
+			own := s
+			if tt.wantAllOf {
+				if len(s.AllOf) != 2 {
+					t.Fatalf("allOf has %d entries, want 2", len(s.AllOf))
+				}
+				if ref := s.AllOf[0].GetReference(); ref != "#/components/schemas/Base" {
+					t.Errorf("allOf[0] = %q, want $ref to Base", ref)
+				}
+				own, _ = s.AllOf[1].BuildSchema()
+			} else if len(s.AllOf) > 0 {
+				t.Fatalf("unexpected allOf with %d entries", len(s.AllOf))
+			}
+
+			var got []string
+			for name := range own.Properties.KeysFromOldest() {
+				got = append(got, name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantProps, ",") {
+				t.Errorf("properties = %v, want %v", got, tt.wantProps)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateSchema_AdditionalProperties(t *testing.T) {
+	gen := NewGenerator("3.0")
+
+	labels := &resolver.ResolvedSchema{
+		Name:                 "Labels",
+		Fields:               []*resolver.ResolvedField{{Name: "name", GoType: "string", OpenAPIType: "string"}},
+		AdditionalProperties: &resolver.TypeDescriptor{GoType: "string", OpenAPIType: "string"},
+	}
+	extra := &resolver.ResolvedSchema{
+		Name:                 "Extra",
+		AdditionalProperties: &resolver.TypeDescriptor{GoType: "any", IsAnyValue: true},
+	}
+	schemas := map[string]*resolver.ResolvedSchema{"Labels": labels, "Extra": extra}
+
+	s, _ := gen.generateSchema(labels, schemas).BuildSchema()
+	if s.Properties == nil || s.Properties.GetOrZero("name") == nil {
+		t.Error("Labels should keep its declared properties")
+	}
+	if s.AdditionalProperties == nil || s.AdditionalProperties.A == nil {
+		t.Fatal("Labels additionalProperties not set")
+	}
+	if values, _ := s.AdditionalProperties.A.BuildSchema(); len(values.Type) == 0 || values.Type[0] != "string" {
+		t.Errorf("Labels additionalProperties type = %v, want string", values.Type)
+	}
+
+	s, _ = gen.generateSchema(extra, schemas).BuildSchema()
+	if s.AdditionalProperties == nil || s.AdditionalProperties.A == nil {
+		t.Fatal("Extra additionalProperties not set")
+	}
+	if values, _ := s.AdditionalProperties.A.BuildSchema(); len(values.Type) != 0 {
+		t.Errorf("Extra additionalProperties type = %v, want any value", values.Type)
+	}
+}
+
+func TestGenerator_GenerateSchema_Value(t *testing.T) {
+	currency := &resolver.ResolvedSchema{
+		Name:        "Currency",
+		GoTypeName:  "Currency",
+		PkgPath:     "example.com/money",
+		Description: "ISO 4217 currency code",
+		Value:       &resolver.ResolvedField{Name: "Currency", GoType: "string", OpenAPIType: "string", Enum: []string{"USD", "EUR"}},
+	}
+	currencies := &resolver.ResolvedSchema{
+		Name:       "Currencies",
+		GoTypeName: "Currencies",
+		PkgPath:    "example.com/money",
+		Value: &resolver.ResolvedField{
+			Name:        "Currencies",
+			GoType:      "[]example.com/money.Currency",
+			OpenAPIType: "array",
+			IsArray:     true,
+			ItemsType:   "string",
+			Type: &resolver.TypeDescriptor{
+				GoType: "[]example.com/money.Currency", OpenAPIType: "array",
+				Items: &resolver.TypeDescriptor{GoType: "example.com/money.Currency", OpenAPIType: "string"},
+			},
+		},
+	}
+	schemas := map[string]*resolver.ResolvedSchema{"Currency": currency, "Currencies": currencies}
+
+	gen := NewGenerator("3.0")
+
+	s, err := gen.generateSchema(currency, schemas).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+	if len(s.Type) == 0 || s.Type[0] != "string" {
+		t.Errorf("type = %v, want string", s.Type)
+	}
+	if s.Description != "ISO 4217 currency code" {
+		t.Errorf("description = %q", s.Description)
+	}
+	if len(s.Enum) != 2 {
+		t.Errorf("enum has %d values, want 2", len(s.Enum))
+	}
+	if s.Properties != nil {
+		t.Error("value schema should have no properties")
+	}
+
+	s, err = gen.generateSchema(currencies, schemas).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+	if len(s.Type) == 0 || s.Type[0] != "array" {
+		t.Errorf("type = %v, want array", s.Type)
+	}
+	if ref := s.Items.A.GetReference(); ref != "#/components/schemas/Currency" {
+		t.Errorf("items = %q, want $ref to Currency", ref)
+	}
+}
+
+func TestGenerator_GenerateFieldSchema_MappedSchema(t *testing.T) {
+	gen := NewGenerator("3.0")
+
+	field := &resolver.ResolvedField{
+		Name:        "price",
+		GoType:      "*example.com/types.Money",
+		OpenAPIType: "object",
+		Description: "Item price",
+		MappedSchema: map[string]any{
+			"type":     "object",
+			"required": []any{"amount"},
+			"properties": map[string]any{
+				"currency": map[string]any{"$ref": "#/components/schemas/Currency"},
+				"amount":   map[string]any{"type": "integer", "minimum": 0},
+			},
+			"x-go-type": "Money",
+		},
+	}
+
+	schema, err := gen.generateFieldSchemaWithRefs(field, map[string]*resolver.ResolvedSchema{}).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+
+	if len(schema.Type) == 0 || schema.Type[0] != "object" {
+		t.Errorf("type = %v, want object", schema.Type)
+	}
+	if schema.Description != "Item price" {
+		t.Errorf("description = %q, want field description", schema.Description)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "amount" {
+		t.Errorf("required = %v, want [amount]", schema.Required)
+	}
+
+	var props []string
+	for name := range schema.Properties.KeysFromOldest() {
+		props = append(props, name)
+	}
+	if strings.Join(props, ",") != "amount,currency" {
+		t.Errorf("properties = %v, want [amount currency]", props)
+	}
+
+	amountProxy, _ := schema.Properties.Get("amount")
+	amount, _ := amountProxy.BuildSchema()
+	if amount.Minimum == nil || *amount.Minimum != 0 {
+		t.Errorf("amount minimum = %v, want 0", amount.Minimum)
+	}
+	currency, _ := schema.Properties.Get("currency")
+	if ref := currency.GetReference(); ref != "#/components/schemas/Currency" {
+		t.Errorf("currency = %q, want $ref to Currency", ref)
+	}
+	if _, ok := schema.Extensions.Get("x-go-type"); !ok {
+		t.Error("x-go-type extension not set")
+	}
+}
+
 func TestGenerator_RenderJSON(t *testing.T) {
 	pkg := &resolver.ResolvedPackage{
 		API: &resolver.ResolvedAPI{
@@ -341,6 +574,205 @@ func TestGenerator_GeneratePaths(t *testing.T) {
 	}
 }
 
+func TestGenerator_GeneratePaths_Version32(t *testing.T) {
+	responses := map[string]*resolver.ResolvedResponse{
+		"200": {StatusCode: "200", Description: "Success"},
+	}
+	endpoints := []*resolver.ResolvedEndpoint{
+		{Method: "QUERY", Path: "/users", OperationID: "searchUsers", Responses: responses},
+		{Method: "PURGE", Path: "/users", OperationID: "purgeUsers", Responses: responses},
+		{Method: "TRACE", Path: "/users", OperationID: "traceUsers", Responses: responses},
+	}
+
+	gen := NewGenerator("3.2")
+	paths := gen.generatePaths(endpoints, map[string]*resolver.ResolvedParameter{}, map[string]*resolver.ResolvedSchema{})
+
+	usersPath := paths.PathItems.GetOrZero("/users")
+	if usersPath == nil {
+		t.Fatal("/users path not found")
+	}
+	if usersPath.Query == nil || usersPath.Query.OperationId != "searchUsers" {
+		t.Errorf("QUERY operation = %+v, want searchUsers", usersPath.Query)
+	}
+	if usersPath.Trace == nil {
+		t.Error("TRACE operation not found")
+	}
+	if usersPath.AdditionalOperations == nil || usersPath.AdditionalOperations.Len() != 1 {
+		t.Fatalf("additionalOperations = %v, want PURGE", usersPath.AdditionalOperations)
+	}
+	if op := usersPath.AdditionalOperations.GetOrZero("PURGE"); op == nil || op.OperationId != "purgeUsers" {
+		t.Errorf("PURGE operation = %+v, want purgeUsers", op)
+	}
+}
+
+func TestGenerator_SortEndpoints(t *testing.T) {
+	at := func(file string, line int) token.Position {
+		return token.Position{Filename: file, Line: line, Column: 1}
+	}
+	endpoints := []*resolver.ResolvedEndpoint{
+		{Method: "GET", Path: "/orders", Tags: []string{"orders"}, Position: at("b.go", 10)},
+		{Method: "GET", Path: "/users", Tags: []string{"users"}, Position: at("a.go", 30)},
+		{Method: "GET", Path: "/health", Position: at("a.go", 5)},
+		{Method: "POST", Path: "/users", Tags: []string{"users"}, Position: at("a.go", 20)},
+		{Method: "GET", Path: "/audit", Tags: []string{"audit"}, Position: at("a.go", 40)},
+	}
+	tags := []*resolver.Tag{{Name: "users"}, {Name: "orders"}}
+
+	tests := []struct {
+		order Order
+		want  []string
+	}{
+		{order: OrderSource, want: []string{"GET /health", "POST /users", "GET /users", "GET /audit", "GET /orders"}},
+		{order: OrderAlpha, want: []string{"GET /audit", "GET /health", "GET /orders", "GET /users", "POST /users"}},
+		// Declared tags first, then undeclared tags, then untagged endpoints
+		{order: OrderTag, want: []string{"POST /users", "GET /users", "GET /orders", "GET /audit", "GET /health"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			gen := NewGenerator("3.0")
+			gen.SetOrder(tt.order)
+
+			var got []string
+			for _, endpoint := range gen.sortEndpoints(endpoints, tags) {
+				got = append(got, endpoint.Method+" "+endpoint.Path)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("sortEndpoints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_Order(t *testing.T) {
+	at := func(line int) token.Position {
+		return token.Position{Filename: "api.go", Line: line, Column: 1}
+	}
+	responses := map[string]*resolver.ResolvedResponse{
+		"default": {StatusCode: "default", Description: "Error"},
+		"404":     {StatusCode: "404", Description: "Not found"},
+		"2XX":     {StatusCode: "2XX", Description: "Success"},
+		"200":     {StatusCode: "200", Description: "OK"},
+	}
+	field := []*resolver.ResolvedField{{Name: "id", GoName: "ID", OpenAPIType: "string"}}
+	pkg := &resolver.ResolvedPackage{
+		API: &resolver.ResolvedAPI{
+			Title:   "Test API",
+			Version: "1.0.0",
+			SecuritySchemes: map[string]*resolver.SecurityScheme{
+				"bearer": {Name: "bearer", Type: "http", Scheme: "bearer"},
+				"apiKey": {Name: "apiKey", Type: "apiKey", In: "header", ParameterName: "X-API-Key"},
+			},
+		},
+		Schemas: map[string]*resolver.ResolvedSchema{
+			"User":    {Name: "User", Fields: field, Position: at(10)},
+			"Account": {Name: "Account", Fields: field, Position: at(20)},
+			"Zone":    {Name: "Zone", Fields: field, Position: at(5)},
+			"Page":    {Name: "Page", Fields: field},
+		},
+		Parameters: map[string]*resolver.ResolvedParameter{},
+		Endpoints: []*resolver.ResolvedEndpoint{
+			{Method: "GET", Path: "/users", Position: at(40), Responses: responses},
+			{Method: "GET", Path: "/accounts", Position: at(50), Responses: responses},
+		},
+	}
+
+	tests := []struct {
+		order       Order
+		wantPaths   string
+		wantSchemas string
+	}{
+		// Schemas without a source position come last
+		{order: OrderSource, wantPaths: "/users,/accounts", wantSchemas: "Zone,User,Account,Page"},
+		{order: OrderAlpha, wantPaths: "/accounts,/users", wantSchemas: "Account,Page,User,Zone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			gen := NewGenerator("3.0")
+			gen.SetOrder(tt.order)
+
+			var rendered []byte
+			for range 5 {
+				spec, err := gen.Generate(pkg)
+				if err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				data, err := gen.Render(spec, FormatYAML)
+				if err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+				if rendered != nil && string(data) != string(rendered) {
+					t.Fatal("Generate() output differs between runs")
+				}
+				rendered = data
+
+				var paths, schemas []string
+				for path := range spec.Paths.PathItems.KeysFromOldest() {
+					paths = append(paths, path)
+				}
+				for name := range spec.Components.Schemas.KeysFromOldest() {
+					schemas = append(schemas, name)
+				}
+				if got := strings.Join(paths, ","); got != tt.wantPaths {
+					t.Errorf("paths = %s, want %s", got, tt.wantPaths)
+				}
+				if got := strings.Join(schemas, ","); got != tt.wantSchemas {
+					t.Errorf("schemas = %s, want %s", got, tt.wantSchemas)
+				}
+
+				var codes []string
+				for code := range spec.Paths.PathItems.GetOrZero("/users").Get.Responses.Codes.KeysFromOldest() {
+					codes = append(codes, code)
+				}
+				if got := strings.Join(codes, ","); got != "200,2XX,404,default" {
+					t.Errorf("responses = %s, want 200,2XX,404,default", got)
+				}
+
+				var schemes []string
+				for name := range spec.Components.SecuritySchemes.KeysFromOldest() {
+					schemes = append(schemes, name)
+				}
+				if got := strings.Join(schemes, ","); got != "apiKey,bearer" {
+					t.Errorf("security schemes = %s, want apiKey,bearer", got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_Version32(t *testing.T) {
+	pkg := &resolver.ResolvedPackage{
+		API: &resolver.ResolvedAPI{
+			Title:   "Test API",
+			Version: "1.0.0",
+			Self:    "https://api.example.com/openapi.yaml",
+			Tags: []*resolver.Tag{
+				{Name: "account", Kind: "nav"},
+				{Name: "billing", Description: "Billing", Parent: "account", Kind: "nav"},
+			},
+		},
+		Schemas:    map[string]*resolver.ResolvedSchema{},
+		Parameters: map[string]*resolver.ResolvedParameter{},
+	}
+
+	gen := NewGenerator("3.2")
+	spec, err := gen.Generate(pkg)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if spec.Self != "https://api.example.com/openapi.yaml" {
+		t.Errorf("$self = %q, want https://api.example.com/openapi.yaml", spec.Self)
+	}
+	if len(spec.Tags) != 2 {
+		t.Fatalf("Expected 2 tags, got %d", len(spec.Tags))
+	}
+	if tag := spec.Tags[1]; tag.Parent != "account" || tag.Kind != "nav" || tag.Description != "Billing" {
+		t.Errorf("Tags[1] = %+v, want billing under account", tag)
+	}
+}
+
 func TestGenerator_GenerateOperation(t *testing.T) {
 	endpoint := &resolver.ResolvedEndpoint{
 		Method:      "GET",
@@ -720,6 +1152,38 @@ func TestIsPrimitive(t *testing.T) {
 	}
 }
 
+func TestSchemaRefName(t *testing.T) {
+	schemas := map[string]*resolver.ResolvedSchema{
+		"Address":       {Name: "Address", GoTypeName: "Address", PkgPath: "example.com/api/models"},
+		"models.User":   {Name: "models.User", GoTypeName: "User", PkgPath: "example.com/api/models"},
+		"handlers.User": {Name: "handlers.User", GoTypeName: "User", PkgPath: "example.com/api/handlers"},
+		"Page":          {Name: "Page", GoTypeName: "Page", IsGeneric: true},
+	}
+
+	tests := []struct {
+		goType string
+		want   string
+		wantOK bool
+	}{
+		{"example.com/api/models.User", "models.User", true},
+		{"*example.com/api/handlers.User", "handlers.User", true},
+		{"example.com/api/models.Address", "Address", true},
+		{"example.com/api/other.Address", "", false},
+		{"Address", "Address", true},
+		{"Page", "Page", false},
+		{"string", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			got, ok := schemaRefName(tt.goType, schemas)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("schemaRefName(%q) = (%q, %v), want (%q, %v)", tt.goType, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestGoTypeToPrimitive(t *testing.T) {
 	tests := []struct {
 		input string
@@ -832,6 +1296,91 @@ func TestGenerator_GenerateInlineWrappedSchema_NoBind(t *testing.T) {
 	}
 }
 
+func TestGenerator_GenerateContent_MultipleContentTypes(t *testing.T) {
+	gen := NewGenerator("3.0")
+
+	schemas := map[string]*resolver.ResolvedSchema{
+		"User": {
+			Name: "User",
+			Fields: []*resolver.ResolvedField{
+				{Name: "id", GoName: "ID", OpenAPIType: "string", Required: true},
+			},
+		},
+	}
+	users := &resolver.ResolvedBody{
+		Schema: "[]User",
+		Type:   &resolver.TypeDescriptor{OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "object", Ref: "User"}},
+	}
+	csv := &resolver.ResolvedBody{
+		Schema: "string",
+		Type:   &resolver.TypeDescriptor{OpenAPIType: "string"},
+	}
+
+	response := &resolver.ResolvedResponse{
+		StatusCode:  "200",
+		Description: "Users",
+		ContentType: "application/json",
+		Body:        users,
+		Content: []*resolver.ResolvedContent{
+			{ContentType: "application/json", Body: users},
+			{ContentType: "application/xml", Body: users},
+			{ContentType: "text/csv", Body: csv},
+			{ContentType: "text/html"}, // No schema, skipped
+		},
+	}
+	inline := &resolver.ResolvedInlineBody{
+		ContentType:  "application/json",
+		ContentTypes: []string{"application/json", "application/x-www-form-urlencoded"},
+		Fields: []*resolver.ResolvedField{
+			{Name: "name", GoName: "Name", OpenAPIType: "string", Required: true},
+		},
+	}
+
+	responses := gen.generateResponsesWithInline(
+		map[string]*resolver.ResolvedResponse{"200": response},
+		map[string]*resolver.ResolvedInlineBody{"201": inline},
+		schemas,
+	)
+
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{status: "200", want: "application/json application/xml text/csv"},
+		{status: "201", want: "application/json application/x-www-form-urlencoded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			content := responses.Codes.GetOrZero(tt.status).Content
+			if content == nil {
+				t.Fatal("content not found")
+			}
+			var contentTypes []string
+			for contentType := range content.KeysFromOldest() {
+				contentTypes = append(contentTypes, contentType)
+			}
+			if got := strings.Join(contentTypes, " "); got != tt.want {
+				t.Errorf("content types = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	schema, err := responses.Codes.GetOrZero("200").Content.GetOrZero("text/csv").Schema.BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+	if len(schema.Type) == 0 || schema.Type[0] != "string" {
+		t.Errorf("text/csv type = %v, want string", schema.Type)
+	}
+
+	// Request bodies without Content use ContentType and Body
+	request := gen.generateRequestBody(&resolver.ResolvedRequestBody{ContentType: "application/xml", Body: users, Required: true}, schemas)
+	if request.Content.Len() != 1 || request.Content.GetOrZero("application/xml") == nil {
+		t.Error("request body should have application/xml content only")
+	}
+}
+
 func TestConvertEnumValues(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -884,6 +1433,7 @@ func TestGenerator_GenerateFieldSchema_ArrayEnum(t *testing.T) {
 		OpenAPIType: "array",
 		IsArray:     true,
 		ItemsType:   "string",
+		Type:        &resolver.TypeDescriptor{OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "string"}},
 		Enum:        []string{"red", "green", "blue"},
 	}
 
@@ -950,24 +1500,103 @@ func TestGenerator_GenerateFieldSchema_IntegerEnum(t *testing.T) {
 	}
 }
 
-func TestGenerator_GenerateParameterFieldSchema_ArrayEnum(t *testing.T) {
+func TestGenerator_GenerateFieldSchema_EnumExtensions(t *testing.T) {
 	gen := NewGenerator("3.0")
 
-	field := &resolver.ResolvedField{
-		Name:        "status",
-		GoName:      "Status",
-		OpenAPIType: "array",
-		IsArray:     true,
-		ItemsType:   "string",
-		Enum:        []string{"active", "pending", "done"},
-	}
-
-	result := gen.generateParameterFieldSchema(field)
-
-	schema, err := result.BuildSchema()
-	if err != nil {
-		t.Fatalf("BuildSchema() error = %v", err)
-	}
+	tests := []struct {
+		name             string
+		field            *resolver.ResolvedField
+		wantDescriptions bool
+	}{
+		{
+			name: "derived enum with descriptions",
+			field: &resolver.ResolvedField{
+				Name:             "status",
+				OpenAPIType:      "string",
+				Enum:             []string{"pending", "done"},
+				EnumVarNames:     []string{"TaskPending", "TaskDone"},
+				EnumDescriptions: []string{"Waiting", ""},
+			},
+			wantDescriptions: true,
+		},
+		{
+			name: "derived enum without descriptions",
+			field: &resolver.ResolvedField{
+				Name:             "priority",
+				OpenAPIType:      "integer",
+				Enum:             []string{"0", "1"},
+				EnumVarNames:     []string{"PriorityLow", "PriorityHigh"},
+				EnumDescriptions: []string{"", ""},
+			},
+		},
+		{
+			name: "array items carry the extensions",
+			field: &resolver.ResolvedField{
+				Name:         "labels",
+				OpenAPIType:  "array",
+				IsArray:      true,
+				ItemsType:    "string",
+				Type:         &resolver.TypeDescriptor{OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "string"}},
+				Enum:         []string{"pending", "done"},
+				EnumVarNames: []string{"TaskPending", "TaskDone"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := gen.generateFieldSchema(tt.field).BuildSchema()
+			if err != nil {
+				t.Fatalf("BuildSchema() error = %v", err)
+			}
+			if tt.field.IsArray {
+				schema, err = schema.Items.A.BuildSchema()
+				if err != nil {
+					t.Fatalf("BuildSchema() for items error = %v", err)
+				}
+			}
+
+			if len(schema.Enum) != len(tt.field.Enum) {
+				t.Fatalf("enum has %d values, want %d", len(schema.Enum), len(tt.field.Enum))
+			}
+			if schema.Extensions == nil {
+				t.Fatal("extensions not set")
+			}
+
+			varNames, ok := schema.Extensions.Get("x-enum-varnames")
+			if !ok {
+				t.Fatal("x-enum-varnames not set")
+			}
+			if len(varNames.Content) != len(tt.field.EnumVarNames) || varNames.Content[0].Value != tt.field.EnumVarNames[0] {
+				t.Errorf("x-enum-varnames = %v, want %v", varNames.Content, tt.field.EnumVarNames)
+			}
+
+			_, ok = schema.Extensions.Get("x-enum-descriptions")
+			if ok != tt.wantDescriptions {
+				t.Errorf("x-enum-descriptions present = %v, want %v", ok, tt.wantDescriptions)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateParameterFieldSchema_ArrayEnum(t *testing.T) {
+	gen := NewGenerator("3.0")
+
+	field := &resolver.ResolvedField{
+		Name:        "status",
+		GoName:      "Status",
+		OpenAPIType: "array",
+		IsArray:     true,
+		ItemsType:   "string",
+		Enum:        []string{"active", "pending", "done"},
+	}
+
+	result := gen.generateParameterFieldSchema(field)
+
+	schema, err := result.BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
 
 	// Verify items has enum
 	if schema.Items == nil {
@@ -1100,3 +1729,745 @@ func TestGenerator_OpenAPIVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerator_GenerateFieldSchema_EncodingJSONTypes(t *testing.T) {
+	gen := NewGenerator("3.0")
+	three := 3
+	schemas := map[string]*resolver.ResolvedSchema{
+		"User": {Name: "User", GoTypeName: "User", PkgPath: "example.com/models"},
+	}
+
+	tests := []struct {
+		name     string
+		field    *resolver.ResolvedField
+		wantType string
+		format   string
+		itemsRef string
+		length   int64
+	}{
+		{
+			name:     "byte slice is a base64 string",
+			field:    &resolver.ResolvedField{Name: "data", GoType: "[]byte", OpenAPIType: "string", Format: "byte"},
+			wantType: "string",
+			format:   "byte",
+		},
+		{
+			name: "fixed-size array of schemas",
+			field: &resolver.ResolvedField{Name: "users", GoType: "[3]example.com/models.User", OpenAPIType: "array",
+				IsArray: true, ItemsType: "object", Type: &resolver.TypeDescriptor{
+					GoType: "[3]example.com/models.User", OpenAPIType: "array", Length: &three,
+					Items: &resolver.TypeDescriptor{GoType: "example.com/models.User", OpenAPIType: "object"},
+				}},
+			wantType: "array",
+			itemsRef: "#/components/schemas/User",
+			length:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := gen.generateFieldSchemaWithRefs(tt.field, schemas).BuildSchema()
+			if err != nil {
+				t.Fatalf("BuildSchema() error = %v", err)
+			}
+			if len(schema.Type) == 0 || schema.Type[0] != tt.wantType {
+				t.Errorf("type = %v, want %s", schema.Type, tt.wantType)
+			}
+			if schema.Format != tt.format {
+				t.Errorf("format = %q, want %q", schema.Format, tt.format)
+			}
+			if tt.itemsRef != "" {
+				if schema.Items == nil || schema.Items.A.GetReference() != tt.itemsRef {
+					t.Errorf("items should reference %s", tt.itemsRef)
+				}
+			}
+			if tt.length > 0 && (schema.MinItems == nil || *schema.MinItems != tt.length || schema.MaxItems == nil || *schema.MaxItems != tt.length) {
+				t.Errorf("minItems/maxItems = %v/%v, want %d", schema.MinItems, schema.MaxItems, tt.length)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateFieldSchema_Bytes31(t *testing.T) {
+	gen := NewGenerator("3.1")
+	field := &resolver.ResolvedField{Name: "data", GoType: "[]byte", OpenAPIType: "string", Format: "byte"}
+
+	schema, err := gen.generateFieldSchemaWithRefs(field, nil).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+	if len(schema.Type) != 1 || schema.Type[0] != "string" {
+		t.Errorf("type = %v, want [string]", schema.Type)
+	}
+	if schema.Format != "byte" || schema.ContentEncoding != "base64" {
+		t.Errorf("format = %q, contentEncoding = %q, want byte and base64", schema.Format, schema.ContentEncoding)
+	}
+}
+
+func TestGenerator_GenerateFieldSchema_NestedContainers(t *testing.T) {
+	gen := NewGenerator("3.0")
+	schemas := map[string]*resolver.ResolvedSchema{
+		"User": {Name: "User", GoTypeName: "User", PkgPath: "example.com/models"},
+	}
+	user := &resolver.TypeDescriptor{GoType: "example.com/models.User", OpenAPIType: "object"}
+
+	// map[string][]User
+	groups := &resolver.ResolvedField{
+		Name: "groups", GoType: "map[string][]example.com/models.User", OpenAPIType: "object",
+		Description: "Users by group",
+		Type: &resolver.TypeDescriptor{OpenAPIType: "object", MapValue: &resolver.TypeDescriptor{
+			OpenAPIType: "array", Items: user,
+		}},
+	}
+
+	schema, err := gen.generateFieldSchemaWithRefs(groups, schemas).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+	if len(schema.Type) == 0 || schema.Type[0] != "object" || schema.Description != "Users by group" {
+		t.Errorf("type = %v, description = %q, want object with the field description", schema.Type, schema.Description)
+	}
+	values, _ := schema.AdditionalProperties.A.BuildSchema()
+	if len(values.Type) == 0 || values.Type[0] != "array" {
+		t.Fatalf("additionalProperties type = %v, want array", values.Type)
+	}
+	if ref := values.Items.A.GetReference(); ref != "#/components/schemas/User" {
+		t.Errorf("additionalProperties items = %q, want $ref to User", ref)
+	}
+
+	// [][]*int
+	matrix := &resolver.ResolvedField{
+		Name: "matrix", GoType: "[][]*int", OpenAPIType: "array", IsArray: true, ItemsType: "array",
+		Type: &resolver.TypeDescriptor{OpenAPIType: "array", Items: &resolver.TypeDescriptor{
+			OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "integer", Nullable: true},
+		}},
+	}
+
+	schema, err = gen.generateFieldSchema(matrix).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+	rows, _ := schema.Items.A.BuildSchema()
+	cells, _ := rows.Items.A.BuildSchema()
+	if len(rows.Type) == 0 || rows.Type[0] != "array" || len(cells.Type) == 0 || cells.Type[0] != "integer" {
+		t.Errorf("items = %v of %v, want array of integer", rows.Type, cells.Type)
+	}
+	if cells.Nullable == nil || !*cells.Nullable {
+		t.Error("pointer elements should be nullable")
+	}
+}
+
+func TestGenerator_GenerateBodySchema_NestedContainers(t *testing.T) {
+	gen := NewGenerator("3.0")
+
+	// [][]User, resolved from an annotation
+	body := &resolver.ResolvedBody{
+		Schema: "[][]User", IsArray: true, ElementType: "User",
+		Type: &resolver.TypeDescriptor{OpenAPIType: "array", Items: &resolver.TypeDescriptor{
+			OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "object", Ref: "User"},
+		}},
+	}
+
+	schema, err := gen.generateBodySchema(body, map[string]*resolver.ResolvedSchema{}).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+	inner, _ := schema.Items.A.BuildSchema()
+	if len(inner.Type) == 0 || inner.Type[0] != "array" {
+		t.Fatalf("items type = %v, want array", inner.Type)
+	}
+	if ref := inner.Items.A.GetReference(); ref != "#/components/schemas/User" {
+		t.Errorf("inner items = %q, want $ref to User", ref)
+	}
+}
+
+func TestGenerator_GenerateFieldSchema_NullableContainers(t *testing.T) {
+	gen := NewGenerator("3.1")
+	schemas := map[string]*resolver.ResolvedSchema{
+		"Base": {Name: "Base", GoTypeName: "Base", PkgPath: "example.com/models"},
+	}
+	item := &resolver.TypeDescriptor{GoType: "example.com/models.Base", OpenAPIType: "object"}
+
+	tests := []struct {
+		name  string
+		field *resolver.ResolvedField
+		want  []string
+	}{
+		{
+			name: "pointer to slice",
+			field: &resolver.ResolvedField{
+				Name: "items", GoType: "*[]example.com/models.Base", OpenAPIType: "array", IsArray: true, Nullable: true,
+				Type: &resolver.TypeDescriptor{OpenAPIType: "array", Items: item, Nullable: true},
+			},
+			want: []string{"array", "null"},
+		},
+		{
+			name: "pointer to map",
+			field: &resolver.ResolvedField{
+				Name: "byName", GoType: "*map[string]example.com/models.Base", OpenAPIType: "object", Nullable: true,
+				Type: &resolver.TypeDescriptor{OpenAPIType: "object", MapValue: item, Nullable: true},
+			},
+			want: []string{"object", "null"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := gen.generateFieldSchemaWithRefs(tt.field, schemas).BuildSchema()
+			if err != nil {
+				t.Fatalf("BuildSchema() error = %v", err)
+			}
+			if !slices.Equal(schema.Type, tt.want) {
+				t.Errorf("type = %v, want %v", schema.Type, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateTypeSchema_NullableRefs(t *testing.T) {
+	schemas := map[string]*resolver.ResolvedSchema{
+		"User": {Name: "User", GoTypeName: "User", PkgPath: "example.com/models"},
+	}
+	user := &resolver.TypeDescriptor{GoType: "example.com/models.User", OpenAPIType: "object", Nullable: true}
+	const ref = "#/components/schemas/User"
+
+	// []*User
+	items := &resolver.TypeDescriptor{OpenAPIType: "array", Items: user}
+	// map[string]*User
+	values := &resolver.TypeDescriptor{OpenAPIType: "object", MapValue: user}
+
+	t.Run("3.0", func(t *testing.T) {
+		gen := NewGenerator("3.0")
+		for _, desc := range []*resolver.TypeDescriptor{items, values} {
+			schema, _ := gen.generateTypeSchema(desc, schemas).BuildSchema()
+			leaf := schema.Items
+			if leaf == nil {
+				leaf = schema.AdditionalProperties
+			}
+			element, _ := leaf.A.BuildSchema()
+			if element.Nullable == nil || !*element.Nullable {
+				t.Errorf("%v: element should be nullable", schema.Type)
+			}
+			if len(element.AllOf) != 1 || element.AllOf[0].GetReference() != ref {
+				t.Errorf("%v: element should be allOf [$ref to User]", schema.Type)
+			}
+		}
+	})
+
+	for _, version := range []string{"3.1", "3.2"} {
+		t.Run(version, func(t *testing.T) {
+			gen := NewGenerator(version)
+			for _, desc := range []*resolver.TypeDescriptor{items, values} {
+				schema, _ := gen.generateTypeSchema(desc, schemas).BuildSchema()
+				leaf := schema.Items
+				if leaf == nil {
+					leaf = schema.AdditionalProperties
+				}
+				element, _ := leaf.A.BuildSchema()
+				if len(element.OneOf) != 2 || element.OneOf[0].GetReference() != ref {
+					t.Fatalf("%v: element should be oneOf [$ref to User, null]", schema.Type)
+				}
+				null, _ := element.OneOf[1].BuildSchema()
+				if !slices.Equal(null.Type, []string{"null"}) {
+					t.Errorf("%v: second alternative type = %v, want [null]", schema.Type, null.Type)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateSchema_Composition(t *testing.T) {
+	gen := NewGenerator("3.1")
+
+	payment := &resolver.ResolvedSchema{
+		Name:        "PaymentMethod",
+		GoTypeName:  "PaymentMethod",
+		Description: "How an order is paid",
+		Value: &resolver.ResolvedField{
+			Name: "PaymentMethod", IsAnyValue: true,
+			Composition: &resolver.Composition{
+				Kind:    "oneOf",
+				Schemas: []string{"BankTransfer", "Card"},
+				Discriminator: &resolver.Discriminator{
+					PropertyName: "type",
+					Mapping: []*resolver.DiscriminatorMapping{
+						{Value: "bank_transfer", Schema: "BankTransfer"},
+						{Value: "card", Schema: "Card"},
+					},
+				},
+			},
+		},
+	}
+
+	s, err := gen.generateSchema(payment, map[string]*resolver.ResolvedSchema{"PaymentMethod": payment}).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+	if len(s.OneOf) != 2 || s.OneOf[1].GetReference() != "#/components/schemas/Card" {
+		t.Fatalf("oneOf = %v, want $refs to BankTransfer and Card", s.OneOf)
+	}
+	if s.Description != "How an order is paid" || len(s.Type) != 0 {
+		t.Errorf("description = %q, type = %v, want description and no type", s.Description, s.Type)
+	}
+	if s.Discriminator == nil || s.Discriminator.PropertyName != "type" {
+		t.Fatalf("discriminator = %+v, want propertyName type", s.Discriminator)
+	}
+	if ref, _ := s.Discriminator.Mapping.Get("bank_transfer"); ref != "#/components/schemas/BankTransfer" {
+		t.Errorf("mapping[bank_transfer] = %q, want BankTransfer reference", ref)
+	}
+
+	// anyOf on the items of an array
+	history := &resolver.ResolvedField{
+		Name: "history", GoType: "[]any", OpenAPIType: "array", IsArray: true,
+		Type: &resolver.TypeDescriptor{OpenAPIType: "array", Items: &resolver.TypeDescriptor{
+			IsAnyValue:  true,
+			Composition: &resolver.Composition{Kind: "anyOf", Schemas: []string{"Card", "Wallet"}},
+		}},
+	}
+
+	s, err = gen.generateFieldSchemaWithRefs(history, map[string]*resolver.ResolvedSchema{}).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+	items, _ := s.Items.A.BuildSchema()
+	if len(items.AnyOf) != 2 || items.AnyOf[0].GetReference() != "#/components/schemas/Card" || items.Discriminator != nil {
+		t.Errorf("items anyOf = %v, want $refs to Card and Wallet without discriminator", items.AnyOf)
+	}
+}
+
+func TestGenerator_GenerateBodySchema_Alternatives(t *testing.T) {
+	gen := NewGenerator("3.0")
+
+	wrapperSchema := &resolver.ResolvedSchema{
+		Name: "DataResponse",
+		Fields: []*resolver.ResolvedField{
+			{Name: "data", GoName: "Data", OpenAPIType: "object", Required: true},
+		},
+	}
+
+	// User | []Organization, bound to DataResponse.Data
+	body := &resolver.ResolvedBody{
+		Schema: "User | []Organization",
+		Type: &resolver.TypeDescriptor{OneOf: []*resolver.TypeDescriptor{
+			{OpenAPIType: "object", Ref: "User"},
+			{OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "object", Ref: "Organization"}},
+		}},
+		Bind: &resolver.ResolvedBindTarget{Wrapper: "DataResponse", Field: "Data", WrapperSchema: wrapperSchema},
+	}
+
+	schema, err := gen.generateBodySchema(body, map[string]*resolver.ResolvedSchema{"DataResponse": wrapperSchema}).BuildSchema()
+	if err != nil {
+		t.Fatalf("BuildSchema() error = %v", err)
+	}
+
+	data, ok := schema.Properties.Get("data")
+	if !ok {
+		t.Fatal("data property not found")
+	}
+	dataSchema, _ := data.BuildSchema()
+	if len(dataSchema.OneOf) != 2 {
+		t.Fatalf("data oneOf has %d alternatives, want 2", len(dataSchema.OneOf))
+	}
+	if ref := dataSchema.OneOf[0].GetReference(); ref != "#/components/schemas/User" {
+		t.Errorf("oneOf[0] = %q, want $ref to User", ref)
+	}
+	orgs, _ := dataSchema.OneOf[1].BuildSchema()
+	if len(orgs.Type) == 0 || orgs.Type[0] != "array" || orgs.Items.A.GetReference() != "#/components/schemas/Organization" {
+		t.Errorf("oneOf[1] = %v, want array of $ref to Organization", orgs.Type)
+	}
+}
+
+func TestGenerator_GenerateSchema_XML(t *testing.T) {
+	item := &resolver.ResolvedSchema{Name: "Item", GoTypeName: "Item"}
+	feed := &resolver.ResolvedSchema{
+		Name:       "Feed",
+		GoTypeName: "Feed",
+		XML:        &resolver.XMLInfo{Name: "feed", Namespace: "http://www.w3.org/2005/Atom"},
+		Fields: []*resolver.ResolvedField{
+			{Name: "id", GoName: "ID", GoType: "string", OpenAPIType: "string", XML: &resolver.XMLInfo{NodeType: "attribute"}},
+			{Name: "note", GoName: "Note", GoType: "string", OpenAPIType: "string", XML: &resolver.XMLInfo{NodeType: "text"}},
+			{Name: "title", GoName: "Title", GoType: "string", OpenAPIType: "string", XML: &resolver.XMLInfo{Prefix: "atom"}},
+			{
+				Name: "tags", GoName: "Tags", GoType: "[]string", OpenAPIType: "array", IsArray: true, ItemsType: "string",
+				Type: &resolver.TypeDescriptor{OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "string"}},
+				XML:  &resolver.XMLInfo{ItemsName: "tag"},
+			},
+			{
+				Name: "items", GoName: "Items", GoType: "[]Item", OpenAPIType: "array", IsArray: true, ItemsType: "object",
+				Type: &resolver.TypeDescriptor{OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "object", Ref: "Item"}},
+				XML:  &resolver.XMLInfo{Wrapped: true, ItemsName: "item"},
+			},
+			{Name: "owner", GoName: "Owner", GoType: "Item", OpenAPIType: "object", XML: &resolver.XMLInfo{Name: "person"}},
+			{Name: "latest", GoName: "Latest", GoType: "Item", OpenAPIType: "object"},
+		},
+	}
+	schemas := map[string]*resolver.ResolvedSchema{"Feed": feed, "Item": item}
+
+	tests := []struct {
+		version       string
+		wantAttribute bool
+		wantID        string // nodeType of the id attribute
+		wantNote      bool   // note has an xml object
+	}{
+		{version: "3.0", wantAttribute: true},
+		{version: "3.1", wantAttribute: true},
+		{version: "3.2", wantID: "attribute", wantNote: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			gen := NewGenerator(tt.version)
+			s, err := gen.generateSchema(feed, schemas).BuildSchema()
+			if err != nil {
+				t.Fatalf("BuildSchema() error = %v", err)
+			}
+
+			if s.XML == nil || s.XML.Name != "feed" || s.XML.Namespace != "http://www.w3.org/2005/Atom" {
+				t.Errorf("schema xml = %+v, want feed in the Atom namespace", s.XML)
+			}
+
+			property := func(name string) *base.Schema {
+				schema, _ := s.Properties.GetOrZero(name).BuildSchema()
+				return schema
+			}
+
+			id := property("id").XML
+			if id == nil || id.Attribute != tt.wantAttribute || id.NodeType != tt.wantID {
+				t.Errorf("id xml = %+v, want attribute %v, nodeType %q", id, tt.wantAttribute, tt.wantID)
+			}
+			if note := property("note").XML; (note != nil) != tt.wantNote {
+				t.Errorf("note xml = %+v, want present %v", note, tt.wantNote)
+			}
+			if title := property("title").XML; title == nil || title.Prefix != "atom" {
+				t.Errorf("title xml = %+v, want prefix atom", title)
+			}
+
+			tags := property("tags")
+			if tags.XML != nil {
+				t.Errorf("tags xml = %+v, want none", tags.XML)
+			}
+			if tag, _ := tags.Items.A.BuildSchema(); tag == nil || tag.XML == nil || tag.XML.Name != "tag" {
+				t.Errorf("tags items should be named tag")
+			}
+
+			items := property("items")
+			if items.XML == nil || !items.XML.Wrapped {
+				t.Errorf("items xml = %+v, want wrapped", items.XML)
+			}
+			element, _ := items.Items.A.BuildSchema()
+			if element == nil || element.XML == nil || element.XML.Name != "item" || len(element.AllOf) != 1 ||
+				element.AllOf[0].GetReference() != "#/components/schemas/Item" {
+				t.Errorf("items items should be allOf the Item reference named item")
+			}
+
+			// A $ref cannot carry an xml object, so a referenced field is wrapped in allOf
+			owner := property("owner")
+			if owner.XML == nil || owner.XML.Name != "person" || len(owner.AllOf) != 1 ||
+				owner.AllOf[0].GetReference() != "#/components/schemas/Item" {
+				t.Errorf("owner should be allOf the Item reference named person")
+			}
+			if ref := s.Properties.GetOrZero("latest").GetReference(); ref != "#/components/schemas/Item" {
+				t.Errorf("latest = %q, want a bare $ref to Item", ref)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateRequestBody_FileUploads(t *testing.T) {
+	headers := &resolver.ResolvedParameter{
+		Name:   "PartHeaders",
+		Type:   "header",
+		Fields: []*resolver.ResolvedField{{Name: "X-Checksum", OpenAPIType: "string"}},
+	}
+	form := &resolver.ResolvedSchema{
+		Name: "UploadForm",
+		Fields: []*resolver.ResolvedField{
+			{
+				Name: "avatar", GoType: "*mime/multipart.FileHeader", OpenAPIType: "string", Format: "binary",
+				Encoding: &resolver.PartEncoding{ContentTypes: []string{"image/png"}},
+			},
+			{
+				Name: "attachments", GoType: "[]*mime/multipart.FileHeader", OpenAPIType: "array", IsArray: true, ItemsType: "string",
+				Type: &resolver.TypeDescriptor{OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "string", Format: "binary"}},
+			},
+			{Name: "thumbnail", GoType: "[]byte", OpenAPIType: "string", Format: "byte"},
+			{
+				Name: "document", GoType: "io.Reader", OpenAPIType: "string", Format: "binary",
+				Encoding: &resolver.PartEncoding{HeaderParams: []string{"PartHeaders"}, Headers: []*resolver.ResolvedParameter{headers}},
+			},
+			{Name: "title", GoType: "string", OpenAPIType: "string"},
+		},
+	}
+	schemas := map[string]*resolver.ResolvedSchema{"UploadForm": form}
+	body := &resolver.ResolvedBody{Schema: "UploadForm", Type: &resolver.TypeDescriptor{OpenAPIType: "object", Ref: "UploadForm"}}
+	request := &resolver.ResolvedRequestBody{
+		ContentType: "multipart/form-data",
+		Body:        body,
+		Content: []*resolver.ResolvedContent{
+			{ContentType: "multipart/form-data", Body: body},
+			{ContentType: "application/json", Body: body},
+		},
+	}
+
+	tests := []struct {
+		version          string
+		wantFormat       string
+		wantMediaType    string
+		wantAvatarMedia  string
+		wantAvatarFormat string
+	}{
+		{version: "3.0", wantFormat: "binary", wantAvatarFormat: "binary"},
+		{version: "3.1", wantMediaType: "application/octet-stream", wantAvatarMedia: "image/png"},
+		{version: "3.2", wantMediaType: "application/octet-stream", wantAvatarMedia: "image/png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			gen := NewGenerator(tt.version)
+			requestBody := gen.generateRequestBody(request, schemas)
+
+			// Only multipart and form bodies have an encoding
+			if encoding := requestBody.Content.GetOrZero("application/json").Encoding; encoding != nil {
+				t.Errorf("application/json encoding = %v, want nil", encoding)
+			}
+			encoding := requestBody.Content.GetOrZero("multipart/form-data").Encoding
+			if encoding == nil {
+				t.Fatal("multipart/form-data encoding not found")
+			}
+
+			var parts []string
+			for name := range encoding.KeysFromOldest() {
+				parts = append(parts, name)
+			}
+			if got := strings.Join(parts, " "); got != "avatar document" {
+				t.Errorf("encoded parts = %q, want %q", got, "avatar document")
+			}
+			if got := encoding.GetOrZero("avatar").ContentType; got != "image/png" {
+				t.Errorf("avatar contentType = %q, want image/png", got)
+			}
+			if h := encoding.GetOrZero("document").Headers; h == nil || h.GetOrZero("X-Checksum") == nil {
+				t.Errorf("document headers = %v, want X-Checksum", h)
+			}
+
+			// []byte parts are binary in multipart bodies, while JSON keeps the base64 component
+			if !requestBody.Content.GetOrZero("application/json").Schema.IsReference() {
+				t.Error("application/json schema should reference UploadForm")
+			}
+			multipart, _ := requestBody.Content.GetOrZero("multipart/form-data").Schema.BuildSchema()
+			thumbnail, _ := multipart.Properties.GetOrZero("thumbnail").BuildSchema()
+			if thumbnail.Format != tt.wantFormat || thumbnail.ContentMediaType != tt.wantMediaType || thumbnail.ContentEncoding != "" {
+				t.Errorf("thumbnail format = %q, contentMediaType = %q, contentEncoding = %q, want %q, %q, none",
+					thumbnail.Format, thumbnail.ContentMediaType, thumbnail.ContentEncoding, tt.wantFormat, tt.wantMediaType)
+			}
+
+			schema, _ := gen.generateSchema(form, schemas).BuildSchema()
+			avatar, _ := schema.Properties.GetOrZero("avatar").BuildSchema()
+			if avatar.Format != tt.wantAvatarFormat || avatar.ContentMediaType != tt.wantAvatarMedia {
+				t.Errorf("avatar format = %q, contentMediaType = %q, want %q, %q", avatar.Format, avatar.ContentMediaType, tt.wantAvatarFormat, tt.wantAvatarMedia)
+			}
+			if avatar.Nullable != nil {
+				t.Error("avatar should not be nullable")
+			}
+
+			// Multi-file uploads are arrays of binary strings
+			attachments, _ := schema.Properties.GetOrZero("attachments").BuildSchema()
+			items, _ := attachments.Items.A.BuildSchema()
+			if items.Format != tt.wantFormat || items.ContentMediaType != tt.wantMediaType {
+				t.Errorf("attachments items format = %q, contentMediaType = %q, want %q, %q", items.Format, items.ContentMediaType, tt.wantFormat, tt.wantMediaType)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateInlineRequestBody_Encoding(t *testing.T) {
+	gen := NewGenerator("3.0")
+
+	inline := &resolver.ResolvedInlineBody{
+		ContentType:  "multipart/form-data",
+		ContentTypes: []string{"multipart/form-data", "application/x-www-form-urlencoded"},
+		Fields: []*resolver.ResolvedField{
+			{
+				Name: "metadata", OpenAPIType: "string",
+				Encoding: &resolver.PartEncoding{ContentTypes: []string{"application/json"}},
+			},
+			{Name: "raw", OpenAPIType: "string", Format: "byte"},
+		},
+	}
+
+	requestBody := gen.generateInlineRequestBody(inline, nil)
+
+	for _, contentType := range []string{"multipart/form-data", "application/x-www-form-urlencoded"} {
+		encoding := requestBody.Content.GetOrZero(contentType).Encoding
+		if encoding == nil || encoding.Len() != 1 || encoding.GetOrZero("metadata").ContentType != "application/json" {
+			t.Errorf("%s encoding = %v, want metadata only", contentType, encoding)
+		}
+	}
+
+	// Raw bytes are only sent as binary parts in multipart bodies
+	for contentType, want := range map[string]string{"multipart/form-data": "binary", "application/x-www-form-urlencoded": "byte"} {
+		schema, _ := requestBody.Content.GetOrZero(contentType).Schema.BuildSchema()
+		raw, _ := schema.Properties.GetOrZero("raw").BuildSchema()
+		if raw.Format != want {
+			t.Errorf("%s raw format = %q, want %q", contentType, raw.Format, want)
+		}
+	}
+}
+
+func TestGenerator_GenerateContent_BinaryBodies(t *testing.T) {
+	pdf := &resolver.ResolvedBody{Schema: "binary", Type: &resolver.TypeDescriptor{GoType: "binary", OpenAPIType: "string", Format: "binary"}}
+	bytes := &resolver.ResolvedBody{Schema: "[]byte", Type: &resolver.TypeDescriptor{GoType: "[]byte", OpenAPIType: "string", Format: "byte"}}
+	disposition := &resolver.ResolvedParameter{
+		Name: "Content-Disposition",
+		Type: "header",
+		Fields: []*resolver.ResolvedField{
+			{Name: "Content-Disposition", OpenAPIType: "string", Example: `attachment; filename="report.pdf"`},
+		},
+	}
+	response := &resolver.ResolvedResponse{
+		StatusCode:  "200",
+		Description: "Report",
+		Headers:     []*resolver.ResolvedParameter{disposition},
+		Content: []*resolver.ResolvedContent{
+			{ContentType: "application/pdf", Body: pdf},
+			{ContentType: "image/png", Body: bytes},
+			{ContentType: "application/json", Body: bytes},
+		},
+	}
+
+	tests := []struct {
+		version     string
+		contentType string
+		wantFormat  string
+		wantMedia   string
+		wantEncode  string
+	}{
+		{version: "3.0", contentType: "application/pdf", wantFormat: "binary"},
+		{version: "3.0", contentType: "image/png", wantFormat: "binary"},
+		{version: "3.0", contentType: "application/json", wantFormat: "byte"},
+		{version: "3.1", contentType: "application/pdf", wantMedia: "application/pdf"},
+		{version: "3.1", contentType: "image/png", wantMedia: "image/png"},
+		{version: "3.1", contentType: "application/json", wantFormat: "byte", wantEncode: "base64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.contentType, func(t *testing.T) {
+			gen := NewGenerator(tt.version)
+			responses := gen.generateResponsesWithInline(map[string]*resolver.ResolvedResponse{"200": response}, nil, nil)
+			resp := responses.Codes.GetOrZero("200")
+
+			schema, err := resp.Content.GetOrZero(tt.contentType).Schema.BuildSchema()
+			if err != nil {
+				t.Fatalf("BuildSchema() error = %v", err)
+			}
+			if len(schema.Type) != 1 || schema.Type[0] != "string" {
+				t.Errorf("type = %v, want string", schema.Type)
+			}
+			if schema.Format != tt.wantFormat || schema.ContentMediaType != tt.wantMedia || schema.ContentEncoding != tt.wantEncode {
+				t.Errorf("format = %q, contentMediaType = %q, contentEncoding = %q, want %q, %q, %q",
+					schema.Format, schema.ContentMediaType, schema.ContentEncoding, tt.wantFormat, tt.wantMedia, tt.wantEncode)
+			}
+
+			header := resp.Headers.GetOrZero("Content-Disposition")
+			if header == nil || header.Example == nil || header.Example.Value != `attachment; filename="report.pdf"` {
+				t.Errorf("Content-Disposition = %+v, want an example", header)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateContent_Streams(t *testing.T) {
+	message := &resolver.ResolvedBody{Schema: "Message", ElementType: "Message"}
+	progress := &resolver.ResolvedEvent{Name: "progress", Body: &resolver.ResolvedBody{Schema: "Progress", ElementType: "Progress"}}
+	response := &resolver.ResolvedResponse{
+		StatusCode:  "200",
+		Description: "Stream",
+		Content: []*resolver.ResolvedContent{
+			{ContentType: "text/event-stream", Stream: "sse", Body: message, Events: []*resolver.ResolvedEvent{progress}},
+			{ContentType: "application/x-ndjson", Stream: "ndjson", Body: message},
+		},
+	}
+
+	tests := []struct {
+		version      string
+		wantItem     bool
+		wantJSONData bool
+	}{
+		{version: "3.0"},
+		{version: "3.1", wantJSONData: true},
+		{version: "3.2", wantItem: true, wantJSONData: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			gen := NewGenerator(tt.version)
+			responses := gen.generateResponsesWithInline(map[string]*resolver.ResolvedResponse{"200": response}, nil, nil)
+			content := responses.Codes.GetOrZero("200").Content
+
+			for contentType, mediaType := range content.FromOldest() {
+				item := mediaType.Schema
+				if tt.wantItem {
+					if mediaType.Schema != nil {
+						t.Errorf("%s: schema should be omitted in favor of itemSchema", contentType)
+					}
+					item = mediaType.ItemSchema
+				} else if mediaType.Extensions == nil || mediaType.Extensions.GetOrZero("x-stream") == nil {
+					t.Errorf("%s: x-stream extension missing", contentType)
+				}
+				if item == nil {
+					t.Fatalf("%s: item schema missing", contentType)
+				}
+			}
+
+			ndjson := content.GetOrZero("application/x-ndjson")
+			item := ndjson.Schema
+			if tt.wantItem {
+				item = ndjson.ItemSchema
+			}
+			if item.GetReference() != "#/components/schemas/Message" {
+				t.Errorf("ndjson item = %s, want Message", item.GetReference())
+			}
+
+			sse := content.GetOrZero("text/event-stream")
+			item = sse.Schema
+			if tt.wantItem {
+				item = sse.ItemSchema
+			}
+			events, err := item.BuildSchema()
+			if err != nil {
+				t.Fatalf("BuildSchema() error = %v", err)
+			}
+			if len(events.OneOf) != 2 {
+				t.Fatalf("oneOf = %d, want progress and message events", len(events.OneOf))
+			}
+
+			wants := []struct {
+				name     string
+				ref      string
+				required string
+			}{
+				{name: "progress", ref: "#/components/schemas/Progress", required: "data,event"},
+				{name: "message", ref: "#/components/schemas/Message", required: "data"},
+			}
+			for i, want := range wants {
+				event := events.OneOf[i].Schema()
+				if got := event.Properties.GetOrZero("event").Schema().Enum; len(got) != 1 || got[0].Value != want.name {
+					t.Errorf("event %d enum = %v, want %s", i, got, want.name)
+				}
+				if got := strings.Join(event.Required, ","); got != want.required {
+					t.Errorf("event %s required = %s, want %s", want.name, got, want.required)
+				}
+
+				data := event.Properties.GetOrZero("data")
+				if !tt.wantJSONData {
+					if data.GetReference() != want.ref {
+						t.Errorf("event %s data = %s, want %s", want.name, data.GetReference(), want.ref)
+					}
+					continue
+				}
+				if schema := data.Schema(); schema.ContentMediaType != "application/json" || schema.ContentSchema.GetReference() != want.ref {
+					t.Errorf("event %s data = %+v, want a JSON string of %s", want.name, schema, want.ref)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"go.yaml.in/yaml/v4"
+)
+
+// schemaFromMap builds a schema from a full schema declared in a type mapping
+// Supported keywords: type, format, pattern, title, description, enum, example, default,
+// minimum, maximum, minLength, maxLength, minItems, maxItems, uniqueItems, nullable,
+// items, properties, required, additionalProperties and x- extensions.
+// Nested schemas may use $ref.
+func (g *Generator) schemaFromMap(m map[string]any) *base.Schema {
+	schema := g.schemaBuilder.NewSchema()
+
+	switch t := m["type"].(type) {
+	case string:
+		g.schemaBuilder.SetType(schema, t)
+	case []any:
+		g.schemaBuilder.SetTypes(schema, stringValues(t))
+	}
+
+	schema.Format, _ = m["format"].(string)
+	schema.Pattern, _ = m["pattern"].(string)
+	schema.Title, _ = m["title"].(string)
+	schema.Description, _ = m["description"].(string)
+
+	if values, ok := m["enum"].([]any); ok {
+		for _, v := range values {
+			schema.Enum = append(schema.Enum, anyToYAMLNode(v))
+		}
+	}
+	if v, ok := m["example"]; ok {
+		schema.Example = anyToYAMLNode(v)
+	}
+	if v, ok := m["default"]; ok {
+		schema.Default = anyToYAMLNode(v)
+	}
+
+	schema.Minimum = floatValue(m["minimum"])
+	schema.Maximum = floatValue(m["maximum"])
+	schema.MinLength = intValue(m["minLength"])
+	schema.MaxLength = intValue(m["maxLength"])
+	schema.MinItems = intValue(m["minItems"])
+	schema.MaxItems = intValue(m["maxItems"])
+	if unique, ok := m["uniqueItems"].(bool); ok {
+		schema.UniqueItems = &unique
+	}
+
+	if items, ok := m["items"].(map[string]any); ok {
+		schema.Items = &base.DynamicValue[*base.SchemaProxy, bool]{A: g.schemaProxyFromMap(items)}
+	}
+
+	if properties, ok := m["properties"].(map[string]any); ok {
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		props := orderedmap.New[string, *base.SchemaProxy]()
+		for _, name := range names {
+			if prop, ok := properties[name].(map[string]any); ok {
+				props.Set(name, g.schemaProxyFromMap(prop))
+			}
+		}
+		schema.Properties = props
+	}
+	if required, ok := m["required"].([]any); ok {
+		schema.Required = stringValues(required)
+	}
+
+	switch additional := m["additionalProperties"].(type) {
+	case bool:
+		schema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: additional}
+	case map[string]any:
+		schema.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{A: g.schemaProxyFromMap(additional)}
+	}
+
+	if nullable, ok := m["nullable"].(bool); ok {
+		g.schemaBuilder.SetNullable(schema, nullable)
+	}
+
+	var extensions []string
+	for key := range m {
+		if strings.HasPrefix(key, "x-") {
+			extensions = append(extensions, key)
+		}
+	}
+	sort.Strings(extensions)
+	for _, key := range extensions {
+		if schema.Extensions == nil {
+			schema.Extensions = orderedmap.New[string, *yaml.Node]()
+		}
+		schema.Extensions.Set(key, anyToYAMLNode(m[key]))
+	}
+
+	return schema
+}
+
+// schemaProxyFromMap builds a schema proxy from a nested schema map, honoring $ref
+func (g *Generator) schemaProxyFromMap(m map[string]any) *base.SchemaProxy {
+	if ref, ok := m["$ref"].(string); ok {
+		return base.CreateSchemaProxyRef(ref)
+	}
+	return base.CreateSchemaProxy(g.schemaFromMap(m))
+}
+
+// anyToYAMLNode converts a decoded configuration value to a yaml node
+func anyToYAMLNode(v any) *yaml.Node {
+	node := &yaml.Node{}
+	if err := node.Encode(v); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: ""}
+	}
+	return node
+}
+
+// stringValues returns the string elements of a decoded list
+func stringValues(values []any) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// floatValue converts a decoded number to a float pointer (nil if not a number)
+func floatValue(v any) *float64 {
+	var f float64
+	switch n := v.(type) {
+	case int:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case uint64:
+		f = float64(n)
+	case float64:
+		f = n
+	default:
+		return nil
+	}
+	return &f
+}
+
+// intValue converts a decoded integer to an int64 pointer (nil if not an integer)
+func intValue(v any) *int64 {
+	var i int64
+	switch n := v.(type) {
+	case int:
+		i = int64(n)
+	case int64:
+		i = n
+	case uint64:
+		i = int64(n)
+	case float64:
+		i = int64(n)
+	default:
+		return nil
+	}
+	return &i
+}
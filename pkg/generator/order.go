@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"cmp"
+	"go/token"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/wontaeyang/go-specgen/pkg/resolver"
+)
+
+// Order represents the order of paths and component schemas in the generated spec
+type Order string
+
+const (
+	// OrderSource follows the declaration order of the Go source (files, then lines)
+	OrderSource Order = "source"
+	// OrderAlpha sorts paths and schemas by name
+	OrderAlpha Order = "alpha"
+	// OrderTag groups paths by their first tag, in the order tags are declared in @api,
+	// then in source order; schemas follow the source order
+	OrderTag Order = "tag"
+)
+
+// SetOrder sets the order of paths and component schemas (OrderSource by default)
+func (g *Generator) SetOrder(order Order) {
+	g.order = order
+}
+
+// sortEndpoints returns the endpoints in the order of the generator
+// Paths are emitted in the order of their first endpoint.
+func (g *Generator) sortEndpoints(endpoints []*resolver.ResolvedEndpoint, tags []*resolver.Tag) []*resolver.ResolvedEndpoint {
+	sorted := slices.Clone(endpoints)
+
+	bySource := func(a, b *resolver.ResolvedEndpoint) int {
+		return cmp.Or(
+			comparePositions(a.Position, b.Position),
+			strings.Compare(a.Path, b.Path),
+			strings.Compare(a.Method, b.Method),
+		)
+	}
+
+	switch g.order {
+	case OrderAlpha:
+		slices.SortStableFunc(sorted, func(a, b *resolver.ResolvedEndpoint) int {
+			return cmp.Or(strings.Compare(a.Path, b.Path), strings.Compare(a.Method, b.Method))
+		})
+	case OrderTag:
+		groups := tagGroups(endpoints, tags)
+		slices.SortStableFunc(sorted, func(a, b *resolver.ResolvedEndpoint) int {
+			return cmp.Or(cmp.Compare(groups[firstTag(a)], groups[firstTag(b)]), bySource(a, b))
+		})
+	default:
+		slices.SortStableFunc(sorted, bySource)
+	}
+	return sorted
+}
+
+// tagGroups numbers the tags of endpoints in grouping order: tags declared in @api first,
+// in declaration order, then undeclared tags by name, then untagged endpoints ("")
+func tagGroups(endpoints []*resolver.ResolvedEndpoint, tags []*resolver.Tag) map[string]int {
+	groups := make(map[string]int)
+	for _, tag := range tags {
+		if _, ok := groups[tag.Name]; !ok {
+			groups[tag.Name] = len(groups)
+		}
+	}
+
+	var undeclared []string
+	for _, endpoint := range endpoints {
+		tag := firstTag(endpoint)
+		if _, ok := groups[tag]; !ok && tag != "" && !slices.Contains(undeclared, tag) {
+			undeclared = append(undeclared, tag)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, tag := range undeclared {
+		groups[tag] = len(groups)
+	}
+
+	groups[""] = len(groups)
+	return groups
+}
+
+// firstTag returns the first tag of an endpoint, or "" when untagged
+func firstTag(endpoint *resolver.ResolvedEndpoint) string {
+	if len(endpoint.Tags) == 0 {
+		return ""
+	}
+	return endpoint.Tags[0]
+}
+
+// sortedSchemaNames returns the names of the component schemas in the order of the generator
+func (g *Generator) sortedSchemaNames(schemas map[string]*resolver.ResolvedSchema) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if g.order != OrderAlpha {
+		slices.SortStableFunc(names, func(a, b string) int {
+			return comparePositions(schemas[a].Position, schemas[b].Position)
+		})
+	}
+	return names
+}
+
+// sortedStatusCodes returns status codes in ascending order, with range codes (2XX) after
+// the codes they cover and default last, as letters sort after digits
+func sortedStatusCodes[V any](responses map[string]V) []string {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	slices.SortFunc(codes, func(a, b string) int {
+		return strings.Compare(strings.ToUpper(a), strings.ToUpper(b))
+	})
+	return codes
+}
+
+// comparePositions compares source positions by file, line and column
+// Unknown positions (e.g. of declarations built in code) sort last.
+func comparePositions(a, b token.Position) int {
+	if a.IsValid() != b.IsValid() {
+		if a.IsValid() {
+			return -1
+		}
+		return 1
+	}
+	return cmp.Or(
+		strings.Compare(a.Filename, b.Filename),
+		cmp.Compare(a.Line, b.Line),
+		cmp.Compare(a.Column, b.Column),
+	)
+}
@@ -1,7 +1,12 @@
 package generator
 
 import (
+	"slices"
+
 	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"go.yaml.in/yaml/v4"
 )
 
 // SchemaBuilder provides version-aware schema construction for OpenAPI 3.0, 3.1, and 3.2.
@@ -44,12 +49,30 @@ func (sb *SchemaBuilder) SetNullable(schema *base.Schema, nullable bool) {
 		schema.Nullable = &nullable
 	case "3.1", "3.2":
 		// 3.1+ uses type array: ["string", "null"]
-		if len(schema.Type) > 0 {
+		if len(schema.Type) > 0 && !slices.Contains(schema.Type, "null") {
 			schema.Type = append(schema.Type, "null")
 		}
 	}
 }
 
+// NullableRef wraps a schema reference so that it also allows null, since siblings of $ref
+// are ignored:
+// - OpenAPI 3.0: allOf: [$ref] with nullable: true
+// - OpenAPI 3.1+: oneOf: [$ref, {type: "null"}]
+func (sb *SchemaBuilder) NullableRef(ref *base.SchemaProxy) *base.SchemaProxy {
+	schema := sb.NewSchema()
+	if sb.Is30() {
+		nullable := true
+		schema.AllOf = []*base.SchemaProxy{ref}
+		schema.Nullable = &nullable
+		return base.CreateSchemaProxy(schema)
+	}
+	null := sb.NewSchema()
+	sb.SetType(null, "null")
+	schema.OneOf = []*base.SchemaProxy{ref, base.CreateSchemaProxy(null)}
+	return base.CreateSchemaProxy(schema)
+}
+
 // SetExclusiveMinimum sets exclusive minimum constraint (version-aware).
 // - OpenAPI 3.0: Sets minimum + exclusiveMinimum as boolean (true)
 // - OpenAPI 3.1+: Sets exclusiveMinimum as the numeric value itself
@@ -86,6 +109,48 @@ func (sb *SchemaBuilder) SetExclusiveMaximum(schema *base.Schema, value float64)
 	}
 }
 
+// SetXMLNodeType sets the kind of XML node of an xml object (version-aware):
+// - OpenAPI 3.2: Sets nodeType ("attribute", "text", "cdata" or "none")
+// - OpenAPI 3.0/3.1: Sets attribute: true for attributes; other node types have no equivalent
+func (sb *SchemaBuilder) SetXMLNodeType(xml *base.XML, nodeType string) {
+	if nodeType == "" {
+		return
+	}
+	switch sb.version {
+	case "3.2":
+		xml.NodeType = nodeType
+	default:
+		xml.Attribute = nodeType == "attribute"
+	}
+}
+
+// SetFormat sets the format of a string schema (version-aware):
+// - OpenAPI 3.0: Sets format, including format: binary for file contents and format: byte for base64
+// - OpenAPI 3.1+: Binary contents are described by contentMediaType: application/octet-stream
+// instead, and base64 strings keep format: byte alongside contentEncoding: base64
+func (sb *SchemaBuilder) SetFormat(schema *base.Schema, format string) {
+	switch {
+	case format == "binary" && sb.Is31Plus():
+		sb.SetBinary(schema, "application/octet-stream")
+	case format == "byte" && sb.Is31Plus():
+		schema.Format = format
+		schema.ContentEncoding = "base64"
+	default:
+		schema.Format = format
+	}
+}
+
+// SetBinary describes raw bytes of a media type, such as a file download (version-aware):
+// - OpenAPI 3.0: Sets format: binary
+// - OpenAPI 3.1+: Sets contentMediaType to the media type of the bytes
+func (sb *SchemaBuilder) SetBinary(schema *base.Schema, mediaType string) {
+	if sb.Is30() {
+		schema.Format = "binary"
+		return
+	}
+	schema.ContentMediaType = mediaType
+}
+
 // Is30 returns true if the target version is OpenAPI 3.0.
 func (sb *SchemaBuilder) Is30() bool {
 	return sb.version == "3.0"
@@ -100,3 +165,19 @@ func (sb *SchemaBuilder) Is31Plus() bool {
 func (sb *SchemaBuilder) Version() string {
 	return sb.version
 }
+
+// SetItemSchema sets the schema of each item of a sequential media type, such as a stream (version-aware):
+// - OpenAPI 3.2: Sets itemSchema
+// - OpenAPI 3.0/3.1: Sets schema to the item schema, and x-stream to the stream format
+// (e.g. x-stream: sse), as these versions cannot describe the items of a media type
+func (sb *SchemaBuilder) SetItemSchema(mediaType *v3.MediaType, stream string, item *base.SchemaProxy) {
+	if sb.version == "3.2" {
+		mediaType.ItemSchema = item
+		return
+	}
+	mediaType.Schema = item
+	if mediaType.Extensions == nil {
+		mediaType.Extensions = orderedmap.New[string, *yaml.Node]()
+	}
+	mediaType.Extensions.Set("x-stream", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: stream})
+}
@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/wontaeyang/go-specgen/pkg/resolver"
+	"go.yaml.in/yaml/v4"
+)
+
+// sseMessageEvent is the type of Server-Sent Events sent without an event field
+const sseMessageEvent = "message"
+
+// generateStreamMediaType generates the media type of a stream (@stream), whose schema describes each item:
+// an event of Server-Sent Events streams, or a line (a record for json-seq) of sequential JSON streams
+func (g *Generator) generateStreamMediaType(mediaType *resolver.ResolvedContent, schemas map[string]*resolver.ResolvedSchema) *v3.MediaType {
+	var item *base.SchemaProxy
+	if mediaType.Stream == "sse" {
+		item = g.generateEventsSchema(mediaType, schemas)
+	} else {
+		item = g.generateMediaTypeSchema(mediaType, schemas)
+	}
+
+	result := &v3.MediaType{}
+	g.schemaBuilder.SetItemSchema(result, mediaType.Stream, item)
+	return result
+}
+
+// generateEventsSchema generates the schema of the events of a Server-Sent Events stream
+// Named events (@event) are a oneOf of events, each with its own event type and data schema;
+// the body describes the data of unnamed (message) events.
+func (g *Generator) generateEventsSchema(mediaType *resolver.ResolvedContent, schemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
+	var message *resolver.ResolvedBody
+	if mediaType.Body != nil && mediaType.Body.Schema != "" {
+		message = mediaType.Body
+	}
+	if len(mediaType.Events) == 0 {
+		return g.generateEventSchema("", message, schemas)
+	}
+
+	schema := g.schemaBuilder.NewSchema()
+	for _, event := range mediaType.Events {
+		schema.OneOf = append(schema.OneOf, g.generateEventSchema(event.Name, event.Body, schemas))
+	}
+	if message != nil {
+		schema.OneOf = append(schema.OneOf, g.generateEventSchema(sseMessageEvent, message, schemas))
+	}
+	return base.CreateSchemaProxy(schema)
+}
+
+// generateEventSchema generates the schema of a Server-Sent Event with the fields of the event stream format
+// A named event requires its event field, except message events, whose event field may be omitted.
+func (g *Generator) generateEventSchema(name string, body *resolver.ResolvedBody, schemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
+	event := g.schemaBuilder.NewSchema()
+	g.schemaBuilder.SetType(event, "string")
+	if name != "" {
+		event.Enum = []*yaml.Node{{Kind: yaml.ScalarNode, Value: name}}
+	}
+
+	id := g.schemaBuilder.NewSchema()
+	g.schemaBuilder.SetType(id, "string")
+
+	retry := g.schemaBuilder.NewSchema()
+	g.schemaBuilder.SetType(retry, "integer")
+	minimum := 0.0
+	retry.Minimum = &minimum
+
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("event", base.CreateSchemaProxy(event))
+	props.Set("data", g.generateEventDataSchema(body, schemas))
+	props.Set("id", base.CreateSchemaProxy(id))
+	props.Set("retry", base.CreateSchemaProxy(retry))
+
+	schema := g.schemaBuilder.NewSchema()
+	g.schemaBuilder.SetType(schema, "object")
+	schema.Properties = props
+	schema.Required = []string{"data"}
+	if name != "" && name != sseMessageEvent {
+		schema.Required = append(schema.Required, "event")
+	}
+	return base.CreateSchemaProxy(schema)
+}
+
+// generateEventDataSchema generates the schema of the data field of a Server-Sent Event (version-aware):
+// - OpenAPI 3.0: The schema of the body
+// - OpenAPI 3.1+: A string of JSON, with the schema of the body as its contentSchema
+// Text data (no body, or a string body) is a plain string.
+func (g *Generator) generateEventDataSchema(body *resolver.ResolvedBody, schemas map[string]*resolver.ResolvedSchema) *base.SchemaProxy {
+	if body != nil && !isTextBody(body) && g.schemaBuilder.Is30() {
+		return g.generateBodySchema(body, schemas)
+	}
+
+	data := g.schemaBuilder.NewSchema()
+	g.schemaBuilder.SetType(data, "string")
+	if body != nil && !isTextBody(body) {
+		data.ContentMediaType = "application/json"
+		data.ContentSchema = g.generateBodySchema(body, schemas)
+	}
+	return base.CreateSchemaProxy(data)
+}
+
+// isTextBody reports whether a body is a plain string, sent as is rather than as JSON
+func isTextBody(body *resolver.ResolvedBody) bool {
+	if body.Bind != nil || body.Type == nil {
+		return false
+	}
+	desc := body.Type
+	return desc.Ref == "" && desc.Items == nil && desc.MapValue == nil && len(desc.OneOf) == 0 &&
+		desc.OpenAPIType == "string" && desc.Format == ""
+}
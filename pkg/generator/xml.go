@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/wontaeyang/go-specgen/pkg/resolver"
+)
+
+// generateXML generates the xml object of a schema or field
+// Returns nil when nothing differs from the defaults of the target version.
+func (g *Generator) generateXML(info *resolver.XMLInfo) *base.XML {
+	if info == nil {
+		return nil
+	}
+
+	xml := &base.XML{
+		Name:      info.Name,
+		Namespace: info.Namespace,
+		Prefix:    info.Prefix,
+		Wrapped:   info.Wrapped,
+	}
+	g.schemaBuilder.SetXMLNodeType(xml, info.NodeType)
+
+	if xml.Name == "" && xml.Namespace == "" && xml.Prefix == "" && !xml.Wrapped && !xml.Attribute && xml.NodeType == "" {
+		return nil
+	}
+	return xml
+}
+
+// addFieldXML adds the xml object of a field to its schema, naming the items of arrays
+// Referenced items are wrapped in allOf, as a $ref cannot carry an xml object in OpenAPI 3.0.
+func (g *Generator) addFieldXML(schema *base.Schema, field *resolver.ResolvedField) {
+	if field.XML == nil {
+		return
+	}
+
+	schema.XML = g.generateXML(field.XML)
+
+	if field.XML.ItemsName == "" || schema.Items == nil || schema.Items.A == nil {
+		return
+	}
+	if schema.Items.A.IsReference() {
+		itemSchema := g.schemaBuilder.NewSchema()
+		itemSchema.AllOf = []*base.SchemaProxy{schema.Items.A}
+		itemSchema.XML = &base.XML{Name: field.XML.ItemsName}
+		schema.Items.A = base.CreateSchemaProxy(itemSchema)
+		return
+	}
+	if itemSchema, _ := schema.Items.A.BuildSchema(); itemSchema != nil {
+		itemSchema.XML = &base.XML{Name: field.XML.ItemsName}
+	}
+}
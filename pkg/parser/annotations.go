@@ -34,6 +34,67 @@ type ParsedAnnotation struct {
 	Lines []string
 }
 
+// SyntaxError is a malformed annotation
+// Text is the source text the error is located at in the comment block (e.g. the annotation name).
+type SyntaxError struct {
+	Rule    string
+	Text    string
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Message
+}
+
+// syntaxErrorf returns a syntax error located at text
+func syntaxErrorf(rule, text, format string, args ...any) *SyntaxError {
+	return &SyntaxError{Rule: rule, Text: text, Message: fmt.Sprintf(format, args...)}
+}
+
+// unknownAnnotationError reports an annotation that is not a child of parent, located at text
+// The message hints at where the annotation belongs when another annotation accepts it,
+// or else at the closest child name (e.g. "did you mean @description?").
+func unknownAnnotationError(text, annotationName string, parent *schema.SchemaNode) *SyntaxError {
+	err := syntaxErrorf("unknown-annotation", text, "unknown annotation %s in %s", annotationName, parent.Name)
+
+	root := parent.Root()
+	if root.Name == "root" && root.HasChild(annotationName) {
+		err.Message += fmt.Sprintf("; %s is a top-level annotation", annotationName)
+	} else if parents := shallowest(parent.FindParents(annotationName)); len(parents) > 0 {
+		// Nested blocks of the parent accept it (e.g. @body in @request)
+		err.Message += fmt.Sprintf("; %s belongs in %s", annotationName, joinOr(parents))
+	} else if parents := shallowest(root.FindParents(annotationName)); len(parents) > 0 {
+		err.Message += fmt.Sprintf("; %s belongs in %s", annotationName, joinOr(parents))
+	} else if suggestion := parent.Suggest(annotationName); suggestion != "" {
+		err.Message += fmt.Sprintf("; did you mean %s?", suggestion)
+	}
+	return err
+}
+
+// shallowest returns the annotation paths with the fewest levels
+func shallowest(paths []string) []string {
+	var result []string
+	minDepth := -1
+	for _, path := range paths {
+		depth := strings.Count(path, " > ")
+		switch {
+		case minDepth < 0 || depth < minDepth:
+			result, minDepth = []string{path}, depth
+		case depth == minDepth:
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// joinOr joins items for a message (e.g. "a, b or c")
+func joinOr(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " or " + items[len(items)-1]
+}
+
 // findBlockOpener finds the position of block delimiter " {" or "\t{" in a line.
 // Block delimiters are distinguished from path parameters by the preceding space/tab.
 // Path params like {id} have no space before the brace.
@@ -147,13 +208,13 @@ func ParseBracedBlock(lines []string) ([]string, error) {
 
 		// Safety check
 		if braceDepth < 0 {
-			return nil, fmt.Errorf("unbalanced braces at line: %s", originalLine)
+			return nil, syntaxErrorf("unbalanced-braces", originalLine, "unbalanced braces at line: %s", originalLine)
 		}
 	}
 
 	// If we get here, braces are unbalanced
 	if braceDepth != 0 {
-		return nil, fmt.Errorf("unbalanced braces: depth=%d", braceDepth)
+		return nil, syntaxErrorf("unbalanced-braces", lines[openLineIndex], "unbalanced braces: depth=%d", braceDepth)
 	}
 
 	return content, nil
@@ -200,7 +261,7 @@ func ExtractMetadata(line, annotationName string) string {
 // ParseAnnotationBlock parses an annotation block using the schema
 func ParseAnnotationBlock(lines []string, annotationName string, node *schema.SchemaNode) (*ParsedAnnotation, error) {
 	if node == nil {
-		return nil, fmt.Errorf("unknown annotation: %s", annotationName)
+		return nil, syntaxErrorf("unknown-annotation", annotationName, "unknown annotation: %s", annotationName)
 	}
 
 	result := &ParsedAnnotation{
@@ -274,7 +335,7 @@ func ParseAnnotationBlock(lines []string, annotationName string, node *schema.Sc
 		// Empty block is allowed if schema has no required children
 		if len(content) == 0 {
 			if !node.CanBeEmpty() {
-				return nil, fmt.Errorf("%s cannot be empty (has required children)", annotationName)
+				return nil, syntaxErrorf("empty-block", annotationName, "%s cannot be empty (has required children)", annotationName)
 			}
 			return result, nil
 		}
@@ -330,7 +391,7 @@ func parseChildren(lines []string, parentNode *schema.SchemaNode, result *Parsed
 		// Get schema node for this annotation
 		childNode := parentNode.GetChild(annotationName)
 		if childNode == nil {
-			return fmt.Errorf("unknown annotation %s in %s", annotationName, parentNode.Name)
+			return unknownAnnotationError(line, annotationName, parentNode)
 		}
 
 		// Collect all lines for this annotation
@@ -389,7 +450,7 @@ func parseChildren(lines []string, parentNode *schema.SchemaNode, result *Parsed
 			)
 		} else {
 			if _, exists := result.Children[annotationName]; exists {
-				return fmt.Errorf("%s appears multiple times but is not repeatable", annotationName)
+				return syntaxErrorf("not-repeatable", annotationName, "%s appears multiple times but is not repeatable", annotationName)
 			}
 			result.Children[annotationName] = parsed
 		}
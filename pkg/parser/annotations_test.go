@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/wontaeyang/go-specgen/pkg/schema"
@@ -460,6 +461,72 @@ func TestParseAnnotationBlock_EmptyBlock(t *testing.T) {
 	}
 }
 
+func TestParseAnnotationBlock_UnknownAnnotationHints(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		lines      []string
+		wantError  string
+	}{
+		{
+			name:       "typo",
+			annotation: "@field",
+			lines:      []string{"@field {", "@descripton User name", "}"},
+			wantError:  "unknown annotation @descripton in @field; did you mean @description?",
+		},
+		{
+			name:       "transposed letters",
+			annotation: "@field",
+			lines:      []string{"@field { @minLenght 3 }"},
+			wantError:  "unknown annotation @minLenght in @field; did you mean @minLength?",
+		},
+		{
+			name:       "wrong case",
+			annotation: "@endpoint",
+			lines:      []string{"@endpoint GET /users {", "@operationId listUsers", "@response 200 { @description OK }", "}"},
+			wantError:  "unknown annotation @operationId in @endpoint; did you mean @operationID?",
+		},
+		{
+			name:       "belongs in a nested block",
+			annotation: "@endpoint",
+			lines:      []string{"@endpoint GET /users {", "@body []User", "@response 200 { @description OK }", "}"},
+			wantError:  "unknown annotation @body in @endpoint; @body belongs in @request or @response",
+		},
+		{
+			name:       "belongs in another annotation",
+			annotation: "@api",
+			lines:      []string{"@api {", "@title Users", "@version 1.0.0", "@format uuid", "}"},
+			wantError:  "unknown annotation @format in @api; @format belongs in @field or @schema",
+		},
+		{
+			name:       "top-level annotation",
+			annotation: "@endpoint",
+			lines:      []string{"@endpoint GET /users {", "@schema", "}"},
+			wantError:  "unknown annotation @schema in @endpoint; @schema is a top-level annotation",
+		},
+		{
+			name:       "no close name",
+			annotation: "@field",
+			lines:      []string{"@field { @xyzzy 3 }"},
+			wantError:  "unknown annotation @xyzzy in @field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAnnotationBlock(tt.lines, tt.annotation, schema.AnnotationSchema.GetChild(tt.annotation))
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParseAnnotationBlock() error = %v, want a syntax error", err)
+			}
+			if syntaxErr.Rule != "unknown-annotation" || syntaxErr.Message != tt.wantError {
+				t.Errorf("error = %q [%s], want %q [unknown-annotation]", syntaxErr.Message, syntaxErr.Rule, tt.wantError)
+			}
+		})
+	}
+}
+
 func TestParseAnnotationBlock_WithMetadata(t *testing.T) {
 	endpointNode := schema.AnnotationSchema.GetChild("@endpoint")
 	lines := []string{
@@ -562,11 +629,12 @@ func TestParseAnnotationBlock_InlineResponse(t *testing.T) {
 		t.Errorf("Metadata = %q, want %q", parsed.Metadata, "200")
 	}
 
-	// Check @body child
-	body, ok := parsed.Children["@body"]
-	if !ok {
-		t.Fatal("@body child not found")
+	// Check @body child (repeatable)
+	bodies := parsed.GetRepeatedChildren("@body")
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 @body, got %d", len(bodies))
 	}
+	body := bodies[0]
 	if body.Value != "User" {
 		t.Errorf("@body value = %q, want %q", body.Value, "User")
 	}
@@ -617,10 +685,11 @@ func TestParseAnnotationBlock_NestedInlineResponse(t *testing.T) {
 		t.Errorf("resp200.Metadata = %q, want %q", resp200.Metadata, "200")
 	}
 
-	body200, ok := resp200.Children["@body"]
-	if !ok {
-		t.Fatal("resp200 @body not found")
+	bodies200 := resp200.GetRepeatedChildren("@body")
+	if len(bodies200) != 1 {
+		t.Fatalf("resp200 has %d @body, want 1", len(bodies200))
 	}
+	body200 := bodies200[0]
 	if body200.Value != "User" {
 		t.Errorf("resp200 @body.Value = %q, want %q", body200.Value, "User")
 	}
@@ -3,10 +3,16 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	goparser "go/parser"
 	"go/token"
 	"go/types"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"unicode"
 
+	"github.com/wontaeyang/go-specgen/pkg/diagnostic"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -17,6 +23,9 @@ type CommentBlock struct {
 
 	// Position is the file position for error reporting
 	Position token.Position
+
+	// Positions are the file positions of the text of each line, parallel to Lines
+	Positions []token.Position
 }
 
 // PackageComments represents all comments extracted from a package
@@ -24,6 +33,9 @@ type PackageComments struct {
 	// Name is the package name
 	Name string
 
+	// PkgPath is the package import path
+	PkgPath string
+
 	// Pkg is the loaded package (needed for type resolution of inline declarations)
 	Pkg *packages.Package
 
@@ -31,19 +43,28 @@ type PackageComments struct {
 	PackageComments *CommentBlock
 
 	// Struct-level comments (for @schema, @path, @query, @header, @cookie)
-	StructComments map[string]*CommentBlock // Key: struct name
+	StructComments map[string]*CommentBlock // Key: type name (structs, aliases and named non-struct types)
 
 	// Field-level comments (for @field)
 	FieldComments map[string]map[string]*CommentBlock // Key: struct name -> field name
 
+	// Field-level comments of every struct, including anonymous structs at any depth (for @field)
+	FieldCommentsAt map[token.Pos]*CommentBlock // Key: position of the field name
+
 	// Function-level comments (for @endpoint)
 	FunctionComments map[string]*CommentBlock // Key: function name
 
+	// Package-level constant comments (for enum value descriptions)
+	ConstComments map[string]*CommentBlock // Key: constant name
+
 	// TypeInfo contains metadata about type declarations
 	TypeInfo map[string]*TypeDeclInfo // Key: type name
 
 	// FuncInlines contains inline struct declarations within function bodies
 	FuncInlines map[string]*FuncInlineInfo // Key: function name
+
+	// Suppressions are the specgen:ignore comments silencing warnings
+	Suppressions []*diagnostic.Suppression
 }
 
 // FuncInlineInfo contains inline declarations extracted from a function body
@@ -94,42 +115,122 @@ type InlineStructInfo struct {
 // TypeDeclInfo contains metadata about a type declaration
 type TypeDeclInfo struct {
 	Name        string
-	IsGeneric   bool   // Has type parameters (e.g., type Foo[T any] struct{})
-	IsTypeAlias bool   // Is a type alias (e.g., type Bar = Foo[Baz])
-	AliasOf     string // For type aliases, the aliased type (e.g., "Foo[Baz]")
+	IsGeneric   bool           // Has type parameters (e.g., type Foo[T any] struct{})
+	IsTypeAlias bool           // Is a type alias (e.g., type Bar = Foo[Baz])
+	AliasOf     string         // For type aliases, the aliased type (e.g., "Foo[Baz]")
+	Position    token.Position // Position of the type declaration
 }
 
-// ExtractComments extracts all comment blocks from a Go package
-func ExtractComments(packagePath string) (*PackageComments, error) {
-	// Load the package with documentation
-	cfg := &packages.Config{
-		Mode: packages.NeedName |
-			packages.NeedFiles |
-			packages.NeedSyntax |
-			packages.NeedTypes |
-			packages.NeedTypesInfo,
+// loadMode is the package loading mode shared by the parser and resolver.
+// Dependencies are loaded as well so that types declared in imported packages can be resolved.
+const loadMode = packages.NeedName |
+	packages.NeedFiles |
+	packages.NeedSyntax |
+	packages.NeedTypes |
+	packages.NeedTypesInfo |
+	packages.NeedImports |
+	packages.NeedDeps |
+	packages.NeedModule
+
+// graphMode lists the import graph of packages without parsing or type checking them
+const graphMode = packages.NeedName |
+	packages.NeedImports |
+	packages.NeedModule
+
+// LoadPackages loads every package matching the given patterns (e.g. "./api/...")
+// Only the files of the main module are parsed in full; dependencies outside it are
+// parsed without comments or function bodies, which is all type checking needs.
+// Packages are returned sorted by import path so results are deterministic
+func LoadPackages(patterns ...string) ([]*packages.Package, error) {
+	graph, err := packages.Load(&packages.Config{Mode: graphMode}, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load package: %w", err)
+	}
+
+	if len(graph) == 0 {
+		return nil, fmt.Errorf("no packages found at path: %s", strings.Join(patterns, ", "))
 	}
 
-	pkgs, err := packages.Load(cfg, packagePath)
+	cfg := &packages.Config{Mode: loadMode, ParseFile: dependencyParser(mainModuleDirs(graph))}
+
+	pkgs, err := packages.Load(cfg, patterns...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load package: %w", err)
 	}
 
-	if len(pkgs) == 0 {
-		return nil, fmt.Errorf("no packages found at path: %s", packagePath)
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("package %s has errors: %v", pkg.PkgPath, pkg.Errors)
+		}
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].PkgPath < pkgs[j].PkgPath
+	})
+
+	return pkgs, nil
+}
+
+// mainModuleDirs returns the directories of the main modules (several in a workspace)
+// found in the import graph of the given packages
+func mainModuleDirs(roots []*packages.Package) []string {
+	var dirs []string
+	packages.Visit(roots, nil, func(pkg *packages.Package) {
+		if pkg.Module != nil && pkg.Module.Main && pkg.Module.Dir != "" && !slices.Contains(dirs, pkg.Module.Dir) {
+			dirs = append(dirs, pkg.Module.Dir)
+		}
+	})
+	return dirs
+}
+
+// dependencyParser returns a ParseFile function that parses files inside the given module
+// directories in full, and files of other modules without comments and function bodies
+func dependencyParser(moduleDirs []string) func(*token.FileSet, string, []byte) (*ast.File, error) {
+	return func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+		for _, dir := range moduleDirs {
+			if strings.HasPrefix(filename, dir+string(filepath.Separator)) {
+				return goparser.ParseFile(fset, filename, src, goparser.AllErrors|goparser.ParseComments)
+			}
+		}
+
+		file, err := goparser.ParseFile(fset, filename, src, goparser.SkipObjectResolution)
+		if file != nil {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok {
+					fn.Body = nil
+				}
+			}
+		}
+		return file, err
+	}
+}
+
+// ExtractComments extracts all comment blocks from a Go package
+// The path must match exactly one package; use LoadPackages and ExtractPackageComments for patterns
+func ExtractComments(packagePath string) (*PackageComments, error) {
+	pkgs, err := LoadPackages(packagePath)
+	if err != nil {
+		return nil, err
 	}
 
-	pkg := pkgs[0]
-	if len(pkg.Errors) > 0 {
-		return nil, fmt.Errorf("package has errors: %v", pkg.Errors)
+	if len(pkgs) > 1 {
+		return nil, fmt.Errorf("path %s matches %d packages, expected one", packagePath, len(pkgs))
 	}
 
+	return ExtractPackageComments(pkgs[0]), nil
+}
+
+// ExtractPackageComments extracts all comment blocks from an already loaded package
+func ExtractPackageComments(pkg *packages.Package) *PackageComments {
 	comments := &PackageComments{
 		Name:             pkg.Name,
+		PkgPath:          pkg.PkgPath,
 		Pkg:              pkg,
 		StructComments:   make(map[string]*CommentBlock),
 		FieldComments:    make(map[string]map[string]*CommentBlock),
+		FieldCommentsAt:  make(map[token.Pos]*CommentBlock),
 		FunctionComments: make(map[string]*CommentBlock),
+		ConstComments:    make(map[string]*CommentBlock),
 		TypeInfo:         make(map[string]*TypeDeclInfo),
 		FuncInlines:      make(map[string]*FuncInlineInfo),
 	}
@@ -143,6 +244,39 @@ func ExtractComments(packagePath string) (*PackageComments, error) {
 			comments.PackageComments = extractCommentBlock(fset, file.Doc)
 		}
 
+		// Extract package-level constant comments
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+
+				// Prefer the spec's doc comment, then a trailing line comment,
+				// then the declaration's doc comment for unparenthesized consts
+				doc := valueSpec.Doc
+				if doc == nil {
+					doc = valueSpec.Comment
+				}
+				if doc == nil && !genDecl.Lparen.IsValid() {
+					doc = genDecl.Doc
+				}
+				if doc == nil {
+					continue
+				}
+
+				for _, name := range valueSpec.Names {
+					comments.ConstComments[name.Name] = extractCommentBlock(fset, doc)
+				}
+			}
+		}
+
+		comments.Suppressions = append(comments.Suppressions, extractSuppressions(fset, file)...)
+
 		// Traverse AST nodes
 		ast.Inspect(file, func(n ast.Node) bool {
 			switch node := n.(type) {
@@ -164,6 +298,7 @@ func ExtractComments(packagePath string) (*PackageComments, error) {
 								Name:        typeName,
 								IsGeneric:   isGeneric,
 								IsTypeAlias: isTypeAlias,
+								Position:    fset.Position(typeSpec.Pos()),
 							}
 
 							// For type aliases, extract the aliased type
@@ -173,37 +308,24 @@ func ExtractComments(packagePath string) (*PackageComments, error) {
 
 							comments.TypeInfo[typeName] = typeInfo
 
-							// Handle struct declarations (including generic ones)
-							if _, ok := typeSpec.Type.(*ast.StructType); ok {
-								// Extract struct-level comment
-								if node.Doc != nil {
-									comments.StructComments[typeName] = extractCommentBlock(fset, node.Doc)
-								} else if typeSpec.Doc != nil {
-									comments.StructComments[typeName] = extractCommentBlock(fset, typeSpec.Doc)
-								}
+							// Extract type-level comment (structs, type aliases and named non-struct types)
+							if node.Doc != nil {
+								comments.StructComments[typeName] = extractCommentBlock(fset, node.Doc)
+							} else if typeSpec.Doc != nil {
+								comments.StructComments[typeName] = extractCommentBlock(fset, typeSpec.Doc)
+							}
 
-								// Extract field-level comments
-								if structType, ok := typeSpec.Type.(*ast.StructType); ok {
-									comments.FieldComments[typeName] = make(map[string]*CommentBlock)
+							// Extract field-level comments of struct declarations (including generic ones)
+							if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+								comments.FieldComments[typeName] = make(map[string]*CommentBlock)
 
-									for _, field := range structType.Fields.List {
-										if field.Doc != nil && len(field.Names) > 0 {
-											fieldName := field.Names[0].Name
-											comments.FieldComments[typeName][fieldName] = extractCommentBlock(fset, field.Doc)
-										}
+								for _, field := range structType.Fields.List {
+									if field.Doc != nil && len(field.Names) > 0 {
+										fieldName := field.Names[0].Name
+										comments.FieldComments[typeName][fieldName] = extractCommentBlock(fset, field.Doc)
 									}
 								}
 							}
-
-							// Handle type aliases that reference other types
-							if isTypeAlias {
-								// Also extract comment for type alias
-								if node.Doc != nil {
-									comments.StructComments[typeName] = extractCommentBlock(fset, node.Doc)
-								} else if typeSpec.Doc != nil {
-									comments.StructComments[typeName] = extractCommentBlock(fset, typeSpec.Doc)
-								}
-							}
 						}
 					}
 				}
@@ -221,13 +343,21 @@ func ExtractComments(packagePath string) (*PackageComments, error) {
 						comments.FuncInlines[funcName] = inlines
 					}
 				}
+
+			case *ast.StructType:
+				// Fields of anonymous structs are looked up by position
+				for _, field := range node.Fields.List {
+					if field.Doc != nil && len(field.Names) > 0 {
+						comments.FieldCommentsAt[field.Names[0].Pos()] = extractCommentBlock(fset, field.Doc)
+					}
+				}
 			}
 
 			return true
 		})
 	}
 
-	return comments, nil
+	return comments
 }
 
 // extractCommentBlock extracts lines from a comment group
@@ -237,6 +367,7 @@ func extractCommentBlock(fset *token.FileSet, cg *ast.CommentGroup) *CommentBloc
 	}
 
 	lines := make([]string, 0, len(cg.List))
+	positions := make([]token.Position, 0, len(cg.List))
 	var position token.Position
 
 	for i, comment := range cg.List {
@@ -251,9 +382,17 @@ func extractCommentBlock(fset *token.FileSet, cg *ast.CommentGroup) *CommentBloc
 		text = strings.TrimSuffix(text, "*/")
 		text = strings.TrimSpace(text)
 
-		// Only add non-empty lines
+		// Only add non-empty lines, leaving out specgen:ignore comments
+		if _, ok := parseIgnoreDirective(comment.Text); ok {
+			continue
+		}
 		if text != "" {
+			linePosition := fset.Position(comment.Pos())
+			offset := strings.Index(comment.Text, text)
+			linePosition.Offset += offset
+			linePosition.Column += offset
 			lines = append(lines, text)
+			positions = append(positions, linePosition)
 		}
 	}
 
@@ -262,11 +401,87 @@ func extractCommentBlock(fset *token.FileSet, cg *ast.CommentGroup) *CommentBloc
 	}
 
 	return &CommentBlock{
-		Lines:    lines,
-		Position: position,
+		Lines:     lines,
+		Position:  position,
+		Positions: positions,
 	}
 }
 
+// ignoreDirective starts a comment that silences warnings: // specgen:ignore rule-id[,rule-id]
+const ignoreDirective = "specgen:ignore"
+
+// parseIgnoreDirective returns the rules of a specgen:ignore comment
+// The rules are empty when the comment silences every rule.
+func parseIgnoreDirective(comment string) ([]string, bool) {
+	text, ok := strings.CutPrefix(comment, "//")
+	if !ok {
+		return nil, false
+	}
+	rest, ok := strings.CutPrefix(strings.TrimSpace(text), ignoreDirective)
+	if !ok || (rest != "" && !unicode.IsSpace(rune(rest[0]))) {
+		return nil, false
+	}
+	return strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}), true
+}
+
+// extractSuppressions returns the specgen:ignore comments of a file
+// A comment in the doc comment of the file, a declaration or a field covers all of it,
+// a comment trailing a field or spec covers its line, and other comments cover their line and the next one.
+func extractSuppressions(fset *token.FileSet, file *ast.File) []*diagnostic.Suppression {
+	// Nodes documented by each comment group
+	documented := make(map[*ast.CommentGroup]ast.Node)
+	document := func(cg *ast.CommentGroup, node ast.Node) {
+		if cg != nil {
+			documented[cg] = node
+		}
+	}
+	document(file.Doc, file)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GenDecl:
+			document(node.Doc, node)
+		case *ast.FuncDecl:
+			document(node.Doc, node)
+		case *ast.TypeSpec:
+			document(node.Doc, node)
+			document(node.Comment, node)
+		case *ast.ValueSpec:
+			document(node.Doc, node)
+			document(node.Comment, node)
+		case *ast.Field:
+			document(node.Doc, node)
+			document(node.Comment, node)
+		}
+		return true
+	})
+
+	var suppressions []*diagnostic.Suppression
+	for _, cg := range file.Comments {
+		for _, comment := range cg.List {
+			rules, ok := parseIgnoreDirective(comment.Text)
+			if !ok {
+				continue
+			}
+
+			position := fset.Position(comment.Pos())
+			suppression := &diagnostic.Suppression{
+				Filename: position.Filename,
+				FromLine: position.Line,
+				ToLine:   position.Line + 1,
+				Rules:    rules,
+			}
+			if node, ok := documented[cg]; ok {
+				suppression.FromLine = fset.Position(cg.Pos()).Line
+				suppression.ToLine = fset.Position(node.End()).Line
+			}
+			suppressions = append(suppressions, suppression)
+		}
+	}
+	return suppressions
+}
+
 // GetStructComment returns the comment block for a struct
 func (pc *PackageComments) GetStructComment(structName string) *CommentBlock {
 	return pc.StructComments[structName]
@@ -323,6 +538,26 @@ func (cb *CommentBlock) GetAnnotationLines() []string {
 	return result
 }
 
+// PositionOf returns the file position of the first occurrence of text in the block
+// Falls back to the position of the block when the text is not found.
+func (cb *CommentBlock) PositionOf(text string) token.Position {
+	if cb == nil {
+		return token.Position{}
+	}
+
+	if text != "" {
+		for i, line := range cb.Lines {
+			if idx := strings.Index(line, text); idx >= 0 && i < len(cb.Positions) {
+				position := cb.Positions[i]
+				position.Offset += idx
+				position.Column += idx
+				return position
+			}
+		}
+	}
+	return cb.Position
+}
+
 // String returns the comment block as a formatted string for debugging
 func (cb *CommentBlock) String() string {
 	if cb == nil {
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -192,6 +193,108 @@ func TestCommentBlock_String(t *testing.T) {
 	}
 }
 
+func TestCommentBlock_PositionOf(t *testing.T) {
+	comments, err := ExtractComments("./testdata")
+	if err != nil {
+		t.Fatalf("ExtractComments() error = %v", err)
+	}
+	cb := comments.GetFunctionComment("GetUser")
+
+	tests := []struct {
+		text       string
+		wantLine   int
+		wantColumn int
+	}{
+		{text: "@endpoint", wantLine: 39, wantColumn: 4},
+		{text: "@summary", wantLine: 40, wantColumn: 6},
+		{text: "UserIDPath", wantLine: 41, wantColumn: 12},
+		{text: "@body", wantLine: 44, wantColumn: 8},
+		// Text that is not in the block falls back to the block position
+		{text: "@deprecated", wantLine: 39, wantColumn: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			pos := cb.PositionOf(tt.text)
+			if !strings.HasSuffix(pos.Filename, "sample.go") || pos.Line != tt.wantLine || pos.Column != tt.wantColumn {
+				t.Errorf("PositionOf(%q) = %s, want sample.go:%d:%d", tt.text, pos, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+
+	// Test nil comment block
+	var nilCB *CommentBlock
+	if pos := nilCB.PositionOf("@api"); pos.IsValid() {
+		t.Errorf("nil CommentBlock.PositionOf() = %s, want an invalid position", pos)
+	}
+}
+
+func TestExtractSuppressions(t *testing.T) {
+	comments, err := ExtractComments("./testdata/warnings")
+	if err != nil {
+		t.Fatalf("ExtractComments() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		fromLine int
+		toLine   int
+		rules    []string
+	}{
+		{name: "field doc comment", fromLine: 11, toLine: 12, rules: []string{"unsupported-validate-rule"}},
+		{name: "trailing comment", fromLine: 14, toLine: 14, rules: []string{"json-marshaler"}},
+		{name: "declaration doc comment without rules", fromLine: 17, toLine: 21, rules: nil},
+	}
+
+	if len(comments.Suppressions) != len(tests) {
+		t.Fatalf("Suppressions = %d, want %d", len(comments.Suppressions), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := comments.Suppressions[i]
+			if !strings.HasSuffix(got.Filename, "warnings.go") || got.FromLine != tt.fromLine || got.ToLine != tt.toLine {
+				t.Errorf("suppression = %s:%d-%d, want warnings.go:%d-%d", got.Filename, got.FromLine, got.ToLine, tt.fromLine, tt.toLine)
+			}
+			if strings.Join(got.Rules, ",") != strings.Join(tt.rules, ",") {
+				t.Errorf("rules = %v, want %v", got.Rules, tt.rules)
+			}
+		})
+	}
+
+	// specgen:ignore comments are not part of the annotations
+	if comment := comments.GetFieldComment("User", "Website"); comment != nil {
+		t.Errorf("Website comment = %q, want none", comment.String())
+	}
+	if comment := comments.GetStructComment("Profile"); comment == nil || comment.String() != "@schema" {
+		t.Errorf("Profile comment = %q, want @schema", comment.String())
+	}
+}
+
+func TestParseIgnoreDirective(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    []string
+		wantOK  bool
+	}{
+		{comment: "// specgen:ignore json-marshaler", want: []string{"json-marshaler"}, wantOK: true},
+		{comment: "//specgen:ignore json-marshaler, unknown-parameter", want: []string{"json-marshaler", "unknown-parameter"}, wantOK: true},
+		{comment: "// specgen:ignore json-marshaler unknown-parameter", want: []string{"json-marshaler", "unknown-parameter"}, wantOK: true},
+		{comment: "// specgen:ignore", want: nil, wantOK: true},
+		{comment: "// specgen:ignored", wantOK: false},
+		{comment: "// @field { @description specgen:ignore }", wantOK: false},
+		{comment: "/* specgen:ignore json-marshaler */", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			got, ok := parseIgnoreDirective(tt.comment)
+			if ok != tt.wantOK || strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("parseIgnoreDirective(%q) = %v, %v, want %v, %v", tt.comment, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestPackageComments_Getters(t *testing.T) {
 	pc := &PackageComments{
 		StructComments: map[string]*CommentBlock{
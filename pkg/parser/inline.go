@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/wontaeyang/go-specgen/pkg/schema"
@@ -55,7 +54,7 @@ func ParseInlineAnnotation(line, annotationName string, node *schema.SchemaNode)
 	closeIdx := strings.LastIndex(line, "}")
 
 	if openIdx == -1 || closeIdx == -1 || openIdx >= closeIdx {
-		return nil, fmt.Errorf("invalid inline format for %s", annotationName)
+		return nil, syntaxErrorf("invalid-inline", annotationName, "invalid inline format for %s", annotationName)
 	}
 
 	content := line[openIdx+1 : closeIdx]
@@ -64,7 +63,7 @@ func ParseInlineAnnotation(line, annotationName string, node *schema.SchemaNode)
 	// Empty content is allowed if schema has no required children
 	if content == "" {
 		if !node.CanBeEmpty() {
-			return nil, fmt.Errorf("%s cannot be empty (has required children)", annotationName)
+			return nil, syntaxErrorf("empty-block", annotationName, "%s cannot be empty (has required children)", annotationName)
 		}
 		return result, nil
 	}
@@ -118,14 +117,14 @@ func parseInlineChildren(content string, parentNode *schema.SchemaNode, result *
 		// Get schema node
 		childNode := parentNode.GetChild(annotationName)
 		if childNode == nil {
-			return fmt.Errorf("unknown annotation %s in %s", annotationName, parentNode.Name)
+			return unknownAnnotationError(annotationName, annotationName, parentNode)
 		}
 
 		// Check if sub-command has sub-blocks (not allowed in inline)
 		if childNode.Type == schema.SubCommand && len(childNode.Children) > 0 {
 			// Check if value contains unescaped braces (sub-block)
 			if ContainsUnescapedBrace(value) {
-				return fmt.Errorf("sub-commands with sub-blocks cannot be inlined: %s", annotationName)
+				return syntaxErrorf("invalid-inline", annotationName, "sub-commands with sub-blocks cannot be inlined: %s", annotationName)
 			}
 		}
 
@@ -152,7 +151,7 @@ func parseInlineChildren(content string, parentNode *schema.SchemaNode, result *
 			)
 		} else {
 			if _, exists := result.Children[annotationName]; exists {
-				return fmt.Errorf("%s appears multiple times but is not repeatable", annotationName)
+				return syntaxErrorf("not-repeatable", annotationName, "%s appears multiple times but is not repeatable", annotationName)
 			}
 			result.Children[annotationName] = parsed
 		}
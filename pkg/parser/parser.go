@@ -1,17 +1,25 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/wontaeyang/go-specgen/pkg/diagnostic"
 	"github.com/wontaeyang/go-specgen/pkg/schema"
+	"golang.org/x/tools/go/packages"
 )
 
-// Parser orchestrates the parsing of a Go package into a ParsedPackage
+// Parser orchestrates the parsing of one or more Go packages into a ParsedPackage
 type Parser struct {
 	packagePath string
+	patterns    []string
+	apiPackage  string
 	comments    *PackageComments
+	allComments []*PackageComments
 }
 
 // NewParser creates a new parser for the given package path
@@ -21,49 +29,288 @@ func NewParser(packagePath string) *Parser {
 	}
 }
 
-// Comments returns the extracted package comments
+// NewMultiParser creates a parser that loads every package matching the given patterns
+// (e.g. "./api/...") and merges them into a single ParsedPackage.
+// apiPackage designates the package that holds the @api annotation, either as an import
+// path or a directory. When empty, the only loaded package with package-level @api is used.
+func NewMultiParser(patterns []string, apiPackage string) *Parser {
+	return &Parser{
+		patterns:   patterns,
+		apiPackage: apiPackage,
+	}
+}
+
+// Comments returns the extracted comments of the @api package
 // This should be called after Parse() to access inline declarations and the loaded package
 func (p *Parser) Comments() *PackageComments {
 	return p.comments
 }
 
-// Parse parses the package and returns a ParsedPackage
+// AllComments returns the extracted comments of every loaded package, sorted by import path
+// This should be called after Parse() and passed to the resolver when parsing multiple packages
+func (p *Parser) AllComments() []*PackageComments {
+	return p.allComments
+}
+
+// Suppressions returns the specgen:ignore comments of every loaded package
+// This should be called after Parse() to filter the warnings of later stages
+func (p *Parser) Suppressions() []*diagnostic.Suppression {
+	var suppressions []*diagnostic.Suppression
+	for _, comments := range p.allComments {
+		suppressions = append(suppressions, comments.Suppressions...)
+	}
+	return suppressions
+}
+
+// Parse parses the package(s) and returns a ParsedPackage
+// Declarations with a malformed annotation are skipped and parsing goes on, so that every problem
+// is reported at once: the result then holds the other declarations and the error is a
+// diagnostic.MultiError listing the problems. The result is nil when the packages cannot be loaded.
 func (p *Parser) Parse() (*ParsedPackage, error) {
+	patterns := p.patterns
+	if len(patterns) == 0 {
+		patterns = []string{p.packagePath}
+	}
+
 	// Step 1: Extract comments from AST
-	comments, err := ExtractComments(p.packagePath)
+	pkgs, err := LoadPackages(patterns...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract comments: %w", err)
 	}
-	p.comments = comments
+
+	p.allComments = make([]*PackageComments, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		p.allComments = append(p.allComments, ExtractPackageComments(pkg))
+	}
+
+	apiComments, err := p.selectAPIPackage()
+	if err != nil {
+		return nil, err
+	}
 
 	result := &ParsedPackage{
-		PackageName: comments.Name,
+		PackageName: apiComments.Name,
 		Schemas:     make(map[string]*Schema),
 		Parameters:  make(map[string]*Parameter),
 		Endpoints:   make([]*Endpoint, 0),
 	}
 
-	// Step 2: Parse @api annotation
-	if err := p.parseAPI(result); err != nil {
-		return nil, fmt.Errorf("failed to parse @api: %w", err)
+	// Step 2: Parse @api annotation from the designated package
+	var errs []error
+	p.comments = apiComments
+	errs = append(errs, p.parseAPI(result))
+
+	// Steps 3-5 run once per package, then each package is merged into the result
+	var schemas []*Schema
+	for _, comments := range p.allComments {
+		p.comments = comments
+
+		pkgResult := &ParsedPackage{
+			PackageName: comments.Name,
+			Schemas:     make(map[string]*Schema),
+			Parameters:  make(map[string]*Parameter),
+			Endpoints:   make([]*Endpoint, 0),
+		}
+
+		// Step 3: Parse @schema annotations
+		errs = append(errs, p.parseSchemas(pkgResult))
+
+		// Step 4: Parse parameter structs (@path, @query, @header, @cookie)
+		errs = append(errs, p.parseParameters(pkgResult))
+
+		// Step 5: Parse @endpoint annotations
+		errs = append(errs, p.parseEndpoints(pkgResult))
+
+		schemas = append(schemas, sortedSchemas(pkgResult.Schemas)...)
+		errs = append(errs, mergeParsedPackage(result, pkgResult)...)
+	}
+
+	// Step 6: Parse @schema annotations from imported packages outside the scan
+	// so that fields and bodies can reference shared types like models.User
+	for _, pkg := range importedPackages(pkgs) {
+		comments := ExtractPackageComments(pkg)
+		p.comments = comments
+
+		pkgResult := &ParsedPackage{
+			PackageName: comments.Name,
+			Schemas:     make(map[string]*Schema),
+		}
+		errs = append(errs, p.parseSchemas(pkgResult))
+		if len(pkgResult.Schemas) == 0 {
+			continue
+		}
+
+		p.allComments = append(p.allComments, comments)
+		schemas = append(schemas, sortedSchemas(pkgResult.Schemas)...)
+	}
+	p.comments = apiComments
+
+	// Step 7: Name schema components, qualifying names declared in more than one package
+	for _, s := range assignSchemaNames(schemas) {
+		result.Schemas[s.Name] = s
+	}
+
+	return result, diagnostic.Join(errs...)
+}
+
+// selectAPIPackage returns the comments of the package that holds the @api annotation
+func (p *Parser) selectAPIPackage() (*PackageComments, error) {
+	if p.apiPackage != "" {
+		apiDir, _ := filepath.Abs(p.apiPackage)
+		for _, comments := range p.allComments {
+			if comments.PkgPath == p.apiPackage || packageDir(comments) == apiDir {
+				return comments, nil
+			}
+		}
+		return nil, fmt.Errorf("@api package %s is not among the loaded packages", p.apiPackage)
 	}
 
-	// Step 3: Parse @schema annotations
-	if err := p.parseSchemas(result); err != nil {
-		return nil, fmt.Errorf("failed to parse schemas: %w", err)
+	if len(p.allComments) == 1 {
+		return p.allComments[0], nil
 	}
 
-	// Step 4: Parse parameter structs (@path, @query, @header, @cookie)
-	if err := p.parseParameters(result); err != nil {
-		return nil, fmt.Errorf("failed to parse parameters: %w", err)
+	var candidates []*PackageComments
+	for _, comments := range p.allComments {
+		if comments.PackageComments.HasAnnotation("@api") {
+			candidates = append(candidates, comments)
+		}
 	}
 
-	// Step 5: Parse @endpoint annotations
-	if err := p.parseEndpoints(result); err != nil {
-		return nil, fmt.Errorf("failed to parse endpoints: %w", err)
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no package-level @api annotation found in %d loaded packages", len(p.allComments))
+	case 1:
+		return candidates[0], nil
+	default:
+		paths := make([]string, len(candidates))
+		for i, c := range candidates {
+			paths[i] = c.PkgPath
+		}
+		return nil, fmt.Errorf("multiple packages declare @api (%s); designate one explicitly", strings.Join(paths, ", "))
 	}
+}
 
-	return result, nil
+// packageDir returns the absolute directory of a loaded package, or "" if unknown
+func packageDir(comments *PackageComments) string {
+	if comments.Pkg == nil || len(comments.Pkg.GoFiles) == 0 {
+		return ""
+	}
+	return filepath.Dir(comments.Pkg.GoFiles[0])
+}
+
+// mergeParsedPackage merges parameters and endpoints from src into dst
+// Schemas are merged separately by assignSchemaNames
+// Returns an error for every parameter name already declared by another package
+func mergeParsedPackage(dst, src *ParsedPackage) []error {
+	var collisions []error
+
+	for name, param := range src.Parameters {
+		if existing, ok := dst.Parameters[name]; ok {
+			collisions = append(collisions, diagnostic.Errorf(param.Position, "duplicate-parameter", "",
+				"parameter %s is declared in both %s and %s", name, existing.PkgPath, param.PkgPath))
+			continue
+		}
+		dst.Parameters[name] = param
+	}
+
+	dst.Endpoints = append(dst.Endpoints, src.Endpoints...)
+
+	return collisions
+}
+
+// importedPackages returns the packages of the main module transitively imported by the
+// loaded packages, excluding the loaded packages themselves, sorted by import path
+// The walk stops at other modules: their annotations never add schemas to the spec.
+func importedPackages(roots []*packages.Package) []*packages.Package {
+	seen := make(map[string]bool)
+	for _, pkg := range roots {
+		seen[pkg.PkgPath] = true
+	}
+
+	var result []*packages.Package
+	queue := append([]*packages.Package(nil), roots...)
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+
+		for path, imp := range pkg.Imports {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+
+			// Standard library packages have no module, and dependencies are outside the main module
+			if imp.Module == nil || !imp.Module.Main {
+				continue
+			}
+
+			result = append(result, imp)
+			queue = append(queue, imp)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PkgPath < result[j].PkgPath
+	})
+
+	return result
+}
+
+// sortedSchemas returns the schemas of a map sorted by name
+func sortedSchemas(schemas map[string]*Schema) []*Schema {
+	result := make([]*Schema, 0, len(schemas))
+	for _, s := range schemas {
+		result = append(result, s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// assignSchemaNames sets the component name of every schema
+// A type name declared in a single package is used as-is (e.g. "User"). A type name
+// declared in several packages is qualified with the trailing elements of each package's
+// import path, using as few elements as needed to tell them apart (e.g. "models.User"
+// and "admin.User", or "v1.models.User" and "v2.models.User").
+func assignSchemaNames(schemas []*Schema) []*Schema {
+	byName := make(map[string][]*Schema)
+	for _, s := range schemas {
+		byName[s.Name] = append(byName[s.Name], s)
+	}
+
+	for name, group := range byName {
+		if len(group) == 1 {
+			continue
+		}
+
+		for depth := 1; ; depth++ {
+			names := make(map[string]bool, len(group))
+			for _, s := range group {
+				names[qualifiedSchemaName(s.PkgPath, name, depth)] = true
+			}
+			if len(names) == len(group) || depth > strings.Count(group[0].PkgPath, "/")+1 {
+				for _, s := range group {
+					s.Name = qualifiedSchemaName(s.PkgPath, name, depth)
+				}
+				break
+			}
+		}
+	}
+
+	return schemas
+}
+
+// qualifiedSchemaName prefixes a type name with the last depth elements of an import path
+// e.g. ("github.com/acme/api/models", "User", 1) -> "models.User"
+func qualifiedSchemaName(pkgPath, typeName string, depth int) string {
+	elems := strings.Split(pkgPath, "/")
+	if depth < len(elems) {
+		elems = elems[len(elems)-depth:]
+	}
+	return strings.Join(append(elems, typeName), ".")
 }
 
 // parseAPI parses the @api annotation from package-level comments
@@ -72,9 +319,10 @@ func (p *Parser) parseAPI(result *ParsedPackage) error {
 		return fmt.Errorf("no package-level comments found (missing @api annotation)")
 	}
 
-	lines := p.comments.PackageComments.GetAnnotationLines()
+	block := p.comments.PackageComments
+	lines := block.GetAnnotationLines()
 	if len(lines) == 0 {
-		return fmt.Errorf("no annotations found in package comments")
+		return diagnostic.Errorf(block.Position, "missing-api", "", "no annotations found in package comments")
 	}
 
 	// Get @api schema node
@@ -86,11 +334,12 @@ func (p *Parser) parseAPI(result *ParsedPackage) error {
 	// Parse @api annotation
 	parsed, err := ParseAnnotationBlock(lines, "@api", apiNode)
 	if err != nil {
-		return err
+		return AnnotationError(block, "@api", err)
 	}
 
 	// Convert to APIInfo
 	api := &APIInfo{
+		Position:        block.Position,
 		Servers:         make([]*Server, 0),
 		SecuritySchemes: make(map[string]*SecurityScheme),
 		Security:        make([][]*SecurityRequirement, 0),
@@ -101,16 +350,19 @@ func (p *Parser) parseAPI(result *ParsedPackage) error {
 	api.Version = parsed.GetChildValue("@version")
 
 	if api.Title == "" {
-		return fmt.Errorf("@api missing required @title")
+		return diagnostic.Errorf(block.Position, "missing-title", "@api", "missing required @title")
 	}
 	if api.Version == "" {
-		return fmt.Errorf("@api missing required @version")
+		return diagnostic.Errorf(block.Position, "missing-version", "@api", "missing required @version")
 	}
 
 	// Optional fields
 	api.Description = parsed.GetChildValue("@description")
 	api.TermsOfService = parsed.GetChildValue("@termsOfService")
+	api.Self = parsed.GetChildValue("@self")
 	api.DefaultContentType = ExpandContentType(parsed.GetChildValue("@defaultContentType"))
+	api.Produces = ExpandContentTypes(parsed.GetChildValue("@produces"))
+	api.Consumes = ExpandContentTypes(parsed.GetChildValue("@consumes"))
 
 	// Contact
 	if contact := parsed.Children["@contact"]; contact != nil {
@@ -179,18 +431,65 @@ func (p *Parser) parseAPI(result *ParsedPackage) error {
 		tag := &Tag{
 			Name:        tagParsed.Metadata,
 			Description: tagParsed.GetChildValue("@description"),
+			Parent:      tagParsed.GetChildValue("@parent"),
+			Kind:        tagParsed.GetChildValue("@kind"),
 		}
 		api.Tags = append(api.Tags, tag)
 	}
 
+	// Type mappings
+	for _, mappingParsed := range parsed.GetRepeatedChildren("@typeMapping") {
+		mapping, err := parseTypeMapping(mappingParsed.Value)
+		if err != nil {
+			return diagnostic.Wrap(err, block.PositionOf(mappingParsed.Value), "invalid-type-mapping", "@api.@typeMapping")
+		}
+		api.TypeMappings = append(api.TypeMappings, mapping)
+	}
+
 	result.API = api
 	return nil
 }
 
+// annotationError returns err as a diagnostic located in the comment block
+// Syntax errors are located at their annotation, other errors at the start of the block.
+func AnnotationError(block *CommentBlock, path string, err error) error {
+	rule := "invalid-annotation"
+	pos := block.PositionOf("")
+
+	var syntaxErr *SyntaxError
+	if errors.As(err, &syntaxErr) {
+		rule = syntaxErr.Rule
+		pos = block.PositionOf(syntaxErr.Text)
+	}
+	return diagnostic.Wrap(err, pos, rule, path)
+}
+
+// parseTypeMapping parses a @typeMapping value: "<go type> <openapi type> [format]"
+func parseTypeMapping(value string) (*TypeMapping, error) {
+	parts := strings.Fields(value)
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("@typeMapping must be '<go type> <openapi type> [format]', got %q", value)
+	}
+
+	mapping := &TypeMapping{
+		GoType: parts[0],
+		Type:   parts[1],
+	}
+	if len(parts) == 3 {
+		mapping.Format = parts[2]
+	}
+	return mapping, nil
+}
+
 // parseSchemas parses all @schema annotated structs
+// A schema with a malformed @schema annotation is skipped, a field with a malformed @field
+// annotation keeps its Go type only; every error is returned.
 func (p *Parser) parseSchemas(result *ParsedPackage) error {
+	var errs []error
+
 	// First pass: parse @schema annotated structs
-	for structName, commentBlock := range p.comments.StructComments {
+	for _, structName := range sortedNames(p.comments.StructComments) {
+		commentBlock := p.comments.StructComments[structName]
 		if !commentBlock.HasAnnotation("@schema") {
 			continue
 		}
@@ -200,12 +499,15 @@ func (p *Parser) parseSchemas(result *ParsedPackage) error {
 
 		parsed, err := ParseAnnotationBlock(lines, "@schema", schemaNode)
 		if err != nil {
-			return fmt.Errorf("failed to parse @schema for %s: %w", structName, err)
+			errs = append(errs, AnnotationError(commentBlock, fmt.Sprintf("@schema[%s]", structName), err))
+			continue
 		}
 
 		s := &Schema{
 			Name:       structName,
 			GoTypeName: structName,
+			PkgPath:    p.comments.PkgPath,
+			Position:   commentBlock.Position,
 			Fields:     make([]*Field, 0),
 		}
 
@@ -217,35 +519,9 @@ func (p *Parser) parseSchemas(result *ParsedPackage) error {
 		}
 
 		// Parse fields
-		if fieldComments, ok := p.comments.FieldComments[structName]; ok {
-			for fieldName, fieldComment := range fieldComments {
-				if !fieldComment.HasAnnotation("@field") {
-					continue
-				}
-
-				fieldLines := fieldComment.GetAnnotationLines()
-				fieldNode := schema.AnnotationSchema.GetChild("@field")
-
-				// Check if inline format
-				if IsInlineFormat(fieldLines) {
-					parsedField, err := ParseInlineAnnotation(fieldLines[0], "@field", fieldNode)
-					if err != nil {
-						return fmt.Errorf("failed to parse inline @field for %s.%s: %w", structName, fieldName, err)
-					}
-
-					field := p.convertParsedField(fieldName, parsedField)
-					s.Fields = append(s.Fields, field)
-				} else {
-					parsedField, err := ParseAnnotationBlock(fieldLines, "@field", fieldNode)
-					if err != nil {
-						return fmt.Errorf("failed to parse @field for %s.%s: %w", structName, fieldName, err)
-					}
-
-					field := p.convertParsedField(fieldName, parsedField)
-					s.Fields = append(s.Fields, field)
-				}
-			}
-		}
+		fields, fieldErrs := p.parseFields(structName, fmt.Sprintf("@schema[%s]", structName))
+		s.Fields = append(s.Fields, fields...)
+		errs = append(errs, fieldErrs...)
 
 		// Store schema with metadata if present
 		if parsed.HasChild("@description") {
@@ -254,6 +530,12 @@ func (p *Parser) parseSchemas(result *ParsedPackage) error {
 		if parsed.HasChild("@deprecated") {
 			s.Deprecated = true
 		}
+		if parsed.HasChild("@allOf") {
+			s.AllOf = true
+		}
+
+		// Type-level constraints apply to named non-struct types (e.g. type Currency string)
+		s.Constraints = p.convertParsedField(structName, parsed)
 
 		result.Schemas[structName] = s
 	}
@@ -278,6 +560,8 @@ func (p *Parser) parseSchemas(result *ParsedPackage) error {
 			s := &Schema{
 				Name:        typeName,
 				GoTypeName:  typeName,
+				PkgPath:     p.comments.PkgPath,
+				Position:    typeInfo.Position,
 				IsTypeAlias: true,
 				AliasOf:     typeInfo.AliasOf,
 				Fields:      make([]*Field, 0),
@@ -297,7 +581,41 @@ func (p *Parser) parseSchemas(result *ParsedPackage) error {
 		}
 	}
 
-	return nil
+	return diagnostic.Join(errs...)
+}
+
+// parseFields parses the @field annotations of a struct, skipping malformed ones
+// path is the annotation path of the struct (e.g. @schema[User]), used to locate errors
+func (p *Parser) parseFields(structName, path string) ([]*Field, []error) {
+	var fields []*Field
+	var errs []error
+
+	fieldComments := p.comments.FieldComments[structName]
+	for _, fieldName := range sortedNames(fieldComments) {
+		fieldComment := fieldComments[fieldName]
+		if !fieldComment.HasAnnotation("@field") {
+			continue
+		}
+
+		field, err := p.parseField(fieldName, fieldComment)
+		if err != nil {
+			errs = append(errs, AnnotationError(fieldComment, fmt.Sprintf("%s.%s", path, fieldName), err))
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, errs
+}
+
+// sortedNames returns the keys of a map in sorted order, so errors are reported deterministically
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // extractBaseType extracts the base type name from a generic instantiation
@@ -310,8 +628,12 @@ func extractBaseType(typeName string) string {
 }
 
 // parseParameters parses all parameter structs (@path, @query, @header, @cookie)
+// A field with a malformed @field annotation keeps its Go type only; every error is returned.
 func (p *Parser) parseParameters(result *ParsedPackage) error {
-	for structName, commentBlock := range p.comments.StructComments {
+	var errs []error
+
+	for _, structName := range sortedNames(p.comments.StructComments) {
+		commentBlock := p.comments.StructComments[structName]
 		var paramType ParameterType
 
 		// Determine parameter type
@@ -331,49 +653,29 @@ func (p *Parser) parseParameters(result *ParsedPackage) error {
 			Name:       structName,
 			Type:       paramType,
 			GoTypeName: structName,
+			PkgPath:    p.comments.PkgPath,
+			Position:   commentBlock.Position,
 			Fields:     make([]*Field, 0),
 		}
 
 		// Parse fields
-		if fieldComments, ok := p.comments.FieldComments[structName]; ok {
-			for fieldName, fieldComment := range fieldComments {
-				if !fieldComment.HasAnnotation("@field") {
-					continue
-				}
-
-				fieldLines := fieldComment.GetAnnotationLines()
-				fieldNode := schema.AnnotationSchema.GetChild("@field")
-
-				// Check if inline format
-				if IsInlineFormat(fieldLines) {
-					parsedField, err := ParseInlineAnnotation(fieldLines[0], "@field", fieldNode)
-					if err != nil {
-						return fmt.Errorf("failed to parse inline @field for %s.%s: %w", structName, fieldName, err)
-					}
-
-					field := p.convertParsedField(fieldName, parsedField)
-					param.Fields = append(param.Fields, field)
-				} else {
-					parsedField, err := ParseAnnotationBlock(fieldLines, "@field", fieldNode)
-					if err != nil {
-						return fmt.Errorf("failed to parse @field for %s.%s: %w", structName, fieldName, err)
-					}
-
-					field := p.convertParsedField(fieldName, parsedField)
-					param.Fields = append(param.Fields, field)
-				}
-			}
-		}
+		fields, fieldErrs := p.parseFields(structName, fmt.Sprintf("@%s[%s]", paramType, structName))
+		param.Fields = append(param.Fields, fields...)
+		errs = append(errs, fieldErrs...)
 
 		result.Parameters[structName] = param
 	}
 
-	return nil
+	return diagnostic.Join(errs...)
 }
 
 // parseEndpoints parses all @endpoint annotated functions
+// An endpoint with a malformed @endpoint annotation is skipped; every error is returned.
 func (p *Parser) parseEndpoints(result *ParsedPackage) error {
-	for funcName, commentBlock := range p.comments.FunctionComments {
+	var errs []error
+
+	for _, funcName := range sortedNames(p.comments.FunctionComments) {
+		commentBlock := p.comments.FunctionComments[funcName]
 		if !commentBlock.HasAnnotation("@endpoint") {
 			continue
 		}
@@ -383,20 +685,24 @@ func (p *Parser) parseEndpoints(result *ParsedPackage) error {
 
 		parsed, err := ParseAnnotationBlock(lines, "@endpoint", endpointNode)
 		if err != nil {
-			return fmt.Errorf("failed to parse @endpoint for %s: %w", funcName, err)
+			errs = append(errs, AnnotationError(commentBlock, fmt.Sprintf("@endpoint[%s]", funcName), err))
+			continue
 		}
 
 		// Extract method and path from metadata
 		metadata := parsed.Metadata
 		parts := strings.Fields(metadata)
 		if len(parts) < 2 {
-			return fmt.Errorf("@endpoint for %s missing method and path: %s", funcName, metadata)
+			errs = append(errs, diagnostic.Errorf(commentBlock.PositionOf("@endpoint"), "missing-method-path", fmt.Sprintf("@endpoint[%s]", funcName), "missing method and path: %s", metadata))
+			continue
 		}
 
 		endpoint := &Endpoint{
 			FuncName:     funcName,
+			PkgPath:      p.comments.PkgPath,
 			Method:       parts[0],
 			Path:         parts[1],
+			Position:     commentBlock.Position,
 			OperationID:  parsed.GetChildValue("@operationID"),
 			Summary:      parsed.GetChildValue("@summary"),
 			Description:  parsed.GetChildValue("@description"),
@@ -419,8 +725,9 @@ func (p *Parser) parseEndpoints(result *ParsedPackage) error {
 		// Parse request
 		if request := parsed.Children["@request"]; request != nil {
 			endpoint.Request = &RequestBody{
-				ContentType: ExpandContentType(request.GetChildValue("@contentType")),
-				Body:        parseBody(request),
+				ContentTypes: ExpandContentTypes(request.GetChildValue("@contentType")),
+				Body:         parseBody(request),
+				Content:      parseContent(request),
 			}
 		}
 
@@ -429,18 +736,58 @@ func (p *Parser) parseEndpoints(result *ParsedPackage) error {
 			statusCode := responseParsed.Metadata
 			resp := &Response{
 				StatusCode:   statusCode,
-				ContentType:  ExpandContentType(responseParsed.GetChildValue("@contentType")),
+				ContentTypes: ExpandContentTypes(responseParsed.GetChildValue("@contentType")),
 				Body:         parseBody(responseParsed),
+				Content:      parseContent(responseParsed),
 				Description:  responseParsed.GetChildValue("@description"),
 				HeaderParams: extractRepeatedReferences(responseParsed, "@header"),
 			}
+			if responseParsed.HasChild("@attachment") {
+				resp.Attachment = &Attachment{Filename: responseParsed.GetChildValue("@attachment")}
+			}
+			resp.Stream = strings.ToLower(responseParsed.GetChildValue("@stream"))
+			resp.Events = parseEvents(responseParsed)
 			endpoint.Responses[statusCode] = resp
 		}
 
 		result.Endpoints = append(result.Endpoints, endpoint)
 	}
 
-	return nil
+	return diagnostic.Join(errs...)
+}
+
+// ParseField parses the @field annotation of a struct field comment
+// Returns nil, nil if the comment has no @field annotation
+func ParseField(fieldName string, comment *CommentBlock) (*Field, error) {
+	if !comment.HasAnnotation("@field") {
+		return nil, nil
+	}
+	field, err := (&Parser{}).parseField(fieldName, comment)
+	if err != nil {
+		return nil, AnnotationError(comment, "", err)
+	}
+	return field, nil
+}
+
+// parseField parses a @field annotation in inline or block format
+func (p *Parser) parseField(fieldName string, comment *CommentBlock) (*Field, error) {
+	fieldLines := comment.GetAnnotationLines()
+	fieldNode := schema.AnnotationSchema.GetChild("@field")
+
+	// Check if inline format
+	if IsInlineFormat(fieldLines) {
+		parsedField, err := ParseInlineAnnotation(fieldLines[0], "@field", fieldNode)
+		if err != nil {
+			return nil, fmt.Errorf("inline format: %w", err)
+		}
+		return p.convertParsedField(fieldName, parsedField), nil
+	}
+
+	parsedField, err := ParseAnnotationBlock(fieldLines, "@field", fieldNode)
+	if err != nil {
+		return nil, err
+	}
+	return p.convertParsedField(fieldName, parsedField), nil
 }
 
 // convertParsedField converts a ParsedAnnotation to a Field
@@ -505,9 +852,62 @@ func (p *Parser) convertParsedField(fieldName string, parsed *ParsedAnnotation)
 		field.UniqueItems = true
 	}
 
+	// Parse polymorphic schemas (comma-separated)
+	field.OneOf = splitList(parsed.GetChildValue("@oneOf"))
+	field.AnyOf = splitList(parsed.GetChildValue("@anyOf"))
+	field.Discriminator = parseDiscriminator(parsed.GetChildValue("@discriminator"))
+
+	// Parse the encoding of multipart and form parts
+	field.ContentTypes = ExpandContentTypes(parsed.GetChildValue("@contentType"))
+	if headers := extractRepeatedReferences(parsed, "@header"); len(headers) > 0 {
+		field.Headers = headers
+	}
+
 	return field
 }
 
+// splitList splits a comma-separated annotation value, trimming spaces and dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseDiscriminator parses @discriminator propertyName [value=Schema ...] syntax
+// e.g. "type card=Card bank_transfer=BankTransfer"
+// Returns nil for an empty value.
+func parseDiscriminator(value string) *Discriminator {
+	parts := strings.Fields(value)
+	if len(parts) == 0 {
+		return nil
+	}
+
+	d := &Discriminator{PropertyName: parts[0]}
+	for _, part := range parts[1:] {
+		v, schemaName, _ := strings.Cut(strings.TrimSuffix(part, ","), "=")
+		d.Mapping = append(d.Mapping, &DiscriminatorMapping{Value: v, Schema: schemaName})
+	}
+	return d
+}
+
+// parseEvents parses the @event annotations of a response block
+// e.g. @event created UserCreated
+func parseEvents(parsed *ParsedAnnotation) []*Event {
+	var events []*Event
+	for _, eventParsed := range parsed.GetRepeatedChildren("@event") {
+		name, schemaName, _ := strings.Cut(strings.TrimSpace(eventParsed.Value), " ")
+		if name == "" {
+			continue
+		}
+		events = append(events, &Event{Name: name, Schema: strings.TrimSpace(schemaName)})
+	}
+	return events
+}
+
 // extractRepeatedReferences extracts references from repeated children annotations
 func extractRepeatedReferences(parsed *ParsedAnnotation, name string) []string {
 	result := make([]string, 0)
@@ -528,8 +928,7 @@ func ExpandContentType(shortName string) string {
 	}
 
 	// Map of short names to MIME types
-	// Note: Only json struct tags are currently supported for schema field names
-	// xml/csv struct tag support may be added in future versions
+	// Note: Field names come from json struct tags, xml tags are described with the xml object
 	contentTypeMap := map[string]string{
 		"json":      "application/json",
 		"xml":       "application/xml",
@@ -539,6 +938,10 @@ func ExpandContentType(shortName string) string {
 		"csv":       "text/csv",
 		"binary":    "application/octet-stream",
 		"html":      "text/html",
+		"sse":       "text/event-stream",
+		"ndjson":    "application/x-ndjson",
+		"jsonl":     "application/jsonl",
+		"json-seq":  "application/json-seq",
 		"empty":     "", // No content
 	}
 
@@ -550,16 +953,46 @@ func ExpandContentType(shortName string) string {
 	return shortName
 }
 
+// ExpandContentTypes expands a comma-separated list of content types (e.g., "json,xml,csv")
+// Empty entries and the "empty" content type are dropped.
+func ExpandContentTypes(value string) []string {
+	var contentTypes []string
+	for _, item := range splitList(value) {
+		if contentType := ExpandContentType(item); contentType != "" {
+			contentTypes = append(contentTypes, contentType)
+		}
+	}
+	return contentTypes
+}
+
+// parseContent parses the @content blocks of a request/response block
+// e.g. @content csv { @body string }
+func parseContent(parsed *ParsedAnnotation) []*Content {
+	var content []*Content
+	for _, contentParsed := range parsed.GetRepeatedChildren("@content") {
+		content = append(content, &Content{
+			ContentType: ExpandContentType(contentParsed.Metadata),
+			Body:        parseBody(contentParsed),
+		})
+	}
+	return content
+}
+
 // parseBody parses @body annotation from a request/response block
 // Returns nil if no body is defined
 // New syntax: @body User @bind DataResponse.Data
-// The @body value is the schema (User, []User, map[string]User)
+// The @body value is the schema (User, []User, map[string]User, User | Organization)
+// Repeated @body annotations are alternatives, joined as User | Organization
 // The @bind is optional and specifies the wrapper (Wrapper.Field format)
 func parseBody(parsed *ParsedAnnotation) *Body {
-	// Check for @body annotation
-	if bodyParsed := parsed.Children["@body"]; bodyParsed != nil {
+	// Check for @body annotations
+	if bodies := parsed.GetRepeatedChildren("@body"); len(bodies) > 0 {
+		schemas := make([]string, 0, len(bodies))
+		for _, bodyParsed := range bodies {
+			schemas = append(schemas, bodyParsed.Metadata) // Schema name is in metadata (e.g., "User", "[]User")
+		}
 		body := &Body{
-			Schema: bodyParsed.Metadata, // Schema name is in metadata (e.g., "User", "[]User")
+			Schema: strings.Join(schemas, " | "),
 		}
 
 		// Parse @bind annotation (sibling of @body in the response/request block)
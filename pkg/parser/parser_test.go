@@ -1,9 +1,14 @@
 package parser
 
 import (
+	"go/token"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/wontaeyang/go-specgen/pkg/diagnostic"
 	"github.com/wontaeyang/go-specgen/pkg/schema"
+	"golang.org/x/tools/go/packages"
 )
 
 func TestParser_Parse(t *testing.T) {
@@ -226,6 +231,13 @@ func TestParser_ParseEndpoints(t *testing.T) {
 	if len(endpoint.Responses) == 0 {
 		t.Error("Endpoint should have responses")
 	}
+
+	// Positions of the @endpoint comments order the generated paths
+	for _, e := range result.Endpoints {
+		if !strings.HasSuffix(e.Position.Filename, ".go") || e.Position.Line == 0 {
+			t.Errorf("%s %s Position = %v, want a source position", e.Method, e.Path, e.Position)
+		}
+	}
 }
 
 func TestParser_ConvertParsedField(t *testing.T) {
@@ -353,6 +365,97 @@ func TestParser_ConvertParsedField_Deprecated(t *testing.T) {
 	}
 }
 
+func TestParser_ConvertParsedField_Polymorphic(t *testing.T) {
+	parser := &Parser{}
+
+	annotation := &ParsedAnnotation{
+		Children: map[string]*ParsedAnnotation{
+			"@oneOf":         {Value: "Card, BankTransfer,"},
+			"@anyOf":         {Value: "models.Tag"},
+			"@discriminator": {Value: "type card=Card bank_transfer=BankTransfer"},
+		},
+	}
+
+	field := parser.convertParsedField("Payment", annotation)
+
+	if len(field.OneOf) != 2 || field.OneOf[0] != "Card" || field.OneOf[1] != "BankTransfer" {
+		t.Errorf("OneOf = %v, want [Card BankTransfer]", field.OneOf)
+	}
+	if len(field.AnyOf) != 1 || field.AnyOf[0] != "models.Tag" {
+		t.Errorf("AnyOf = %v, want [models.Tag]", field.AnyOf)
+	}
+	if field.Discriminator == nil || field.Discriminator.PropertyName != "type" {
+		t.Fatalf("Discriminator = %+v, want propertyName type", field.Discriminator)
+	}
+	if len(field.Discriminator.Mapping) != 2 {
+		t.Fatalf("Mapping has %d entries, want 2", len(field.Discriminator.Mapping))
+	}
+	if m := field.Discriminator.Mapping[1]; m.Value != "bank_transfer" || m.Schema != "BankTransfer" {
+		t.Errorf("Mapping[1] = %+v, want bank_transfer=BankTransfer", *m)
+	}
+}
+
+func TestParser_ConvertParsedField_PartEncoding(t *testing.T) {
+	parser := &Parser{}
+
+	annotation := &ParsedAnnotation{
+		Children: map[string]*ParsedAnnotation{
+			"@contentType": {Value: "image/png, json"},
+		},
+		RepeatedChildren: map[string][]*ParsedAnnotation{
+			"@header": {{Value: "PartHeaders"}, {Value: "TraceHeaders"}},
+		},
+	}
+
+	field := parser.convertParsedField("Avatar", annotation)
+
+	if got := strings.Join(field.ContentTypes, ","); got != "image/png,application/json" {
+		t.Errorf("ContentTypes = %s, want image/png,application/json", got)
+	}
+	if got := strings.Join(field.Headers, ","); got != "PartHeaders,TraceHeaders" {
+		t.Errorf("Headers = %s, want PartHeaders,TraceHeaders", got)
+	}
+
+	// Fields without part annotations have no encoding
+	field = parser.convertParsedField("Name", &ParsedAnnotation{})
+	if field.ContentTypes != nil || field.Headers != nil {
+		t.Errorf("ContentTypes = %v, Headers = %v, want nil", field.ContentTypes, field.Headers)
+	}
+}
+
+func TestParseDiscriminator(t *testing.T) {
+	tests := []struct {
+		input    string
+		property string
+		mapping  []DiscriminatorMapping
+	}{
+		{"", "", nil},
+		{"kind", "kind", nil},
+		{"kind a=A, b=B", "kind", []DiscriminatorMapping{{"a", "A"}, {"b", "B"}}},
+		{"kind a", "kind", []DiscriminatorMapping{{"a", ""}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := parseDiscriminator(tt.input)
+			if tt.property == "" {
+				if got != nil {
+					t.Errorf("parseDiscriminator() = %+v, want nil", got)
+				}
+				return
+			}
+			if got.PropertyName != tt.property || len(got.Mapping) != len(tt.mapping) {
+				t.Fatalf("parseDiscriminator() = %+v, want %s with %d mappings", got, tt.property, len(tt.mapping))
+			}
+			for i, m := range tt.mapping {
+				if *got.Mapping[i] != m {
+					t.Errorf("Mapping[%d] = %+v, want %+v", i, *got.Mapping[i], m)
+				}
+			}
+		})
+	}
+}
+
 func TestParser_ParseEndpoint_Metadata(t *testing.T) {
 	// Test that endpoint metadata (method and path) is parsed correctly
 	parser := &Parser{
@@ -399,6 +502,92 @@ func TestParser_ParseEndpoint_Metadata(t *testing.T) {
 	}
 }
 
+func TestParser_ParseEndpoints_Diagnostics(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      []string
+		wantRule   string
+		wantLine   int
+		wantColumn int
+		wantError  string
+	}{
+		{
+			name:       "unknown annotation",
+			lines:      []string{"@endpoint GET /users {", "@summary List users", "@sumary Typo", "}"},
+			wantRule:   "unknown-annotation",
+			wantLine:   12,
+			wantColumn: 4,
+			wantError:  "unknown annotation @sumary in @endpoint; did you mean @summary?",
+		},
+		{
+			name:       "not repeatable",
+			lines:      []string{"@endpoint GET /users {", "@summary List users", "@operationID listUsers", "@operationID getUsers", "}"},
+			wantRule:   "not-repeatable",
+			wantLine:   12,
+			wantColumn: 4,
+			wantError:  "@operationID appears multiple times but is not repeatable",
+		},
+		{
+			name:       "unbalanced braces",
+			lines:      []string{"@endpoint GET /users {", "@summary List users"},
+			wantRule:   "unbalanced-braces",
+			wantLine:   10,
+			wantColumn: 4,
+			wantError:  "unbalanced braces",
+		},
+		{
+			name:       "missing method and path",
+			lines:      []string{"@endpoint /users {", "@summary List users", "}"},
+			wantRule:   "missing-method-path",
+			wantLine:   10,
+			wantColumn: 4,
+			wantError:  "missing method and path: /users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Each line is a // comment starting on line 10
+			positions := make([]token.Position, len(tt.lines))
+			for i := range tt.lines {
+				positions[i] = token.Position{Filename: "users.go", Line: 10 + i, Column: 4}
+			}
+
+			parser := &Parser{
+				comments: &PackageComments{
+					FunctionComments: map[string]*CommentBlock{
+						"ListUsers": {
+							Lines:     tt.lines,
+							Position:  token.Position{Filename: "users.go", Line: 10, Column: 1},
+							Positions: positions,
+						},
+					},
+				},
+			}
+
+			err := parser.parseEndpoints(&ParsedPackage{Endpoints: make([]*Endpoint, 0)})
+			diagnostics := diagnostic.List(err)
+			if len(diagnostics) != 1 {
+				t.Fatalf("parseEndpoints() error = %v, want one diagnostic", err)
+			}
+
+			d := diagnostics[0]
+			if d.Rule != tt.wantRule {
+				t.Errorf("Rule = %q, want %q", d.Rule, tt.wantRule)
+			}
+			if d.Pos.Line != tt.wantLine || d.Pos.Column != tt.wantColumn {
+				t.Errorf("Pos = %s, want users.go:%d:%d", d.Pos, tt.wantLine, tt.wantColumn)
+			}
+			if d.Path != "@endpoint[ListUsers]" {
+				t.Errorf("Path = %q, want %q", d.Path, "@endpoint[ListUsers]")
+			}
+			if !strings.Contains(d.Message, tt.wantError) {
+				t.Errorf("Message = %q, want it to contain %q", d.Message, tt.wantError)
+			}
+		})
+	}
+}
+
 func TestParser_ParseEndpoint_Tags(t *testing.T) {
 	// Test that tags are parsed from multiple annotations
 	parser := &Parser{
@@ -478,8 +667,8 @@ func TestParser_ParseEndpoint_Request(t *testing.T) {
 		t.Fatal("Request not parsed")
 	}
 
-	if endpoint.Request.ContentType != "application/json" {
-		t.Errorf("Request.ContentType = %q, want %q", endpoint.Request.ContentType, "application/json")
+	if got := strings.Join(endpoint.Request.ContentTypes, ","); got != "application/json" {
+		t.Errorf("Request.ContentTypes = %q, want %q", got, "application/json")
 	}
 
 	if endpoint.Request.Body == nil || endpoint.Request.Body.Schema != "CreateUserRequest" {
@@ -541,8 +730,8 @@ func TestParser_ParseEndpoint_Responses(t *testing.T) {
 		t.Errorf("Response.StatusCode = %q, want %q", resp200.StatusCode, "200")
 	}
 
-	if resp200.ContentType != "application/json" {
-		t.Errorf("Response.ContentType = %q, want %q", resp200.ContentType, "application/json")
+	if got := strings.Join(resp200.ContentTypes, ","); got != "application/json" {
+		t.Errorf("Response.ContentTypes = %q, want %q", got, "application/json")
 	}
 
 	if resp200.Body == nil || resp200.Body.Schema != "User" {
@@ -655,6 +844,53 @@ func TestParser_ParseAPI_Servers(t *testing.T) {
 	}
 }
 
+func TestParser_ParseAPI_TagHierarchy(t *testing.T) {
+	parser := &Parser{
+		comments: &PackageComments{
+			PackageComments: &CommentBlock{
+				Lines: []string{
+					"@api {",
+					"  @title Test API",
+					"  @version 1.0.0",
+					"  @self https://api.example.com/openapi.yaml",
+					"  @tag account {",
+					"    @description Account management",
+					"    @kind nav",
+					"  }",
+					"  @tag billing {",
+					"    @parent account",
+					"    @kind nav",
+					"  }",
+					"}",
+				},
+			},
+		},
+	}
+
+	result := &ParsedPackage{
+		Schemas:    make(map[string]*Schema),
+		Parameters: make(map[string]*Parameter),
+		Endpoints:  make([]*Endpoint, 0),
+	}
+
+	if err := parser.parseAPI(result); err != nil {
+		t.Fatalf("parseAPI() error = %v", err)
+	}
+
+	if result.API.Self != "https://api.example.com/openapi.yaml" {
+		t.Errorf("Self = %q, want https://api.example.com/openapi.yaml", result.API.Self)
+	}
+	if len(result.API.Tags) != 2 {
+		t.Fatalf("Expected 2 tags, got %d", len(result.API.Tags))
+	}
+	if tag := result.API.Tags[0]; tag.Parent != "" || tag.Kind != "nav" || tag.Description != "Account management" {
+		t.Errorf("Tags[0] = %+v, want a nav tag without parent", tag)
+	}
+	if tag := result.API.Tags[1]; tag.Name != "billing" || tag.Parent != "account" || tag.Kind != "nav" {
+		t.Errorf("Tags[1] = %+v, want billing under account", tag)
+	}
+}
+
 func TestParser_ParseAPI_SecuritySchemes(t *testing.T) {
 	parser := &Parser{
 		comments: &PackageComments{
@@ -777,6 +1013,41 @@ func TestParser_ParseAPI_DefaultContentType(t *testing.T) {
 	}
 }
 
+func TestParser_ParseAPI_ProducesConsumes(t *testing.T) {
+	parser := &Parser{
+		packagePath: "./testdata",
+		comments: &PackageComments{
+			PackageComments: &CommentBlock{
+				Lines: []string{
+					"@api {",
+					"  @title Test API",
+					"  @version 1.0.0",
+					"  @produces json, xml, csv",
+					"  @consumes json,form",
+					"}",
+				},
+			},
+		},
+	}
+
+	result := &ParsedPackage{
+		Schemas:    make(map[string]*Schema),
+		Parameters: make(map[string]*Parameter),
+		Endpoints:  make([]*Endpoint, 0),
+	}
+
+	if err := parser.parseAPI(result); err != nil {
+		t.Fatalf("parseAPI() error = %v", err)
+	}
+
+	if got := strings.Join(result.API.Produces, ","); got != "application/json,application/xml,text/csv" {
+		t.Errorf("Produces = %q, want %q", got, "application/json,application/xml,text/csv")
+	}
+	if got := strings.Join(result.API.Consumes, ","); got != "application/json,application/x-www-form-urlencoded" {
+		t.Errorf("Consumes = %q, want %q", got, "application/json,application/x-www-form-urlencoded")
+	}
+}
+
 func TestParser_ParseBody_Simple(t *testing.T) {
 	// Test @body without binding
 	parser := &Parser{
@@ -828,6 +1099,226 @@ func TestParser_ParseBody_Simple(t *testing.T) {
 	}
 }
 
+func TestParser_ParseBody_Alternatives(t *testing.T) {
+	// Test repeated @body and @body A | B
+	parser := &Parser{
+		comments: &PackageComments{
+			FunctionComments: map[string]*CommentBlock{
+				"CreateAccount": {
+					Lines: []string{
+						"@endpoint POST /accounts {",
+						"  @request {",
+						"    @contentType json",
+						"    @body CreateUser",
+						"    @body CreateOrganization",
+						"  }",
+						"  @response 200 {",
+						"    @contentType json",
+						"    @body User | Organization",
+						"    @bind DataResponse.Data",
+						"  }",
+						"}",
+					},
+				},
+			},
+		},
+	}
+
+	result := &ParsedPackage{
+		Endpoints: make([]*Endpoint, 0),
+	}
+
+	if err := parser.parseEndpoints(result); err != nil {
+		t.Fatalf("parseEndpoints failed: %v", err)
+	}
+
+	endpoint := result.Endpoints[0]
+	if got := endpoint.Request.Body.Schema; got != "CreateUser | CreateOrganization" {
+		t.Errorf("Request Body.Schema = %q, want %q", got, "CreateUser | CreateOrganization")
+	}
+
+	resp := endpoint.Responses["200"]
+	if got := resp.Body.Schema; got != "User | Organization" {
+		t.Errorf("Response Body.Schema = %q, want %q", got, "User | Organization")
+	}
+	if resp.Body.Bind == nil || resp.Body.Bind.Wrapper != "DataResponse" {
+		t.Errorf("Bind = %+v, want DataResponse.Data", resp.Body.Bind)
+	}
+}
+
+func TestParser_ParseAttachment(t *testing.T) {
+	parser := &Parser{
+		comments: &PackageComments{
+			FunctionComments: map[string]*CommentBlock{
+				"DownloadReport": {
+					Lines: []string{
+						"@endpoint GET /reports/{id} {",
+						"  @response 200 {",
+						"    @contentType application/pdf",
+						"    @body binary",
+						"    @attachment report.pdf",
+						"  }",
+						"  @response 202 {",
+						"    @contentType binary",
+						"    @attachment",
+						"  }",
+						"  @response 404 {",
+						"    @description Not found",
+						"  }",
+						"}",
+					},
+				},
+			},
+		},
+	}
+
+	result := &ParsedPackage{
+		Endpoints: make([]*Endpoint, 0),
+	}
+
+	if err := parser.parseEndpoints(result); err != nil {
+		t.Fatalf("parseEndpoints failed: %v", err)
+	}
+
+	responses := result.Endpoints[0].Responses
+	if a := responses["200"].Attachment; a == nil || a.Filename != "report.pdf" {
+		t.Errorf("200 Attachment = %+v, want report.pdf", a)
+	}
+	if got := responses["200"].Body.Schema; got != "binary" {
+		t.Errorf("200 Body.Schema = %q, want binary", got)
+	}
+	if a := responses["202"].Attachment; a == nil || a.Filename != "" {
+		t.Errorf("202 Attachment = %+v, want no file name", a)
+	}
+	if got := strings.Join(responses["202"].ContentTypes, ","); got != "application/octet-stream" {
+		t.Errorf("202 ContentTypes = %q, want application/octet-stream", got)
+	}
+	if a := responses["404"].Attachment; a != nil {
+		t.Errorf("404 Attachment = %+v, want nil", a)
+	}
+}
+
+func TestParser_ParseStream(t *testing.T) {
+	parser := &Parser{
+		comments: &PackageComments{
+			FunctionComments: map[string]*CommentBlock{
+				"StreamJob": {
+					Lines: []string{
+						"@endpoint GET /jobs/events {",
+						"  @response 200 {",
+						"    @stream SSE",
+						"    @body Message",
+						"    @event progress Progress",
+						"    @event done",
+						"  }",
+						"}",
+					},
+				},
+			},
+		},
+	}
+
+	result := &ParsedPackage{
+		Endpoints: make([]*Endpoint, 0),
+	}
+
+	if err := parser.parseEndpoints(result); err != nil {
+		t.Fatalf("parseEndpoints failed: %v", err)
+	}
+
+	response := result.Endpoints[0].Responses["200"]
+	if response.Stream != "sse" {
+		t.Errorf("Stream = %q, want sse", response.Stream)
+	}
+	if len(response.Events) != 2 {
+		t.Fatalf("Events = %d, want 2", len(response.Events))
+	}
+	if e := response.Events[0]; e.Name != "progress" || e.Schema != "Progress" {
+		t.Errorf("Events[0] = %+v, want progress Progress", e)
+	}
+	if e := response.Events[1]; e.Name != "done" || e.Schema != "" {
+		t.Errorf("Events[1] = %+v, want done without a schema", e)
+	}
+
+	for short, want := range map[string]string{
+		"sse":      "text/event-stream",
+		"ndjson":   "application/x-ndjson",
+		"jsonl":    "application/jsonl",
+		"json-seq": "application/json-seq",
+	} {
+		if got := ExpandContentType(short); got != want {
+			t.Errorf("ExpandContentType(%s) = %s, want %s", short, got, want)
+		}
+	}
+}
+
+func TestParser_ParseContent(t *testing.T) {
+	// Test @contentType lists and @content blocks
+	parser := &Parser{
+		comments: &PackageComments{
+			FunctionComments: map[string]*CommentBlock{
+				"ExportUsers": {
+					Lines: []string{
+						"@endpoint POST /users/export {",
+						"  @request {",
+						"    @contentType json, xml",
+						"    @body UserFilter",
+						"  }",
+						"  @response 200 {",
+						"    @contentType json,xml",
+						"    @body []User",
+						"    @content csv {",
+						"      @body string",
+						"    }",
+						"    @content application/vnd.api+json {",
+						"      @body []User",
+						"      @bind DataResponse.Data",
+						"    }",
+						"    @content text/html {",
+						"    }",
+						"  }",
+						"}",
+					},
+				},
+			},
+		},
+	}
+
+	result := &ParsedPackage{
+		Endpoints: make([]*Endpoint, 0),
+	}
+
+	if err := parser.parseEndpoints(result); err != nil {
+		t.Fatalf("parseEndpoints failed: %v", err)
+	}
+
+	endpoint := result.Endpoints[0]
+	if got := strings.Join(endpoint.Request.ContentTypes, ","); got != "application/json,application/xml" {
+		t.Errorf("Request.ContentTypes = %q, want %q", got, "application/json,application/xml")
+	}
+	if len(endpoint.Request.Content) != 0 {
+		t.Errorf("Request.Content = %d blocks, want 0", len(endpoint.Request.Content))
+	}
+
+	resp := endpoint.Responses["200"]
+	if got := strings.Join(resp.ContentTypes, ","); got != "application/json,application/xml" {
+		t.Errorf("Response.ContentTypes = %q, want %q", got, "application/json,application/xml")
+	}
+	if len(resp.Content) != 3 {
+		t.Fatalf("Response.Content = %d blocks, want 3", len(resp.Content))
+	}
+
+	if resp.Content[0].ContentType != "text/csv" || resp.Content[0].Body == nil || resp.Content[0].Body.Schema != "string" {
+		t.Errorf("Content[0] = %+v, want text/csv with body string", resp.Content[0])
+	}
+	if resp.Content[1].ContentType != "application/vnd.api+json" || resp.Content[1].Body == nil || resp.Content[1].Body.Bind == nil {
+		t.Errorf("Content[1] = %+v, want application/vnd.api+json with bound body", resp.Content[1])
+	}
+	if resp.Content[2].ContentType != "text/html" || resp.Content[2].Body != nil {
+		t.Errorf("Content[2] = %+v, want text/html without body", resp.Content[2])
+	}
+}
+
 func TestParser_ParseBody_WithBind(t *testing.T) {
 	// Test @body with @bind using new syntax: @body User @bind DataResponse.Data
 	parser := &Parser{
@@ -1145,3 +1636,347 @@ func TestExpandContentType(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_ParseMultiPackage(t *testing.T) {
+	parser := NewMultiParser([]string{"./testdata/multi/..."}, "")
+
+	parsed, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if parsed.API == nil || parsed.API.Title != "Multi Package API" {
+		t.Fatalf("API not parsed from the @api package: %+v", parsed.API)
+	}
+
+	if parsed.PackageName != "api" {
+		t.Errorf("PackageName = %q, want %q", parsed.PackageName, "api")
+	}
+
+	if len(parser.AllComments()) != 3 {
+		t.Errorf("AllComments() returned %d packages, want 3", len(parser.AllComments()))
+	}
+
+	if parser.Comments() == nil || parser.Comments().Name != "api" {
+		t.Error("Comments() should return the @api package")
+	}
+
+	tests := []struct {
+		schema  string
+		pkgPath string
+	}{
+		{"User", "github.com/wontaeyang/go-specgen/pkg/parser/testdata/multi/users"},
+		{"Order", "github.com/wontaeyang/go-specgen/pkg/parser/testdata/multi/orders"},
+	}
+	for _, tt := range tests {
+		s, ok := parsed.Schemas[tt.schema]
+		if !ok {
+			t.Errorf("schema %s not merged", tt.schema)
+			continue
+		}
+		if s.PkgPath != tt.pkgPath {
+			t.Errorf("schema %s PkgPath = %q, want %q", tt.schema, s.PkgPath, tt.pkgPath)
+		}
+	}
+
+	if _, ok := parsed.Parameters["UserIDPath"]; !ok {
+		t.Error("parameter UserIDPath not merged")
+	}
+
+	if len(parsed.Endpoints) != 3 {
+		t.Errorf("got %d endpoints, want 3", len(parsed.Endpoints))
+	}
+}
+
+func TestParser_ParseMultiPackage_APIPackage(t *testing.T) {
+	tests := []struct {
+		name       string
+		patterns   []string
+		apiPackage string
+		wantErr    bool
+	}{
+		{
+			name:       "directory",
+			patterns:   []string{"./testdata/multi/..."},
+			apiPackage: "./testdata/multi/api",
+		},
+		{
+			name:       "import path",
+			patterns:   []string{"./testdata/multi/..."},
+			apiPackage: "github.com/wontaeyang/go-specgen/pkg/parser/testdata/multi/api",
+		},
+		{
+			name:       "repeated patterns",
+			patterns:   []string{"./testdata/multi/api", "./testdata/multi/users"},
+			apiPackage: "",
+		},
+		{
+			name:       "package without @api",
+			patterns:   []string{"./testdata/multi/..."},
+			apiPackage: "./testdata/multi/users",
+			wantErr:    true,
+		},
+		{
+			name:       "package not loaded",
+			patterns:   []string{"./testdata/multi/..."},
+			apiPackage: "./testdata",
+			wantErr:    true,
+		},
+		{
+			name:     "no @api package",
+			patterns: []string{"./testdata/multi/users", "./testdata/multi/orders"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := NewMultiParser(tt.patterns, tt.apiPackage).Parse()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && parsed.API.Title != "Multi Package API" {
+				t.Errorf("API.Title = %q, want %q", parsed.API.Title, "Multi Package API")
+			}
+		})
+	}
+}
+
+func TestParser_ParseMultiPackage_Collision(t *testing.T) {
+	_, err := NewMultiParser([]string{"./testdata/collision/..."}, "").Parse()
+	if err == nil {
+		t.Fatal("Parse() should error when two packages declare the same parameter")
+	}
+
+	want := "parameter ItemPath is declared in both github.com/wontaeyang/go-specgen/pkg/parser/testdata/collision/a and github.com/wontaeyang/go-specgen/pkg/parser/testdata/collision/b"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err.Error(), want)
+	}
+
+	// Schemas with the same name are qualified instead of reported
+	if strings.Contains(err.Error(), "schema Item") {
+		t.Errorf("error = %q, schema name collisions should not be reported", err.Error())
+	}
+}
+
+func TestParser_ParseImportedSchemas(t *testing.T) {
+	parser := NewParser("./testdata/imports/handlers")
+
+	parsed, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	const modelsPath = "github.com/wontaeyang/go-specgen/pkg/parser/testdata/imports/models"
+	const handlersPath = "github.com/wontaeyang/go-specgen/pkg/parser/testdata/imports/handlers"
+
+	tests := []struct {
+		name       string
+		goTypeName string
+		pkgPath    string
+	}{
+		{"handlers.User", "User", handlersPath},
+		{"models.User", "User", modelsPath},
+		{"Address", "Address", modelsPath},
+	}
+
+	if len(parsed.Schemas) != len(tests) {
+		t.Errorf("got %d schemas, want %d", len(parsed.Schemas), len(tests))
+	}
+
+	for _, tt := range tests {
+		s, ok := parsed.Schemas[tt.name]
+		if !ok {
+			t.Errorf("schema %s not found", tt.name)
+			continue
+		}
+		if s.Name != tt.name {
+			t.Errorf("schema Name = %q, want %q", s.Name, tt.name)
+		}
+		if s.GoTypeName != tt.goTypeName {
+			t.Errorf("schema %s GoTypeName = %q, want %q", tt.name, s.GoTypeName, tt.goTypeName)
+		}
+		if s.PkgPath != tt.pkgPath {
+			t.Errorf("schema %s PkgPath = %q, want %q", tt.name, s.PkgPath, tt.pkgPath)
+		}
+	}
+
+	// Imported packages with schemas are handed to the resolver
+	found := false
+	for _, c := range parser.AllComments() {
+		if c.PkgPath == modelsPath {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("AllComments() should include imported package %s", modelsPath)
+	}
+
+	// Endpoints are only parsed from the scanned package
+	if len(parsed.Endpoints) != 3 {
+		t.Errorf("got %d endpoints, want 3", len(parsed.Endpoints))
+	}
+}
+
+func TestAssignSchemaNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas []*Schema
+		want    []string
+	}{
+		{
+			name:    "unique name is kept",
+			schemas: []*Schema{{Name: "User", PkgPath: "example.com/api/models"}},
+			want:    []string{"User"},
+		},
+		{
+			name: "shared name is qualified with package",
+			schemas: []*Schema{
+				{Name: "User", PkgPath: "example.com/api/models"},
+				{Name: "User", PkgPath: "example.com/api/admin"},
+			},
+			want: []string{"models.User", "admin.User"},
+		},
+		{
+			name: "shared package name uses more path elements",
+			schemas: []*Schema{
+				{Name: "User", PkgPath: "example.com/v1/models"},
+				{Name: "User", PkgPath: "example.com/v2/models"},
+			},
+			want: []string{"v1.models.User", "v2.models.User"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignSchemaNames(tt.schemas)
+			for i, s := range tt.schemas {
+				if s.Name != tt.want[i] {
+					t.Errorf("schema %d Name = %q, want %q", i, s.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestImportedPackages(t *testing.T) {
+	mainModule := &packages.Module{Path: "example.com/api", Main: true}
+	dependency := &packages.Module{Path: "example.com/lib"}
+
+	// api -> models -> shared, api -> lib -> libmodels, api -> net/http
+	shared := &packages.Package{PkgPath: "example.com/api/shared", Module: mainModule}
+	models := &packages.Package{PkgPath: "example.com/api/models", Module: mainModule,
+		Imports: map[string]*packages.Package{shared.PkgPath: shared}}
+	libModels := &packages.Package{PkgPath: "example.com/lib/models", Module: dependency}
+	lib := &packages.Package{PkgPath: "example.com/lib", Module: dependency,
+		Imports: map[string]*packages.Package{libModels.PkgPath: libModels}}
+	http := &packages.Package{PkgPath: "net/http"}
+	api := &packages.Package{PkgPath: "example.com/api", Module: mainModule,
+		Imports: map[string]*packages.Package{models.PkgPath: models, lib.PkgPath: lib, http.PkgPath: http}}
+
+	var got []string
+	for _, pkg := range importedPackages([]*packages.Package{api}) {
+		got = append(got, pkg.PkgPath)
+	}
+
+	want := []string{"example.com/api/models", "example.com/api/shared"}
+	if !slices.Equal(got, want) {
+		t.Errorf("importedPackages() = %v, want %v", got, want)
+	}
+}
+
+func TestParser_ParseAPI_TypeMappings(t *testing.T) {
+	p := NewParser("./testdata/typemap")
+	result, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []TypeMapping{
+		{GoType: "typemap.ID", Type: "string", Format: "uuid"},
+		{GoType: "time.Time", Type: "string", Format: "date"},
+	}
+	if len(result.API.TypeMappings) != len(want) {
+		t.Fatalf("TypeMappings = %d, want %d", len(result.API.TypeMappings), len(want))
+	}
+	for i, m := range result.API.TypeMappings {
+		if *m != want[i] {
+			t.Errorf("TypeMappings[%d] = %+v, want %+v", i, *m, want[i])
+		}
+	}
+}
+
+func TestParseTypeMapping(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    *TypeMapping
+		wantErr bool
+	}{
+		{"github.com/google/uuid.UUID string uuid", &TypeMapping{GoType: "github.com/google/uuid.UUID", Type: "string", Format: "uuid"}, false},
+		{"decimal.Decimal string", &TypeMapping{GoType: "decimal.Decimal", Type: "string"}, false},
+		{"uuid.UUID", nil, true},
+		{"uuid.UUID string uuid extra", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseTypeMapping(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTypeMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != nil && *got != *tt.want {
+				t.Errorf("parseTypeMapping() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_Parse_CollectsErrors(t *testing.T) {
+	parsed, err := NewParser("./testdata/broken").Parse()
+	if parsed == nil {
+		t.Fatalf("Parse() result = nil, want the declarations without errors (error = %v)", err)
+	}
+
+	// Every malformed declaration is reported in one run
+	want := []struct {
+		path string
+		rule string
+		line int
+	}{
+		{path: "@schema[Order]", rule: "unknown-annotation", line: 22},
+		{path: "@schema[User].ID", rule: "unknown-annotation", line: 10},
+		{path: "@query[ListQuery].Page", rule: "not-repeatable", line: 31},
+		{path: "@endpoint[GetOrder]", rule: "unbalanced-braces", line: 51},
+		{path: "@endpoint[ListOrders]", rule: "missing-method-path", line: 46},
+	}
+	diagnostics := diagnostic.List(err)
+	if len(diagnostics) != len(want) {
+		t.Fatalf("Parse() error = %v, want %d diagnostics", err, len(want))
+	}
+	for i, d := range diagnostics {
+		if d.Path != want[i].path || d.Rule != want[i].rule || d.Pos.Line != want[i].line {
+			t.Errorf("diagnostic[%d] = %s, want %s [%s] on line %d", i, d, want[i].path, want[i].rule, want[i].line)
+		}
+	}
+
+	// Malformed declarations are skipped, the others are kept
+	if _, ok := parsed.Schemas["Order"]; ok {
+		t.Error("schema Order has a malformed annotation and should be skipped")
+	}
+	user, ok := parsed.Schemas["User"]
+	if !ok {
+		t.Fatal("schema User should be kept")
+	}
+	if len(user.Fields) != 1 || user.Fields[0].GoName != "Name" {
+		t.Errorf("User fields = %v, want only the well-formed Name annotation", user.Fields)
+	}
+	if _, ok := parsed.Parameters["ListQuery"]; !ok {
+		t.Error("parameter ListQuery should be kept")
+	}
+	if len(parsed.Endpoints) != 1 || parsed.Endpoints[0].FuncName != "ListUsers" {
+		t.Errorf("endpoints = %v, want only ListUsers", parsed.Endpoints)
+	}
+	if parsed.API == nil || parsed.API.Title != "Broken API" {
+		t.Errorf("API = %v, want it parsed", parsed.API)
+	}
+}
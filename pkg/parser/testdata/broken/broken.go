@@ -0,0 +1,56 @@
+// @api {
+//   @title Broken API
+//   @version 1.0.0
+// }
+package broken
+
+// @schema
+type User struct {
+	// @field {
+	//   @fromat uuid
+	//   @description User ID
+	// }
+	ID string `json:"id"`
+
+	// @field {
+	//   @description User name
+	// }
+	Name string `json:"name"`
+}
+
+// @schema {
+//   @descripton An order
+// }
+type Order struct {
+	ID string `json:"id"`
+}
+
+// @query
+type ListQuery struct {
+	// @field {
+	//   @minimum 1
+	//   @minimum 2
+	// }
+	Page int `query:"page"`
+}
+
+// @endpoint GET /users {
+//   @summary List users
+//   @query ListQuery
+//   @response 200 {
+//     @body []User
+//   }
+// }
+func ListUsers() {}
+
+// @endpoint /orders {
+//   @summary List orders
+// }
+func ListOrders() {}
+
+// @endpoint GET /orders/{id} {
+//   @summary Get an order
+//   @response 200 {
+//     @body Order
+//   }
+func GetOrder() {}
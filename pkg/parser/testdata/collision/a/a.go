@@ -0,0 +1,15 @@
+// @api {
+//   @title Collision API
+//   @version 1.0.0
+// }
+package a
+
+// @schema
+type Item struct {
+	ID string `json:"id"`
+}
+
+// @path
+type ItemPath struct {
+	ID string `path:"id"`
+}
@@ -0,0 +1,11 @@
+package b
+
+// @schema
+type Item struct {
+	Name string `json:"name"`
+}
+
+// @path
+type ItemPath struct {
+	ID string `path:"id"`
+}
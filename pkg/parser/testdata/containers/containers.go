@@ -0,0 +1,77 @@
+//	@api {
+//	  @title Containers API
+//	  @version 1.0.0
+//	}
+package containers
+
+// @schema
+type User struct {
+	Name string `json:"name"`
+}
+
+// @schema
+type Item struct {
+	SKU string `json:"sku"`
+}
+
+// Tree is a recursive named map
+type Tree map[string]Tree
+
+// @schema
+type Catalog struct {
+	Matrix   [][]string         `json:"matrix"`
+	Groups   map[string][]User  `json:"groups"`
+	Rows     []map[string]*Item `json:"rows"`
+	Owners   *[]User            `json:"owners"`
+	Scores   []*int             `json:"scores"`
+	Grid     [][3]int           `json:"grid"`
+	Values   []any              `json:"values"`
+	Tree     Tree               `json:"tree"`
+	Sections map[string][]struct {
+		Title string `json:"title"`
+	} `json:"sections"`
+}
+
+// @endpoint GET /catalog {
+//   @response 200 {
+//     @contentType json
+//     @body map[string][]Item
+//   }
+// }
+func GetCatalog() {}
+
+// @endpoint POST /matrix {
+//   @request {
+//     @contentType json
+//     @body [][]User
+//   }
+//   @response 200 {
+//     @contentType json
+//     @body *[]string
+//   }
+// }
+func PostMatrix() {}
+
+// @endpoint POST /accounts {
+//   @request {
+//     @contentType json
+//     @body User
+//     @body []Item
+//   }
+//   @response 200 {
+//     @contentType json
+//     @body User | map[string]Item | string
+//   }
+// }
+func PostAccounts() {}
+
+// @endpoint GET /users/export {
+//   @response 200 {
+//     @contentType json,xml
+//     @body []User
+//     @content csv {
+//       @body string
+//     }
+//   }
+// }
+func ExportUsers() {}
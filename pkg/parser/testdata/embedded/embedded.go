@@ -0,0 +1,104 @@
+// @api {
+//   @title Embedded API
+//   @version 1.0.0
+// }
+package embedded
+
+// @schema
+type Base struct {
+	// @field {
+	//   @description Unique identifier
+	// }
+	ID string `json:"id"`
+
+	CreatedAt string `json:"createdAt"`
+}
+
+type audit struct {
+	// @field { @description Last editor }
+	UpdatedBy string `json:"updatedBy"`
+}
+
+// @schema
+type User struct {
+	Base
+	*audit
+
+	Name string `json:"name"`
+}
+
+// @schema {
+//   @allOf
+// }
+type Admin struct {
+	Base
+
+	Level int `json:"level"`
+}
+
+// @schema {
+//   @allOf
+// }
+type Override struct {
+	Base
+
+	// ID shadows Base.ID
+	ID int `json:"id"`
+}
+
+type First struct {
+	Name  string `json:"name"`
+	Title string `json:"Title"`
+}
+
+type Second struct {
+	Name  string `json:"name"`
+	Title string
+}
+
+// @schema
+type Conflict struct {
+	First
+	Second
+
+	// Tagged embedded structs are regular fields
+	Base `json:"base"`
+}
+
+type Pagination struct {
+	// @field { @description Maximum results }
+	Limit int `query:"limit"`
+
+	Offset int `query:"offset"`
+}
+
+// @query
+type ListQuery struct {
+	Pagination
+
+	Search string `query:"q"`
+}
+
+// @endpoint GET /users {
+//   @query ListQuery
+//   @response 200 {
+//     @body []User
+//   }
+// }
+func ListUsers() {}
+
+// @endpoint GET /admins {
+//   @response 200 {
+//     @body []Admin
+//   }
+// }
+func ListAdmins() {
+	// @query
+	var query struct {
+		Pagination
+
+		Level int `query:"level"`
+	}
+
+	_ = query
+}
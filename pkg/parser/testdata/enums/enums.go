@@ -0,0 +1,57 @@
+// @api {
+//   @title Enums API
+//   @version 1.0.0
+// }
+package enums
+
+import (
+	"os"
+	"time"
+)
+
+// TaskStatus is the lifecycle state of a task
+type TaskStatus string
+
+const (
+	// Task is waiting to be picked up
+	TaskPending TaskStatus = "pending"
+	TaskRunning TaskStatus = "running" // Task is being processed
+	TaskDone    TaskStatus = "done"
+
+	// TaskFinished is an alias of TaskDone and is not repeated
+	TaskFinished TaskStatus = "done"
+
+	taskUnknown TaskStatus = "unknown"
+)
+
+// Priority is declared with iota
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityMedium
+	PriorityHigh
+)
+
+// @schema
+type Task struct {
+	Status TaskStatus `json:"status"`
+
+	Priority *Priority `json:"priority,omitempty"`
+
+	Labels []TaskStatus `json:"labels"`
+
+	// @field {
+	//   @enum pending,done
+	// }
+	Filter TaskStatus `json:"filter"`
+
+	// Constants of library types are not enums
+	Timeout time.Duration `json:"timeout"`
+	Mode    os.FileMode   `json:"mode"`
+}
+
+// @query
+type TaskQuery struct {
+	Status TaskStatus `query:"status"`
+}
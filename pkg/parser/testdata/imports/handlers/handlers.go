@@ -0,0 +1,35 @@
+// @api {
+//   @title Imports API
+//   @version 1.0.0
+// }
+package handlers
+
+import "github.com/wontaeyang/go-specgen/pkg/parser/testdata/imports/models"
+
+// @schema
+type User struct {
+	ID      string          `json:"id"`
+	Profile models.User     `json:"profile"`
+	Address *models.Address `json:"address,omitempty"`
+}
+
+// @endpoint GET /users/{id} {
+//   @response 200 {
+//     @body User
+//   }
+// }
+func GetUser() {}
+
+// @endpoint GET /profiles {
+//   @response 200 {
+//     @body []models.User
+//   }
+// }
+func ListProfiles() {}
+
+// @endpoint GET /addresses/{id} {
+//   @response 200 {
+//     @body Address
+//   }
+// }
+func GetAddress() {}
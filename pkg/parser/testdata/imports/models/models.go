@@ -0,0 +1,13 @@
+package models
+
+// @schema
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// @schema
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
@@ -0,0 +1,60 @@
+//	@api {
+//	  @title JSON Encoding API
+//	  @version 1.0.0
+//	}
+package jsonenc
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Checksum is a named byte slice (still base64)
+type Checksum []byte
+
+// Octet encodes itself as text, so a slice of octets is an array
+type Octet byte
+
+func (o Octet) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(int(o))), nil
+}
+
+// Values covers types encoding/json treats specially
+//
+// @schema
+type Values struct {
+	Name     string          `json:"name"`
+	Bytes    []byte          `json:"bytes"`
+	Checksum Checksum        `json:"checksum"`
+	Octets   []Octet         `json:"octets"`
+	Raw      json.RawMessage `json:"raw"`
+	Number   json.Number     `json:"number"`
+	Window   [3]int          `json:"window"`
+	ID       [4]byte         `json:"id"`
+	Tags     []string        `json:"tags"`
+	Count    int64           `json:"count,string"`
+	Ratio    float64         `json:"ratio,string"`
+	Enabled  bool            `json:"enabled,string"`
+	Limit    *int            `json:"limit,string"`
+	Label    string          `json:"label,string"`
+}
+
+var limit = 10
+
+// Sample is encoded by the conformance test and compared against the resolved schema
+var Sample = Values{
+	Name:     "sample",
+	Bytes:    []byte("hello"),
+	Checksum: Checksum{0xde, 0xad, 0xbe, 0xef},
+	Octets:   []Octet{1, 2},
+	Raw:      json.RawMessage(`{"nested":[1,"two"]}`),
+	Number:   json.Number("12.5"),
+	Window:   [3]int{1, 2, 3},
+	ID:       [4]byte{1, 2, 3, 4},
+	Tags:     []string{"a", "b"},
+	Count:    42,
+	Ratio:    0.5,
+	Enabled:  true,
+	Limit:    &limit,
+	Label:    "text",
+}
@@ -0,0 +1,48 @@
+//	@api {
+//	  @title JSON Tags API
+//	  @version 1.0.0
+//	}
+package jsontags
+
+import (
+	"time"
+)
+
+// Audit is flattened into the structs that inline it
+type Audit struct {
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+// @schema
+type Document struct {
+	Title     string            `json:"title"`
+	Summary   string            `json:"summary,omitzero"`
+	Notes     string            `json:"notes,omitempty"`
+	Quoted    string            `json:"'a,b'"`
+	Audit     Audit             `json:"audit,inline"`
+	Extra     map[string]any    `json:",unknown"`
+	Labels    map[string]string `json:",inline"`
+	Name      string            `json:"name,case:ignore"`
+	Published time.Time         `json:"published,format:DateOnly"`
+	Expires   time.Time         `json:"expires,format:unixmilli"`
+	Day       time.Time         `json:"day,format:'2006-01-02'"`
+	Timeout   time.Duration     `json:"timeout,format:iso8601"`
+	Checksum  []byte            `json:"checksum,format:base64url"`
+	Raw       []byte            `json:"raw,format:array"`
+	Bad       string            `json:"bad,format:upper"`
+}
+
+// @schema
+type Tagged struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:",inline"`
+}
+
+// @schema
+type Inline struct {
+	Body struct {
+		Title string `json:"title"`
+		Audit Audit  `json:",inline"`
+	} `json:"body"`
+}
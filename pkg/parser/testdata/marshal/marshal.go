@@ -0,0 +1,71 @@
+//	@api {
+//	  @title Marshal API
+//	  @version 1.0.0
+//	}
+package marshal
+
+import "strconv"
+
+// Level is encoded by name
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(int(l))), nil
+}
+
+// Point is encoded as a string with a pointer receiver
+type Point struct {
+	X, Y int
+}
+
+func (p *Point) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(p.X) + "," + strconv.Itoa(p.Y)), nil
+}
+
+// Blob encodes itself
+type Blob struct {
+	data []byte
+}
+
+func (b Blob) MarshalJSON() ([]byte, error) {
+	return b.data, nil
+}
+
+// Raw encodes itself but is described by its @schema
+//
+// @schema
+type Raw struct {
+	Value string `json:"value"`
+}
+
+func (r Raw) MarshalJSON() ([]byte, error) {
+	return []byte(`{"value":"` + r.Value + `"}`), nil
+}
+
+// NotMarshaler has a MarshalJSON method with the wrong signature
+type NotMarshaler struct {
+	Name string `json:"name"`
+}
+
+func (n NotMarshaler) MarshalJSON() string {
+	return n.Name
+}
+
+// @schema
+type Event struct {
+	Level  Level   `json:"level"`
+	Origin *Point  `json:"origin"`
+	Blob   Blob    `json:"blob"`
+	Raw    Raw     `json:"raw"`
+	Levels []Level `json:"levels"`
+}
+
+// @schema
+type Wrong struct {
+	Other NotMarshaler `json:"other"`
+}
@@ -0,0 +1,13 @@
+// @api {
+//   @title Multi Package API
+//   @version 1.0.0
+// }
+package api
+
+// @endpoint GET /health {
+//   @summary Health check
+//   @response 204 {
+//     @description No content
+//   }
+// }
+func Health() {}
@@ -0,0 +1,26 @@
+package orders
+
+// @schema
+type Order struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+// @endpoint POST /orders {
+//   @summary Create order
+//   @request {
+//     @body Order
+//   }
+//   @response 201 {
+//     @body Order
+//   }
+// }
+func CreateOrder() {
+	// @query
+	var query struct {
+		// @field { @description Validate without creating }
+		DryRun bool `query:"dryRun"`
+	}
+
+	_ = query
+}
@@ -0,0 +1,26 @@
+package users
+
+// @schema
+type User struct {
+	// @field {
+	//   @description User ID
+	// }
+	ID string `json:"id"`
+
+	Name string `json:"name"`
+}
+
+// @path
+type UserIDPath struct {
+	// @field
+	ID string `path:"id"`
+}
+
+// @endpoint GET /users/{id} {
+//   @summary Get user by ID
+//   @path UserIDPath
+//   @response 200 {
+//     @body User
+//   }
+// }
+func GetUser() {}
@@ -0,0 +1,97 @@
+//	@api {
+//	  @title Polymorphic API
+//	  @version 1.0.0
+//	}
+package polymorphic
+
+//	@schema {
+//	  @description How an order is paid
+//	  @discriminator type
+//	}
+type PaymentMethod interface {
+	paymentMethod()
+}
+
+// @schema
+type Card struct {
+	// @field { @enum card }
+	Type   string `json:"type"`
+	Number string `json:"number"`
+}
+
+func (Card) paymentMethod() {}
+
+// @schema
+type BankTransfer struct {
+	Type BankTransferType `json:"type"`
+	IBAN string           `json:"iban"`
+}
+
+func (*BankTransfer) paymentMethod() {}
+
+type BankTransferType string
+
+const BankTransferKind BankTransferType = "bank_transfer"
+
+// @schema
+type Wallet struct {
+	Type     string `json:"type"`
+	Provider string `json:"provider"`
+}
+
+// Event is not a @schema: fields of this type list its implementations with @discriminator
+type Event interface {
+	EventName() string
+}
+
+// @schema
+type Created struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+}
+
+func (Created) EventName() string { return "created" }
+
+// @schema
+type Cancelled struct {
+	Kind   string `json:"kind"`
+	Reason string `json:"reason"`
+}
+
+func (Cancelled) EventName() string { return "cancelled" }
+
+// @schema
+type Order struct {
+	Payment PaymentMethod `json:"payment"`
+
+	// @field {
+	//   @oneOf Card, BankTransfer
+	//   @discriminator type card=Card
+	// }
+	Refund any `json:"refund"`
+
+	// @field { @anyOf Card, Wallet }
+	Fallback any `json:"fallback,omitempty"`
+
+	// @field { @oneOf Card, Wallet }
+	History []any `json:"history"`
+
+	// @field { @discriminator kind }
+	Events []Event `json:"events"`
+
+	Billing struct {
+		// @field { @oneOf Card, BankTransfer }
+		Method any `json:"method"`
+	} `json:"billing"`
+}
+
+// @endpoint POST /refunds {
+// }
+func CreateRefund() {
+	// @request
+	var req struct {
+		// @field { @oneOf Card, Wallet }
+		Method any `json:"method"`
+	}
+	_ = req
+}
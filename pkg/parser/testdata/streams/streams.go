@@ -0,0 +1,60 @@
+//	@api {
+//	  @title Streams API
+//	  @version 1.0.0
+//	}
+package streams
+
+// @schema
+type Message struct {
+	Text string `json:"text"`
+}
+
+// @schema
+type Progress struct {
+	Percent int `json:"percent"`
+}
+
+// @schema
+type Done struct {
+	ID string `json:"id"`
+}
+
+// @schema
+type LogLine struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// @endpoint GET /messages/stream {
+//   @response 200 {
+//     @stream sse
+//     @body Message
+//   }
+// }
+func StreamMessages() {}
+
+// @endpoint GET /jobs/events {
+//   @response 200 {
+//     @stream sse
+//     @event progress Progress
+//     @event done Done
+//   }
+// }
+func StreamJobEvents() {}
+
+// @endpoint GET /logs {
+//   @response 200 {
+//     @contentType json
+//     @stream ndjson
+//     @body LogLine
+//   }
+// }
+func StreamLogs() {}
+
+// @endpoint GET /ticks {
+//   @response 200 {
+//     @stream sse
+//     @body string
+//   }
+// }
+func StreamTicks() {}
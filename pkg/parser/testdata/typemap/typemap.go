@@ -0,0 +1,32 @@
+//	@api {
+//	  @title Type Mapping API
+//	  @version 1.0.0
+//	  @typeMapping typemap.ID string uuid
+//	  @typeMapping time.Time string date
+//	}
+package typemap
+
+import "time"
+
+// ID is a 16-byte identifier
+type ID [16]byte
+
+// Decimal is an arbitrary-precision number
+type Decimal struct {
+	value string
+}
+
+// Money is an amount in a currency
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// @schema
+type Invoice struct {
+	ID    ID        `json:"id"`
+	Total Decimal   `json:"total"`
+	Price *Money    `json:"price"`
+	Due   time.Time `json:"due"`
+	Tags  []ID      `json:"tags"`
+}
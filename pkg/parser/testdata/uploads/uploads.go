@@ -0,0 +1,87 @@
+//	@api {
+//	  @title Uploads API
+//	  @version 1.0.0
+//	}
+package uploads
+
+import (
+	"io"
+	"mime/multipart"
+)
+
+// @header
+type PartHeaders struct {
+	Checksum string `header:"X-Checksum"`
+}
+
+// @schema
+type UploadForm struct {
+	// @field {
+	//   @contentType image/png, image/jpeg
+	// }
+	Avatar      *multipart.FileHeader   `json:"avatar"`
+	Attachments []*multipart.FileHeader `json:"attachments,omitempty"`
+	Thumbnail   []byte                  `json:"thumbnail,omitempty"`
+
+	// @field {
+	//   @contentType json
+	// }
+	Metadata string `json:"metadata"`
+
+	// @field {
+	//   @header PartHeaders
+	// }
+	Document io.Reader `json:"document"`
+}
+
+// @schema
+type Upload struct {
+	ID string `json:"id"`
+}
+
+// @endpoint POST /uploads {
+//   @request {
+//     @contentType multipart
+//     @body UploadForm
+//   }
+//   @response 201 {
+//     @body Upload
+//   }
+// }
+func CreateUpload() {}
+
+// @endpoint PUT /uploads/raw {
+//   @request {
+//     @contentType binary
+//   }
+//   @response 201 {
+//     @body Upload
+//   }
+// }
+func UploadRaw() {}
+
+// @endpoint GET /reports/latest {
+//   @response 200 {
+//     @contentType application/pdf
+//     @body binary
+//     @attachment report.pdf
+//   }
+// }
+func DownloadReport() {}
+
+// @endpoint GET /exports/latest {
+//   @response 200 {
+//     @contentType application/zip
+//     @body io.Reader
+//     @attachment
+//   }
+// }
+func DownloadExport() {}
+
+// @endpoint GET /thumbnails/latest {
+//   @response 200 {
+//     @contentType image/png, json
+//     @body []byte
+//   }
+// }
+func GetThumbnail() {}
@@ -0,0 +1,19 @@
+//	@api {
+//	  @title Validate API
+//	  @version 1.0.0
+//	}
+package validate
+
+// @schema
+type CreateUser struct {
+	Name string `json:"name,omitempty" validate:"required,min=1,max=100"`
+
+	// @field {
+	//   @maxLength 50
+	// }
+	Nickname string `json:"nickname" validate:"max=20"`
+
+	Email string `json:"email" validate:"omitempty,email"`
+
+	Website string `json:"website" validate:"excludesall=!@"`
+}
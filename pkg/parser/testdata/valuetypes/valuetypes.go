@@ -0,0 +1,51 @@
+//	@api {
+//	  @title Value Types API
+//	  @version 1.0.0
+//	}
+package valuetypes
+
+//	@schema {
+//	  @description ISO 4217 currency code
+//	  @pattern ^[A-Z]{3}$
+//	}
+type Currency string
+
+const (
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+)
+
+//	@schema {
+//	  @description Amount in minor units
+//	  @format int64
+//	  @minimum 0
+//	}
+type Money int64
+
+//	@schema {
+//	  @enum red,green
+//	}
+type Color string
+
+const (
+	Red   Color = "red"
+	Green Color = "green"
+	Blue  Color = "blue"
+)
+
+//	@schema {
+//	  @maxItems 10
+//	  @uniqueItems
+//	}
+type Tags []string
+
+// @schema
+type Currencies []Currency
+
+// @schema
+type Price struct {
+	Amount   Money      `json:"amount"`
+	Currency *Currency  `json:"currency"`
+	Tags     Tags       `json:"tags"`
+	Accepted []Currency `json:"accepted"`
+}
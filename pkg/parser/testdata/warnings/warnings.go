@@ -0,0 +1,38 @@
+//	@api {
+//	  @title Warnings API
+//	  @version 1.0.0
+//	}
+package warnings
+
+import "net/http"
+
+// @schema
+type User struct {
+	// specgen:ignore unsupported-validate-rule
+	Website string `json:"website" validate:"excludesall=!@"`
+
+	Homepage string `json:"homepage" validate:"excludesall=!@"` // specgen:ignore json-marshaler
+}
+
+// @schema
+// specgen:ignore
+type Profile struct {
+	Blog string `json:"blog" validate:"excludesall=!@"`
+}
+
+// @endpoint GET /users {
+//   @query ListQuery
+//   @response 200 {
+//     @contentType json
+//     @body []User
+//     @header RateLimitHeaders
+//   }
+// }
+func ListUsers(w http.ResponseWriter, r *http.Request) {
+	// @response 200
+	var resp struct {
+		Name string `json:"name"`
+	}
+
+	_ = resp
+}
@@ -0,0 +1,44 @@
+//	@api {
+//	  @title XML API
+//	  @version 1.0.0
+//	  @produces json, xml
+//	}
+package xmltags
+
+import "encoding/xml"
+
+// @schema
+type Item struct {
+	SKU string `json:"sku" xml:"sku,attr"`
+}
+
+// @schema
+type Feed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed" json:"-"`
+	ID      string   `json:"id" xml:"id,attr"`
+	Title   string   `json:"title" xml:"atom:title"`
+	Lang    string   `json:"lang" xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Note    string   `json:"note" xml:",chardata"`
+	Raw     string   `json:"raw" xml:",innerxml"`
+	Items   []Item   `json:"items" xml:"items>item"`
+	Tags    []string `json:"tags" xml:"tag"`
+	Links   []string `json:"links" xml:"links"`
+	City    string   `json:"city" xml:"address>city"`
+	Hidden  string   `json:"hidden" xml:"-"`
+	Comment string   `json:"comment" xml:",comment"`
+	Plain   string   `json:"plain"`
+}
+
+// @schema
+type Entry struct {
+	XMLName xml.Name
+	Value   string `xml:"value"`
+	Entries []Item `xml:"entries>entry"`
+}
+
+// @endpoint GET /feed {
+//   @response 200 {
+//     @body Feed
+//   }
+// }
+func GetFeed() {}
@@ -1,5 +1,7 @@
 package parser
 
+import "go/token"
+
 // ParsedPackage represents a complete parsed Go package with all annotations
 type ParsedPackage struct {
 	// PackageName is the Go package name
@@ -20,6 +22,9 @@ type ParsedPackage struct {
 
 // APIInfo represents the @api annotation
 type APIInfo struct {
+	// Position is the position of the package comment holding @api
+	Position token.Position
+
 	// Title is the API title (required)
 	Title string
 
@@ -32,6 +37,9 @@ type APIInfo struct {
 	// TermsOfService is the URL to the terms of service
 	TermsOfService string
 
+	// Self is the URI of the document ($self, OpenAPI 3.2)
+	Self string
+
 	// Contact contains contact information
 	Contact *Contact
 
@@ -52,6 +60,15 @@ type APIInfo struct {
 
 	// DefaultContentType is the default content type for requests/responses
 	DefaultContentType string
+
+	// Produces are the default content types of responses (from @produces)
+	Produces []string
+
+	// Consumes are the default content types of request bodies (from @consumes)
+	Consumes []string
+
+	// TypeMappings map Go types to OpenAPI types (from @typeMapping)
+	TypeMappings []*TypeMapping
 }
 
 // Contact represents contact information
@@ -98,15 +115,28 @@ type Schema struct {
 	// GoTypeName is the full Go type name (for resolution)
 	GoTypeName string
 
+	// PkgPath is the import path of the package declaring the type
+	PkgPath string
+
 	// Description is the schema description
 	Description string
 
+	// Position is the position of the @schema comment (of the declaration for generic aliases)
+	Position token.Position
+
 	// Deprecated indicates if the schema is deprecated
 	Deprecated bool
 
+	// AllOf composes embedded @schema types with allOf instead of flattening their fields
+	AllOf bool
+
 	// Fields are the struct fields
 	Fields []*Field
 
+	// Constraints are the type-level constraints of a named non-struct type
+	// (e.g. @enum on type Currency string), in the same form as a @field annotation
+	Constraints *Field
+
 	// IsGeneric indicates this is a generic struct (has type parameters)
 	// Generic structs are templates and not emitted to components
 	IsGeneric bool
@@ -177,6 +207,39 @@ type Field struct {
 
 	// Deprecated indicates if the field is deprecated
 	Deprecated bool
+
+	// OneOf lists the schemas of a polymorphic value exactly one of which it matches (@oneOf)
+	OneOf []string
+
+	// AnyOf lists the schemas of a polymorphic value it matches at least one of (@anyOf)
+	AnyOf []string
+
+	// Discriminator names the property that selects the schema of a polymorphic value
+	Discriminator *Discriminator
+
+	// ContentTypes are the content types of the field's part in multipart and form bodies (@contentType)
+	ContentTypes []string
+
+	// Headers are the header struct references describing the headers of the field's part (@header)
+	Headers []string
+}
+
+// Discriminator represents @discriminator propertyName [value=Schema ...] syntax
+type Discriminator struct {
+	// PropertyName is the name of the property holding the discriminating value
+	PropertyName string
+
+	// Mapping maps discriminator values to schemas, in annotation order
+	Mapping []*DiscriminatorMapping
+}
+
+// DiscriminatorMapping maps a discriminator value to a schema (e.g. card=Card)
+type DiscriminatorMapping struct {
+	// Value is the discriminator value
+	Value string
+
+	// Schema is the schema name; empty if the mapping has no "=" (reported by validation)
+	Schema string
 }
 
 // Parameter represents a parameter struct (@path, @query, @header, @cookie)
@@ -190,6 +253,12 @@ type Parameter struct {
 	// GoTypeName is the full Go type name
 	GoTypeName string
 
+	// PkgPath is the import path of the package declaring the type
+	PkgPath string
+
+	// Position is the position of the parameter struct comment
+	Position token.Position
+
 	// Fields are the parameter fields
 	Fields []*Field
 }
@@ -209,12 +278,18 @@ type Endpoint struct {
 	// FuncName is the Go function name (for inline declaration lookup)
 	FuncName string
 
+	// PkgPath is the import path of the package declaring the function
+	PkgPath string
+
 	// Method is the HTTP method (GET, POST, PUT, DELETE, etc.)
 	Method string
 
 	// Path is the URL path (e.g., /users/{id})
 	Path string
 
+	// Position is the position of the @endpoint comment
+	Position token.Position
+
 	// OperationID is the operation ID
 	OperationID string
 
@@ -254,11 +329,14 @@ type Endpoint struct {
 
 // RequestBody represents a request body
 type RequestBody struct {
-	// ContentType is the content type (json, xml, form, etc.)
-	ContentType string
+	// ContentTypes are the content types sharing Body (e.g., @contentType json,xml)
+	ContentTypes []string
 
 	// Body is the body definition with optional bindings
 	Body *Body
+
+	// Content are the @content blocks, content types with their own body
+	Content []*Content
 }
 
 // Response represents a response definition
@@ -266,22 +344,60 @@ type Response struct {
 	// StatusCode is the HTTP status code (200, 404, etc.)
 	StatusCode string
 
-	// ContentType is the content type
-	ContentType string
+	// ContentTypes are the content types sharing Body (e.g., @contentType json,xml)
+	ContentTypes []string
 
 	// Body is the body definition with optional bindings
 	Body *Body
 
+	// Content are the @content blocks, content types with their own body
+	Content []*Content
+
 	// Description is the response description
 	Description string
 
 	// HeaderParams are the response header struct references
 	HeaderParams []string
+
+	// Attachment marks a file download (@attachment [filename]), nil for other responses
+	Attachment *Attachment
+
+	// Stream is the stream format of a streamed response (@stream sse|ndjson|jsonl|json-seq)
+	// Body then describes each item of the stream.
+	Stream string
+
+	// Events are the named Server-Sent Events of the stream (@event name Schema)
+	Events []*Event
+}
+
+// Event represents @event name Schema syntax: a named Server-Sent Event and the schema of its data
+type Event struct {
+	// Name is the event name (the SSE event field)
+	Name string
+
+	// Schema is the schema of the event data, empty if the event has no data schema (reported by validation)
+	Schema string
+}
+
+// Attachment represents @attachment [filename] syntax: the Content-Disposition of a download
+type Attachment struct {
+	// Filename is the suggested file name, empty if not given
+	Filename string
+}
+
+// Content represents a @content block: a content type of a request or response
+type Content struct {
+	// ContentType is the content type (e.g., "text/csv")
+	ContentType string
+
+	// Body is the body of this content type, nil to use the body of the request/response
+	Body *Body
 }
 
 // Body represents a @body annotation with optional binding
 type Body struct {
 	// Schema is the schema name being referenced (e.g., "User", "[]User", "map[string]User")
+	// Alternatives are separated by "|" (e.g., "User | Organization")
 	Schema string
 
 	// Bind specifies the wrapper and field for envelope responses
@@ -298,6 +414,19 @@ type BindTarget struct {
 	Field string
 }
 
+// TypeMapping maps a Go type to an OpenAPI type and format
+// e.g. @typeMapping github.com/google/uuid.UUID string uuid
+type TypeMapping struct {
+	// GoType is the package-qualified Go type (e.g. "github.com/google/uuid.UUID")
+	GoType string
+
+	// Type is the OpenAPI type
+	Type string
+
+	// Format is the optional OpenAPI format
+	Format string
+}
+
 // Tag represents an API tag definition
 type Tag struct {
 	// Name is the tag name
@@ -305,4 +434,10 @@ type Tag struct {
 
 	// Description is the tag description
 	Description string
+
+	// Parent is the name of the parent tag (OpenAPI 3.2)
+	Parent string
+
+	// Kind is the kind of tag, e.g. nav, badge or audience (OpenAPI 3.2)
+	Kind string
 }
@@ -0,0 +1,204 @@
+package resolver
+
+import (
+	"go/token"
+	"go/types"
+	"slices"
+	"sort"
+
+	"github.com/wontaeyang/go-specgen/pkg/parser"
+)
+
+// applyComposition sets the composition of a polymorphic field: the schemas listed by @oneOf or @anyOf,
+// or, for interface types with a @discriminator, the @schema types implementing the interface.
+// The composition of arrays and maps is set on their innermost element.
+func (r *Resolver) applyComposition(field *ResolvedField, annotation *parser.Field, t types.Type, pos token.Pos, pkgPath string) {
+	if annotation == nil {
+		return
+	}
+
+	var c *Composition
+	switch {
+	case len(annotation.OneOf) > 0:
+		if len(annotation.AnyOf) > 0 {
+			r.warnf(pos, r.subpath(field.GoName), "ignored-anyof", "@anyOf is ignored when @oneOf is given")
+		}
+		c = &Composition{Kind: "oneOf", Schemas: slices.Clone(annotation.OneOf)}
+	case len(annotation.AnyOf) > 0:
+		c = &Composition{Kind: "anyOf", Schemas: slices.Clone(annotation.AnyOf)}
+	case annotation.Discriminator != nil:
+		iface := r.implementedInterface(t)
+		if iface == nil {
+			r.warnf(pos, r.subpath(field.GoName), "ignored-discriminator", "@discriminator requires @oneOf, @anyOf or an interface type")
+			return
+		}
+		c = &Composition{Kind: "oneOf", iface: iface}
+	default:
+		return
+	}
+	c.Discriminator = discriminatorFor(annotation.Discriminator)
+	c.pkgPath = pkgPath
+
+	if field.Type != nil && (field.Type.Items != nil || field.Type.MapValue != nil) {
+		field.Type.Innermost().Composition = c
+		return
+	}
+	field.Composition = c
+}
+
+// implementedInterface returns the interface of a polymorphic field type whose implementations are
+// discovered, following pointers, slices, arrays and maps to their elements
+// Returns nil for non-interface types, empty interfaces (any) and interfaces declared as @schema,
+// which are referenced and compose their implementations themselves.
+func (r *Resolver) implementedInterface(t types.Type) *types.Interface {
+	t = innermostElem(t)
+	if named, ok := types.Unalias(t).(*types.Named); ok && r.schemaNames[qualifiedTypeName(named)] {
+		return nil
+	}
+
+	iface, ok := t.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return nil
+	}
+	return iface
+}
+
+// innermostElem follows pointers, slices, arrays and maps to their innermost element type
+func innermostElem(t types.Type) types.Type {
+	for {
+		switch tt := types.Unalias(t).(type) {
+		case *types.Pointer:
+			t = tt.Elem()
+		case *types.Slice:
+			t = tt.Elem()
+		case *types.Array:
+			t = tt.Elem()
+		case *types.Map:
+			t = tt.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// discriminatorFor converts a parsed @discriminator annotation
+func discriminatorFor(d *parser.Discriminator) *Discriminator {
+	if d == nil {
+		return nil
+	}
+
+	resolved := &Discriminator{PropertyName: d.PropertyName}
+	for _, m := range d.Mapping {
+		resolved.Mapping = append(resolved.Mapping, &DiscriminatorMapping{Value: m.Value, Schema: m.Schema})
+	}
+	return resolved
+}
+
+// resolveCompositions completes the compositions of all schemas once every schema is resolved:
+// schema names become component names, interface schemas list their implementations,
+// and discriminators get a mapping entry for every alternative
+func (r *Resolver) resolveCompositions(schemas map[string]*ResolvedSchema) {
+	for _, schema := range schemas {
+		if schema.Value != nil {
+			r.resolveFieldCompositions([]*ResolvedField{schema.Value}, schemas)
+		}
+		r.resolveFieldCompositions(schema.Fields, schemas)
+		if schema.AdditionalProperties != nil {
+			r.resolveTypeCompositions(schema.AdditionalProperties, schemas)
+		}
+	}
+}
+
+// resolveFieldCompositions resolves the compositions of fields, including nested inline fields
+func (r *Resolver) resolveFieldCompositions(fields []*ResolvedField, schemas map[string]*ResolvedSchema) {
+	for _, field := range fields {
+		if field.Composition != nil {
+			r.resolveComposition(field.Composition, schemas)
+		}
+		if field.Type != nil {
+			r.resolveTypeCompositions(field.Type, schemas)
+			continue
+		}
+		r.resolveFieldCompositions(field.InlineFields, schemas)
+	}
+}
+
+// resolveTypeCompositions resolves the compositions within a type descriptor
+func (r *Resolver) resolveTypeCompositions(desc *TypeDescriptor, schemas map[string]*ResolvedSchema) {
+	for ; desc != nil; desc = desc.Items {
+		if desc.MapValue != nil {
+			r.resolveTypeCompositions(desc.MapValue, schemas)
+		}
+		if desc.Composition != nil {
+			r.resolveComposition(desc.Composition, schemas)
+		}
+		r.resolveFieldCompositions(desc.InlineFields, schemas)
+	}
+}
+
+// resolveComposition resolves the alternatives and discriminator mapping of a composition
+func (r *Resolver) resolveComposition(c *Composition, schemas map[string]*ResolvedSchema) {
+	if c.iface != nil {
+		c.Schemas = r.implementingSchemas(c.iface, schemas)
+	}
+	for i, name := range c.Schemas {
+		c.Schemas[i] = r.schemaComponentName(name, c.pkgPath, schemas)
+	}
+
+	if c.Discriminator == nil {
+		return
+	}
+
+	// Explicit mappings first, then the remaining alternatives in order
+	mapped := make(map[string]bool)
+	for _, m := range c.Discriminator.Mapping {
+		if m.Schema == "" {
+			continue
+		}
+		m.Schema = r.schemaComponentName(m.Schema, c.pkgPath, schemas)
+		mapped[m.Schema] = true
+	}
+	for _, name := range c.Schemas {
+		if mapped[name] {
+			continue
+		}
+		c.Discriminator.Mapping = append(c.Discriminator.Mapping, &DiscriminatorMapping{
+			Value:  discriminatorValue(schemas[name], name, c.Discriminator.PropertyName),
+			Schema: name,
+		})
+	}
+}
+
+// implementingSchemas returns the component names of the non-interface @schema types implementing
+// an interface (by value or pointer receiver), sorted by name
+func (r *Resolver) implementingSchemas(iface *types.Interface, schemas map[string]*ResolvedSchema) []string {
+	var names []string
+	for name, schema := range schemas {
+		if schema.IsGeneric {
+			continue
+		}
+		obj := r.packageFor(schema.PkgPath).Types.Scope().Lookup(schema.GoTypeName)
+		if obj == nil || types.IsInterface(obj.Type()) {
+			continue
+		}
+		if types.Implements(obj.Type(), iface) || types.Implements(types.NewPointer(obj.Type()), iface) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// discriminatorValue returns the discriminator value of a schema: the only enum value of its
+// discriminator property (e.g. @enum card, or a type with a single constant), or the schema
+// name, which OpenAPI uses when a value is not mapped
+func discriminatorValue(schema *ResolvedSchema, name, propertyName string) string {
+	if schema != nil {
+		for _, field := range schema.Fields {
+			if field.Name == propertyName && len(field.Enum) == 1 {
+				return field.Enum[0]
+			}
+		}
+	}
+	return name
+}
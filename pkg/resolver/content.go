@@ -0,0 +1,106 @@
+package resolver
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/wontaeyang/go-specgen/pkg/parser"
+)
+
+// binaryContentType is the content type of raw bytes (@contentType binary)
+const binaryContentType = "application/octet-stream"
+
+// contentDefaults are the content types of bodies without @contentType
+type contentDefaults struct {
+	Requests  []string // @consumes, else @defaultContentType, else application/json
+	Responses []string // @produces, else @defaultContentType, else application/json
+}
+
+// newContentDefaults returns the default content types of an API (api may be nil)
+func newContentDefaults(api *ResolvedAPI) contentDefaults {
+	fallback := []string{"application/json"}
+	if api != nil && api.DefaultContentType != "" {
+		fallback = []string{api.DefaultContentType}
+	}
+
+	defaults := contentDefaults{Requests: fallback, Responses: fallback}
+	if api != nil && len(api.Consumes) > 0 {
+		defaults.Requests = api.Consumes
+	}
+	if api != nil && len(api.Produces) > 0 {
+		defaults.Responses = api.Produces
+	}
+	return defaults
+}
+
+// resolveContent resolves the media types of a request or response, in order:
+// the @contentType list sharing the body, then the @content blocks with their own body
+// A body without @contentType uses the default content types not already given by a @content block.
+// Returns the media types and the resolved shared body.
+func (r *Resolver) resolveContent(contentTypes []string, body *parser.Body, blocks []*parser.Content, defaults []string, pkgPath string, schemas map[string]*ResolvedSchema) ([]*ResolvedContent, *ResolvedBody) {
+	resolvedBody := r.resolveBody(body, pkgPath, schemas)
+
+	if len(contentTypes) == 0 && body != nil {
+		for _, contentType := range defaults {
+			if !slices.ContainsFunc(blocks, func(block *parser.Content) bool { return block.ContentType == contentType }) {
+				contentTypes = append(contentTypes, contentType)
+			}
+		}
+	}
+
+	var content []*ResolvedContent
+	for _, contentType := range contentTypes {
+		contentBody := resolvedBody
+		if contentBody == nil && contentType == binaryContentType {
+			// @contentType binary without @body is a raw file
+			contentBody = r.resolveBody(&parser.Body{Schema: "binary"}, pkgPath, schemas)
+		}
+		content = append(content, &ResolvedContent{ContentType: contentType, Body: contentBody})
+	}
+	for _, block := range blocks {
+		blockBody := resolvedBody
+		if block.Body != nil {
+			blockBody = r.resolveBody(block.Body, pkgPath, schemas)
+		}
+		content = append(content, &ResolvedContent{ContentType: block.ContentType, Body: blockBody})
+	}
+	return content, resolvedBody
+}
+
+// MediaTypes returns the media types of a request body: Content, or ContentType with Body
+func (b *ResolvedRequestBody) MediaTypes() []*ResolvedContent {
+	if len(b.Content) > 0 {
+		return b.Content
+	}
+	return []*ResolvedContent{{ContentType: b.ContentType, Body: b.Body}}
+}
+
+// MediaTypes returns the media types of a response: Content, or ContentType with Body
+func (r *ResolvedResponse) MediaTypes() []*ResolvedContent {
+	if len(r.Content) > 0 {
+		return r.Content
+	}
+	return []*ResolvedContent{{ContentType: r.ContentType, Body: r.Body}}
+}
+
+// contentDispositionHeader returns the Content-Disposition header of a download (@attachment)
+func contentDispositionHeader(attachment *parser.Attachment) *ResolvedParameter {
+	example := "attachment"
+	if attachment.Filename != "" {
+		example += "; filename=" + strconv.Quote(attachment.Filename)
+	}
+
+	return &ResolvedParameter{
+		Name: "Content-Disposition",
+		Type: "header",
+		Fields: []*ResolvedField{{
+			Name:        "Content-Disposition",
+			GoName:      "ContentDisposition",
+			GoType:      "string",
+			OpenAPIType: "string",
+			Description: "Downloads the response as a file",
+			Example:     example,
+			Required:    true,
+		}},
+	}
+}
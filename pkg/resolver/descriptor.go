@@ -0,0 +1,219 @@
+package resolver
+
+import (
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// describeType builds the type descriptor of a Go type, following slices, arrays, maps and
+// pointers down to their elements. Named @schema types, mapped types and types with custom
+// marshaling are leaves; anonymous structs are resolved to inline fields.
+func (r *Resolver) describeType(t types.Type, schemaNames map[string]bool) *TypeDescriptor {
+	return r.describeTypeVisiting(t, schemaNames, make(map[*types.Named]bool))
+}
+
+// describeTypeVisiting builds a type descriptor, stopping at named types already being described
+// (e.g. type Tree map[string]Tree) to keep recursive types finite
+func (r *Resolver) describeTypeVisiting(t types.Type, schemaNames map[string]bool, visiting map[*types.Named]bool) *TypeDescriptor {
+	desc := &TypeDescriptor{GoType: t.String()}
+
+	switch tt := types.Unalias(t).(type) {
+	case *types.Pointer:
+		elem := r.describeTypeVisiting(tt.Elem(), schemaNames, visiting)
+		elem.Nullable = !isFileFormat(elem.Format)
+		return elem
+
+	case *types.Slice:
+		// []byte is a base64 string leaf
+		if !isByteType(tt.Elem()) {
+			desc.OpenAPIType = "array"
+			desc.Items = r.describeTypeVisiting(tt.Elem(), schemaNames, visiting)
+			return desc
+		}
+
+	case *types.Array:
+		desc.OpenAPIType = "array"
+		desc.Items = r.describeTypeVisiting(tt.Elem(), schemaNames, visiting)
+		length := int(tt.Len())
+		desc.Length = &length
+		return desc
+
+	case *types.Map:
+		desc.OpenAPIType = "object"
+		desc.MapValue = r.describeTypeVisiting(tt.Elem(), schemaNames, visiting)
+		return desc
+
+	case *types.Struct:
+		desc.OpenAPIType = "object"
+		desc.InlineFields = r.resolveAnonymousStruct(tt, schemaNames)
+		return desc
+
+	case *types.Named:
+		if r.lookupTypeMapping(tt.Obj()) != nil || marshalerOf(tt) != notMarshaler {
+			break
+		}
+		if _, isStruct := tt.Underlying().(*types.Struct); isStruct {
+			// Named structs are referenced, never inlined (unresolved ones are reported separately)
+			desc.OpenAPIType = "object"
+			return desc
+		}
+		if schemaNames[qualifiedTypeName(tt)] {
+			break
+		}
+		if visiting[tt] {
+			desc.IsAnyValue = true
+			return desc
+		}
+
+		// Named containers (e.g. type Tags []Tag) take the shape of their underlying type
+		visiting[tt] = true
+		underlying := r.describeTypeVisiting(tt.Underlying(), schemaNames, visiting)
+		delete(visiting, tt)
+		underlying.GoType = desc.GoType
+		return underlying
+	}
+
+	applyTypeInfoToDescriptor(desc, r.resolveType(t))
+	return desc
+}
+
+// applyTypeInfoToDescriptor copies the resolved type information of a leaf type to a descriptor
+func applyTypeInfoToDescriptor(desc *TypeDescriptor, info *TypeInfo) {
+	desc.OpenAPIType = info.OpenAPIType
+	desc.Format = info.Format
+	desc.Pattern = info.Pattern
+	desc.Nullable = desc.Nullable || info.IsNullable
+	desc.IsAnyValue = info.IsAnyValue
+	desc.MappedSchema = info.Schema
+}
+
+// resolveFieldType resolves the type of a field into its descriptor and the flattened
+// top-level type information (OpenAPI type, items type, inline fields of anonymous structs)
+func (r *Resolver) resolveFieldType(field *ResolvedField, t types.Type, schemaNames map[string]bool) {
+	// Fields of anonymous structs are located under the field (e.g. @schema[Order].Billing.Method)
+	restore := r.at(r.subpath(field.GoName))
+	desc := r.describeType(t, schemaNames)
+	restore()
+	field.Type = desc
+
+	switch {
+	case desc.InlineFields != nil:
+		// Anonymous struct
+		field.InlineFields = desc.InlineFields
+		field.OpenAPIType = "object"
+	case desc.Items != nil && desc.Items.InlineFields != nil:
+		// Slice of anonymous struct
+		field.IsArray = true
+		field.OpenAPIType = "array"
+		field.ItemsType = "object"
+	case desc.MapValue != nil && desc.MapValue.InlineFields != nil:
+		// Map with anonymous struct values
+		field.IsMap = true
+		field.OpenAPIType = "object"
+	default:
+		// Resolve Go type to OpenAPI type
+		applyTypeInfo(field, r.resolveType(t))
+
+		// Derive enum values from typed constants (overridden by an explicit @enum)
+		r.applyConstEnum(field, t)
+	}
+}
+
+// describeBodyType builds the type descriptor of a body type written in an annotation
+// (e.g. "User", "[]User", "map[string][]models.User", "*[]User", "[][]string", "User | Organization")
+// Type names are resolved to component names relative to pkgPath.
+func (r *Resolver) describeBodyType(schema string, pkgPath string, schemas map[string]*ResolvedSchema) *TypeDescriptor {
+	schema = strings.TrimSpace(schema)
+	desc := &TypeDescriptor{GoType: schema}
+
+	// Alternatives (User | Organization)
+	if alternatives := strings.Split(schema, "|"); len(alternatives) > 1 {
+		for _, alternative := range alternatives {
+			desc.OneOf = append(desc.OneOf, r.describeBodyType(alternative, pkgPath, schemas))
+		}
+		return desc
+	}
+
+	switch {
+	case strings.HasPrefix(schema, "*"):
+		elem := r.describeBodyType(schema[1:], pkgPath, schemas)
+		elem.Nullable = !isFileFormat(elem.Format)
+		return elem
+
+	case schema == "binary":
+		// Raw file contents (downloads, uploads)
+		desc.OpenAPIType = "string"
+		desc.Format = "binary"
+		return desc
+
+	case schema == "[]byte" || schema == "[]uint8":
+		desc.OpenAPIType = "string"
+		desc.Format = "byte"
+		return desc
+
+	case strings.HasPrefix(schema, "["):
+		end := strings.Index(schema, "]")
+		if end < 0 {
+			break
+		}
+		if end > 1 {
+			length, err := strconv.Atoi(schema[1:end])
+			if err != nil {
+				break
+			}
+			desc.Length = &length
+		}
+		desc.OpenAPIType = "array"
+		desc.Items = r.describeBodyType(schema[end+1:], pkgPath, schemas)
+		return desc
+
+	case strings.HasPrefix(schema, "map["):
+		end := strings.Index(schema, "]")
+		if end < 0 {
+			break
+		}
+		desc.OpenAPIType = "object"
+		desc.MapValue = r.describeBodyType(schema[end+1:], pkgPath, schemas)
+		return desc
+
+	case schema == "any" || schema == "interface{}":
+		desc.IsAnyValue = true
+		return desc
+	}
+
+	// Predeclared types (string, int64, ...)
+	if obj, ok := types.Universe.Lookup(schema).(*types.TypeName); ok {
+		if _, isBasic := obj.Type().(*types.Basic); isBasic {
+			applyTypeInfoToDescriptor(desc, r.resolveType(obj.Type()))
+			return desc
+		}
+	}
+
+	// Mapped types, e.g. files (io.Reader, *os.File) or time.Time, unless a @schema has the name
+	ref := r.schemaComponentName(schema, pkgPath, schemas)
+	if _, isSchema := schemas[ref]; !isSchema {
+		if mapping := r.lookupAnnotationTypeMapping(schema, pkgPath); mapping != nil {
+			applyTypeInfoToDescriptor(desc, mapping.typeInfo())
+			return desc
+		}
+	}
+
+	desc.OpenAPIType = "object"
+	desc.Ref = ref
+	return desc
+}
+
+// Innermost returns the leaf of a descriptor, following array items and map values
+func (d *TypeDescriptor) Innermost() *TypeDescriptor {
+	for {
+		switch {
+		case d.Items != nil:
+			d = d.Items
+		case d.MapValue != nil:
+			d = d.MapValue
+		default:
+			return d
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package resolver
+
+import (
+	"go/types"
+	"sort"
+
+	"github.com/wontaeyang/go-specgen/pkg/parser"
+)
+
+// structField is a field visible in the encoded form of a struct,
+// either declared directly or promoted from an embedded struct
+type structField struct {
+	field  *types.Var
+	tag    string
+	name   string // Encoded name, used for shadowing
+	tagged bool   // Name comes from a struct tag
+	index  []int  // Field index sequence from the outer struct (like reflect.StructField.Index)
+
+	// owner is the named struct declaring the field (nil for anonymous structs)
+	owner *types.Named
+
+	// embedded is the outermost embedded type the field was promoted from (nil for direct fields)
+	embedded *types.Named
+}
+
+// depth returns the embedding depth of the field (0 for fields declared directly)
+func (f *structField) depth() int {
+	return len(f.index) - 1
+}
+
+// collectStructFields returns the fields of a struct following encoding/json's rules for embedding:
+//   - Fields of an embedded struct (or pointer to struct) without a tag name are promoted into the parent
+//   - A field at a shallower depth shadows fields with the same name at deeper depths
+//   - Among fields at the same depth, a single tagged field wins over untagged ones
+//   - Any remaining conflict drops all fields with that name
+//   - Fields tagged inline (encoding/json/v2) are promoted like embedded structs, whatever their name
+//
+// tagKey selects the struct tag that names fields: "json" uses the schema fallback chain
+// (json -> xml -> Go field name), parameter tags ("query", "header", ...) fall back to the Go field name.
+// Fields are returned in encoding order, with promoted fields at the position of their embedded field.
+func collectStructFields(st *types.Struct, owner *types.Named, tagKey string) []*structField {
+	fields, _ := collectStructMembers(st, owner, tagKey)
+	return fields
+}
+
+// collectStructMembers returns the fields of a struct like collectStructFields, along with the
+// shallowest field collecting its undeclared members (an inlined map or unknown field, encoding/json/v2)
+func collectStructMembers(st *types.Struct, owner *types.Named, tagKey string) ([]*structField, *types.Var) {
+	type level struct {
+		st       *types.Struct
+		owner    *types.Named
+		embedded *types.Named
+		index    []int
+	}
+
+	var fields []*structField
+	var unknown *types.Var
+	visited := make(map[*types.Named]bool)
+
+	current := []level{{st: st, owner: owner}}
+	for len(current) > 0 {
+		var next []level
+
+		for _, lvl := range current {
+			// A type embedded at a shallower depth already contributed its fields.
+			// The same type embedded twice at one depth is walked twice, so its fields conflict.
+			if lvl.owner != nil && visited[lvl.owner] {
+				continue
+			}
+
+			for i := 0; i < lvl.st.NumFields(); i++ {
+				field := lvl.st.Field(i)
+				tag := lvl.st.Tag(i)
+				index := append(append([]int(nil), lvl.index...), i)
+
+				name, tagged := fieldTagName(tag, field.Name(), tagKey)
+				if name == "" {
+					continue // Explicitly skipped (e.g., json:"-")
+				}
+
+				jt := jsonTag{}
+				if tagKey == "json" {
+					jt = parseJSONTag(tag)
+				}
+				inline := jt.Inline && (field.Exported() || field.Embedded())
+
+				if (field.Embedded() && !tagged) || inline {
+					if named, embeddedStruct := embeddedStructType(field.Type()); embeddedStruct != nil {
+						embedded := lvl.embedded
+						if embedded == nil {
+							embedded = named
+						}
+						next = append(next, level{st: embeddedStruct, owner: named, embedded: embedded, index: index})
+						continue
+					}
+				}
+
+				// Inlined maps and unknown fields collect undeclared members, they are not properties
+				if jt.Inline || jt.Unknown {
+					if unknown == nil && field.Exported() {
+						unknown = field
+					}
+					continue
+				}
+
+				// Unexported fields (including unexported non-struct embedded types) are never encoded
+				if !field.Exported() {
+					continue
+				}
+
+				fields = append(fields, &structField{
+					field:    field,
+					tag:      tag,
+					name:     name,
+					tagged:   tagged,
+					index:    index,
+					owner:    lvl.owner,
+					embedded: lvl.embedded,
+				})
+			}
+		}
+
+		for _, lvl := range current {
+			if lvl.owner != nil {
+				visited[lvl.owner] = true
+			}
+		}
+		current = next
+	}
+
+	return dominantFields(fields), unknown
+}
+
+// describeUnknownMembers describes the values of the undeclared members collected by a field:
+// the values of a map, or any value (e.g. jsontext.Value)
+func (r *Resolver) describeUnknownMembers(field *types.Var, schemaNames map[string]bool) *TypeDescriptor {
+	t := field.Type()
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if m, ok := t.Underlying().(*types.Map); ok {
+		return r.describeType(m.Elem(), schemaNames)
+	}
+	return &TypeDescriptor{GoType: t.String(), IsAnyValue: true}
+}
+
+// dominantFields applies encoding/json's shadowing rules and sorts fields by index sequence
+func dominantFields(fields []*structField) []*structField {
+	byName := make(map[string][]*structField)
+	var order []string
+	for _, f := range fields {
+		if _, ok := byName[f.name]; !ok {
+			order = append(order, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	result := make([]*structField, 0, len(order))
+	for _, name := range order {
+		candidates := byName[name]
+
+		// Only fields at the shallowest depth compete
+		minDepth := candidates[0].depth()
+		for _, f := range candidates {
+			if f.depth() < minDepth {
+				minDepth = f.depth()
+			}
+		}
+
+		var shallowest, tagged []*structField
+		for _, f := range candidates {
+			if f.depth() == minDepth {
+				shallowest = append(shallowest, f)
+				if f.tagged {
+					tagged = append(tagged, f)
+				}
+			}
+		}
+
+		switch {
+		case len(shallowest) == 1:
+			result = append(result, shallowest[0])
+		case len(tagged) == 1:
+			result = append(result, tagged[0])
+		}
+		// Otherwise the name is ambiguous and encoding/json omits it
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i].index, result[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	return result
+}
+
+// fieldTagName returns the encoded name of a field and whether it comes from a struct tag
+// Returns "" if the field is explicitly skipped
+func fieldTagName(tag, goFieldName, tagKey string) (string, bool) {
+	if tagKey == "json" {
+		name := resolveFieldNameFromTag(tag, goFieldName)
+		if name == "" {
+			return "", false
+		}
+		for _, key := range SupportedTags {
+			if extractTagName(tag, key) == name {
+				return name, true
+			}
+		}
+		return name, false
+	}
+
+	// For parameter types, "-" and empty fall back to the Go field name
+	name := extractTagName(tag, tagKey)
+	if name == "" || name == "-" {
+		return goFieldName, false
+	}
+	return name, true
+}
+
+// embeddedStructType returns the struct type of an embedded field (unwrapping one pointer level)
+// named is nil for embedded aliases of anonymous structs; the struct is nil if the type is not a struct
+func embeddedStructType(t types.Type) (*types.Named, *types.Struct) {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	t = types.Unalias(t)
+
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil, nil
+	}
+
+	named, _ := t.(*types.Named)
+	return named, st
+}
+
+// qualifiedTypeName returns the package-qualified name of a named type (e.g. "github.com/acme/models.User")
+func qualifiedTypeName(named *types.Named) string {
+	if named == nil {
+		return ""
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// structFieldAnnotation returns the @field annotation of a collected field
+// Fields declared directly use the parsed annotations of the struct; promoted fields
+// use the annotations declared on the embedded struct
+func (r *Resolver) structFieldAnnotation(sf *structField, annotations []*parser.Field) (*parser.Field, error) {
+	if sf.depth() == 0 {
+		for _, f := range annotations {
+			if f.GoName == sf.field.Name() {
+				return f, nil
+			}
+		}
+		return nil, nil
+	}
+	return r.promotedFieldAnnotation(sf)
+}
+
+// anonymousFieldAnnotation returns the @field annotation of a field collected from an anonymous struct,
+// looked up by position in the comments of the declaring package
+func (r *Resolver) anonymousFieldAnnotation(sf *structField) (*parser.Field, error) {
+	if sf.depth() > 0 {
+		return r.promotedFieldAnnotation(sf)
+	}
+	if sf.field.Pkg() == nil {
+		return nil, nil
+	}
+
+	comments := r.declaringComments(sf.field.Pkg().Path())
+	if comments == nil {
+		return nil, nil
+	}
+	return parser.ParseField(sf.field.Name(), comments.FieldCommentsAt[sf.field.Pos()])
+}
+
+// promotedFieldAnnotation parses the @field annotation of a field promoted from an embedded struct,
+// looked up in the comments of the package declaring the embedded struct
+func (r *Resolver) promotedFieldAnnotation(sf *structField) (*parser.Field, error) {
+	if sf.owner == nil || sf.owner.Obj().Pkg() == nil {
+		return nil, nil
+	}
+
+	comments := r.declaringComments(sf.owner.Obj().Pkg().Path())
+	if comments == nil {
+		return nil, nil
+	}
+
+	structName := sf.owner.Obj().Name()
+	return parser.ParseField(sf.field.Name(), comments.FieldComments[structName][sf.field.Name()])
+}
+
+// resolvePromotedField resolves a field promoted from an embedded struct
+// tagType is "json" for bodies or the parameter type ("path", "query", "header", "cookie")
+func (r *Resolver) resolvePromotedField(sf *structField, tagType string, schemaNames map[string]bool) (*ResolvedField, error) {
+	annotation, err := r.promotedFieldAnnotation(sf)
+	if err != nil {
+		return nil, r.fieldError(sf.field, "failed to parse annotation of field", err)
+	}
+
+	var resolved *ResolvedField
+	if tagType == "json" {
+		resolved, err = r.resolveField(sf.field, sf.tag, annotation, schemaNames)
+	} else {
+		resolved, err = r.resolveFieldWithParamType(sf.field, sf.tag, annotation, tagType)
+	}
+	if err != nil {
+		return nil, r.fieldError(sf.field, "failed to resolve field", err)
+	}
+	if resolved != nil {
+		resolved.EmbeddedFrom = qualifiedTypeName(sf.embedded)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,46 @@
+package resolver
+
+import (
+	"github.com/wontaeyang/go-specgen/pkg/parser"
+)
+
+// applyPartEncoding sets the encoding of a field's part in multipart and form bodies
+// from its @contentType and @header annotations
+func applyPartEncoding(field *ResolvedField, annotation *parser.Field) {
+	if len(annotation.ContentTypes) == 0 && len(annotation.Headers) == 0 {
+		return
+	}
+	field.Encoding = &PartEncoding{
+		ContentTypes: annotation.ContentTypes,
+		HeaderParams: annotation.Headers,
+	}
+}
+
+// partEncoding returns the encoding of a field's part, creating it if needed
+func partEncoding(field *ResolvedField) *PartEncoding {
+	if field.Encoding == nil {
+		field.Encoding = &PartEncoding{}
+	}
+	return field.Encoding
+}
+
+// resolvePartHeaders resolves the @header references of part encodings to header structs
+// Only top-level properties have parts; unknown references are reported by validation.
+func resolvePartHeaders(fields []*ResolvedField, parameters map[string]*ResolvedParameter) {
+	for _, field := range fields {
+		if field.Encoding == nil {
+			continue
+		}
+		field.Encoding.Headers = nil
+		for _, ref := range field.Encoding.HeaderParams {
+			if param, ok := parameters[ref]; ok && param.Type == "header" {
+				field.Encoding.Headers = append(field.Encoding.Headers, param)
+			}
+		}
+	}
+}
+
+// isFileFormat reports whether a string format describes file contents
+func isFileFormat(format string) bool {
+	return format == "binary"
+}
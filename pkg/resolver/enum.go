@@ -0,0 +1,133 @@
+package resolver
+
+import (
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/wontaeyang/go-specgen/pkg/parser"
+)
+
+// constEnum holds enum values derived from the typed constants of a named type
+type constEnum struct {
+	values       []string
+	varNames     []string
+	descriptions []string
+}
+
+// constEnumFor derives an enum from the exported constants declared with a named type
+// in the type's own package (e.g. `const TaskPending TaskStatus = "pending"` or iota blocks).
+// Values are returned in declaration order; constants repeating an earlier value are skipped.
+// Only types of the scanned packages and the main module have their constants taken as enums:
+// the constants of library types (time.Duration, os.FileMode) are not the only valid values.
+// Returns nil if t is not a named string or integer type, or has no such constants.
+func (r *Resolver) constEnumFor(t types.Type) *constEnum {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || !r.isOwnPackage(named.Obj().Pkg().Path()) {
+		return nil
+	}
+
+	if cached, ok := r.enumCache[named]; ok {
+		return cached
+	}
+	r.enumCache[named] = nil
+
+	basic, ok := named.Underlying().(*types.Basic)
+	if !ok || basic.Info()&(types.IsString|types.IsInteger) == 0 {
+		return nil
+	}
+
+	// Constants of types with custom marshaling don't encode as their values
+	if marshalerOf(named) != notMarshaler {
+		return nil
+	}
+
+	pkg := named.Obj().Pkg()
+	var consts []*types.Const
+	for _, name := range pkg.Scope().Names() {
+		c, ok := pkg.Scope().Lookup(name).(*types.Const)
+		if !ok || !c.Exported() || !types.Identical(c.Type(), named) {
+			continue
+		}
+		consts = append(consts, c)
+	}
+	if len(consts) == 0 {
+		return nil
+	}
+
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	comments := r.declaringComments(pkg.Path())
+
+	enum := &constEnum{}
+	seen := make(map[string]bool)
+	for _, c := range consts {
+		value := constValueString(c.Val())
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+
+		var description string
+		if comments != nil {
+			description = constDescription(comments.ConstComments[c.Name()])
+		}
+
+		enum.values = append(enum.values, value)
+		enum.varNames = append(enum.varNames, c.Name())
+		enum.descriptions = append(enum.descriptions, description)
+	}
+
+	r.enumCache[named] = enum
+	return enum
+}
+
+// constDescription joins the plain text lines of a constant's doc comment, skipping annotations
+func constDescription(cb *parser.CommentBlock) string {
+	if cb == nil {
+		return ""
+	}
+
+	var lines []string
+	for _, line := range cb.Lines {
+		if line == "" || strings.HasPrefix(line, "@") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
+
+// constValueString formats a constant value as an enum literal
+func constValueString(v constant.Value) string {
+	if v.Kind() == constant.String {
+		return constant.StringVal(v)
+	}
+	return v.ExactString()
+}
+
+// applyConstEnum sets the enum of a field from the typed constants of its Go type
+// For slices, the element type is used (the enum applies to the items)
+func (r *Resolver) applyConstEnum(field *ResolvedField, t types.Type) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if slice, ok := types.Unalias(t).(*types.Slice); ok {
+		t = slice.Elem()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+	}
+
+	enum := r.constEnumFor(t)
+	if enum == nil {
+		return
+	}
+
+	field.Enum = enum.values
+	field.EnumVarNames = enum.varNames
+	field.EnumDescriptions = enum.descriptions
+}
@@ -83,7 +83,11 @@ func ParseInlineAnnotation(comment *parser.CommentBlock, annotationType string)
 		return nil, nil
 	}
 
-	return parser.ParseAnnotationBlock(lines, "@"+annotationType, schemaNode)
+	parsed, err := parser.ParseAnnotationBlock(lines, "@"+annotationType, schemaNode)
+	if err != nil {
+		return nil, parser.AnnotationError(comment, "", err)
+	}
+	return parsed, nil
 }
 
 // extractInlineBlockContent extracts the annotation block content from comment lines.
@@ -0,0 +1,225 @@
+package resolver
+
+import (
+	"go/token"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonTag is a parsed json struct tag, including the options added by encoding/json/v2
+type jsonTag struct {
+	Name      string // Encoded name, "" to use the Go field name
+	Skip      bool   // json:"-"
+	OmitEmpty bool
+	OmitZero  bool
+	String    bool   // Numbers and booleans are encoded as JSON strings
+	Inline    bool   // v2: the fields of the value are flattened into the parent object
+	Unknown   bool   // v2: the field holds unknown object members
+	Format    string // v2: format:<value>
+	Case      string // v2: case:ignore or case:strict (only affects decoding)
+}
+
+// parseJSONTag parses the json tag of a struct tag
+// Names and format values may be single-quoted as in encoding/json/v2 (e.g. json:"'a,b',format:'2006-01-02'").
+func parseJSONTag(tag string) jsonTag {
+	value, ok := reflect.StructTag(tag).Lookup("json")
+	if !ok {
+		return jsonTag{}
+	}
+	if value == "-" {
+		return jsonTag{Skip: true}
+	}
+
+	parts := splitTagOptions(value)
+	jt := jsonTag{Name: unquoteTagOption(parts[0])}
+	for _, opt := range parts[1:] {
+		key, arg, _ := strings.Cut(strings.TrimSpace(opt), ":")
+		switch key {
+		case "omitempty":
+			jt.OmitEmpty = true
+		case "omitzero":
+			jt.OmitZero = true
+		case "string":
+			jt.String = true
+		case "inline":
+			jt.Inline = true
+		case "unknown":
+			jt.Unknown = true
+		case "format":
+			jt.Format = unquoteTagOption(arg)
+		case "case":
+			jt.Case = arg
+		}
+	}
+	return jt
+}
+
+// splitTagOptions splits a tag value on commas outside single-quoted strings
+func splitTagOptions(value string) []string {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '\'':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts = append(parts, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// unquoteTagOption removes the single quotes around a tag name or option value
+func unquoteTagOption(s string) string {
+	if len(s) < 2 || s[0] != '\'' || s[len(s)-1] != '\'' {
+		return s
+	}
+	if unquoted, err := strconv.Unquote(`"` + strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`) + `"`); err == nil {
+		return unquoted
+	}
+	return s[1 : len(s)-1]
+}
+
+// omitsEmpty reports whether a struct tag makes a field optional: json omitempty or omitzero,
+// or omitempty in any other tag (xml, query, validate, ...)
+func omitsEmpty(tag string) bool {
+	jt := parseJSONTag(tag)
+	if jt.OmitEmpty || jt.OmitZero {
+		return true
+	}
+
+	st := reflect.StructTag(tag)
+	for _, key := range tagKeys(tag) {
+		if key == "json" {
+			continue
+		}
+		// Validation tags hold rules only; other tags start with a name
+		options := strings.Split(st.Get(key), ",")
+		if !slices.Contains(ValidationTags, key) {
+			options = options[1:]
+		}
+		if slices.Contains(options, "omitempty") {
+			return true
+		}
+	}
+	return false
+}
+
+// tagKeys returns the keys of a struct tag in the conventional key:"value" format
+func tagKeys(tag string) []string {
+	var keys []string
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		colon := strings.Index(tag, ":")
+		if colon <= 0 || colon+1 >= len(tag) || tag[colon+1] != '"' {
+			break
+		}
+		value, err := strconv.QuotedPrefix(tag[colon+1:])
+		if err != nil {
+			break
+		}
+		keys = append(keys, tag[:colon])
+		tag = tag[colon+1+len(value):]
+	}
+	return keys
+}
+
+// applyJSONStringOption applies the json ",string" option: encoding/json writes
+// integer, number and boolean fields (and pointers to them) as JSON strings
+func applyJSONStringOption(field *ResolvedField, tag string) {
+	if field.IsArray || !parseJSONTag(tag).String {
+		return
+	}
+	switch field.OpenAPIType {
+	case "integer", "number", "boolean":
+		field.OpenAPIType = "string"
+		field.Format = ""
+	}
+}
+
+// jsonTimeFormats maps json/v2 format options of time.Time to OpenAPI types and formats
+var jsonTimeFormats = map[string]specialTypeMapping{
+	"RFC3339":     {openAPIType: "string", format: "date-time"},
+	"RFC3339Nano": {openAPIType: "string", format: "date-time"},
+	"DateOnly":    {openAPIType: "string", format: "date"},
+	"unix":        {openAPIType: "number", format: ""},
+	"unixmilli":   {openAPIType: "number", format: ""},
+	"unixmicro":   {openAPIType: "number", format: ""},
+	"unixnano":    {openAPIType: "number", format: ""},
+}
+
+// jsonBytesFormats maps json/v2 format options of []byte to OpenAPI types and formats
+var jsonBytesFormats = map[string]specialTypeMapping{
+	"base64":    {openAPIType: "string", format: "byte"},
+	"base64url": {openAPIType: "string", format: "base64url"},
+	"base32":    {openAPIType: "string", format: "base32"},
+	"base32hex": {openAPIType: "string", format: "base32hex"},
+	"base16":    {openAPIType: "string", format: "base16"},
+}
+
+// jsonDurationFormats maps json/v2 format options of time.Duration to OpenAPI types and formats
+var jsonDurationFormats = map[string]specialTypeMapping{
+	"sec":     {openAPIType: "number", format: ""},
+	"milli":   {openAPIType: "number", format: ""},
+	"micro":   {openAPIType: "number", format: ""},
+	"nano":    {openAPIType: "integer", format: "int64"},
+	"units":   {openAPIType: "string", format: ""},
+	"iso8601": {openAPIType: "string", format: "duration"},
+}
+
+// applyJSONFormat applies the json/v2 format option (e.g. format:base64, format:RFC3339, format:unixmilli)
+// Formats of types other than time.Time, time.Duration and []byte, or without an OpenAPI
+// equivalent, are reported as warnings.
+func (r *Resolver) applyJSONFormat(field *ResolvedField, tag string, pos token.Pos) {
+	format := parseJSONTag(tag).Format
+	if format == "" {
+		return
+	}
+
+	goType := strings.TrimLeft(field.GoType, "*")
+	var mapping specialTypeMapping
+	var ok bool
+	switch {
+	case goType == "time.Time":
+		mapping, ok = jsonTimeFormats[format]
+		if layout, isLayout := validateDateTimeFormats[format]; isLayout {
+			mapping, ok = specialTypeMapping{openAPIType: "string", format: layout}, true
+		}
+	case goType == "time.Duration":
+		mapping, ok = jsonDurationFormats[format]
+	case field.Format == "byte" || (field.IsArray && field.ItemsType == "integer" && strings.HasSuffix(goType, "byte")):
+		if format == "array" {
+			field.OpenAPIType = "array"
+			field.Format = ""
+			field.IsArray = true
+			field.ItemsType = "integer"
+			field.Type = &TypeDescriptor{GoType: field.GoType, OpenAPIType: "array", Items: &TypeDescriptor{GoType: "uint8", OpenAPIType: "integer"}}
+			return
+		}
+		mapping, ok = jsonBytesFormats[format]
+	}
+	if !ok {
+		r.warnf(pos, r.subpath(field.GoName), "unsupported-json-format", "json format %q has no OpenAPI equivalent", format)
+		return
+	}
+
+	field.OpenAPIType = mapping.openAPIType
+	field.Format = mapping.format
+	if field.IsArray {
+		// Byte arrays encoded as strings
+		field.IsArray = false
+		field.ItemsType = ""
+		field.MinItems = nil
+		field.MaxItems = nil
+		field.Type = &TypeDescriptor{GoType: field.GoType, OpenAPIType: mapping.openAPIType, Format: mapping.format}
+	}
+}
@@ -0,0 +1,76 @@
+package resolver
+
+import (
+	"go/types"
+)
+
+// marshalerKind describes how a type encodes itself with encoding/json
+type marshalerKind int
+
+const (
+	notMarshaler  marshalerKind = iota
+	textMarshaler               // encoding.TextMarshaler: encoded as a JSON string
+	jsonMarshaler               // json.Marshaler: encoded as arbitrary JSON
+)
+
+// marshalerOf reports whether a named type implements json.Marshaler or encoding.TextMarshaler
+// Pointer receivers count, since encoding/json calls them on addressable values.
+// MarshalJSON takes precedence over MarshalText, as in encoding/json.
+func marshalerOf(named *types.Named) marshalerKind {
+	methods := types.NewMethodSet(types.NewPointer(named))
+	switch {
+	case hasMarshalMethod(methods, named.Obj().Pkg(), "MarshalJSON"):
+		return jsonMarshaler
+	case hasMarshalMethod(methods, named.Obj().Pkg(), "MarshalText"):
+		return textMarshaler
+	default:
+		return notMarshaler
+	}
+}
+
+// hasMarshalMethod reports whether a method set has a method `name() ([]byte, error)`
+func hasMarshalMethod(methods *types.MethodSet, pkg *types.Package, name string) bool {
+	sel := methods.Lookup(pkg, name)
+	if sel == nil {
+		return false
+	}
+
+	sig, ok := sel.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 2 {
+		return false
+	}
+
+	bytes, ok := sig.Results().At(0).Type().(*types.Slice)
+	if !ok {
+		return false
+	}
+	if elem, ok := bytes.Elem().(*types.Basic); !ok || elem.Kind() != types.Byte {
+		return false
+	}
+	return types.Identical(sig.Results().At(1).Type(), types.Universe.Lookup("error").Type())
+}
+
+// isByteType reports whether a slice of t is encoded as a base64 string by encoding/json,
+// i.e. t is a byte type without custom marshaling
+func isByteType(t types.Type) bool {
+	t = types.Unalias(t)
+	if named, ok := t.(*types.Named); ok && marshalerOf(named) != notMarshaler {
+		return false
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Byte
+}
+
+// isRawMessage reports whether a named type is json.RawMessage (pre-encoded JSON of any shape)
+// With the json/v2 experiment, json.RawMessage is an alias of jsontext.Value.
+func isRawMessage(named *types.Named) bool {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return false
+	}
+	switch obj.Pkg().Path() + "." + obj.Name() {
+	case "encoding/json.RawMessage", "encoding/json/jsontext.Value":
+		return true
+	}
+	return false
+}
@@ -3,10 +3,15 @@ package resolver
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
+	"maps"
+	"path"
 	"reflect"
+	"slices"
 	"strings"
 
+	"github.com/wontaeyang/go-specgen/pkg/diagnostic"
 	"github.com/wontaeyang/go-specgen/pkg/parser"
 	"golang.org/x/tools/go/packages"
 )
@@ -18,7 +23,7 @@ type specialTypeMapping struct {
 }
 
 // specialTypes maps package path + type name to OpenAPI type info
-// These are Go standard library struct types that should be treated as primitives
+// These are Go standard library types that should be treated as primitives
 var specialTypes = map[string]map[string]*specialTypeMapping{
 	"time": {
 		"Time": {openAPIType: "string", format: "date-time"},
@@ -39,6 +44,21 @@ var specialTypes = map[string]map[string]*specialTypeMapping{
 	"regexp": {
 		"Regexp": {openAPIType: "string", format: ""},
 	},
+	"encoding/json": {
+		"Number": {openAPIType: "number", format: ""},
+	},
+	// Files, uploaded as multipart parts or sent as raw bodies
+	"mime/multipart": {
+		"FileHeader": {openAPIType: "string", format: "binary"},
+		"File":       {openAPIType: "string", format: "binary"},
+	},
+	"io": {
+		"Reader":     {openAPIType: "string", format: "binary"},
+		"ReadCloser": {openAPIType: "string", format: "binary"},
+	},
+	"os": {
+		"File": {openAPIType: "string", format: "binary"},
+	},
 }
 
 // resolveSpecialType checks if a type is a special standard library type
@@ -52,17 +72,32 @@ func resolveSpecialType(pkgPath, typeName string) *specialTypeMapping {
 	return nil
 }
 
-// isSpecialType checks if a package path and type name represent a special type
-func isSpecialType(pkgPath, typeName string) bool {
-	return resolveSpecialType(pkgPath, typeName) != nil
-}
-
 // Resolver resolves Go types to OpenAPI types
 type Resolver struct {
 	packagePath string
 	pkg         *packages.Package
 	typeCache   map[string]*TypeInfo
+	enumCache   map[*types.Named]*constEnum
 	comments    *parser.PackageComments // For inline type resolution
+	warnings    []*diagnostic.Diagnostic
+
+	// path is the annotation path of the declaration being resolved (e.g. @schema[User]),
+	// locating the warnings of its fields; "" when unknown
+	path string
+
+	// errs holds the errors found while describing types, which are reported by Resolve
+	errs []error
+
+	// typeMappings map package-qualified Go types to OpenAPI schemas (config file and @typeMapping)
+	typeMappings map[string]*TypeMapping
+
+	// schemaNames holds the names of all @schema types while resolving
+	schemaNames map[string]bool
+
+	// pkgs and allComments hold every loaded package by import path
+	// when resolving a spec assembled from multiple packages
+	pkgs        map[string]*packages.Package
+	allComments map[string]*parser.PackageComments
 }
 
 // TypeInfo contains resolved type information
@@ -73,52 +108,149 @@ type TypeInfo struct {
 	ItemsType   string
 	IsNullable  bool
 	IsAnyValue  bool // true for any/interface{} types
+	Pattern     string
+	Schema      map[string]any // Full schema of a type mapped by configuration
+	Length      *int           // Length of a fixed-size array ([N]T)
 }
 
 // NewResolver creates a new resolver for the given package
-// If comments is provided, it uses the package from comments for type resolution,
-// which enables inline struct resolution. Otherwise, it loads the package itself.
-func NewResolver(packagePath string, comments *parser.PackageComments) (*Resolver, error) {
-	var pkg *packages.Package
-
-	// Use the package from comments if available (enables inline type resolution)
-	if comments != nil && comments.Pkg != nil {
-		pkg = comments.Pkg
-	} else {
-		// Load the package ourselves
-		cfg := &packages.Config{
-			Mode: packages.NeedName |
-				packages.NeedFiles |
-				packages.NeedSyntax |
-				packages.NeedTypes |
-				packages.NeedTypesInfo,
+// If comments are provided, it uses the packages from comments for type resolution,
+// which enables inline struct resolution. The first entry is the primary package.
+// Otherwise, it loads the package itself.
+func NewResolver(packagePath string, comments ...*parser.PackageComments) (*Resolver, error) {
+	r := &Resolver{
+		packagePath: packagePath,
+		typeCache:   make(map[string]*TypeInfo),
+		enumCache:   make(map[*types.Named]*constEnum),
+		pkgs:        make(map[string]*packages.Package),
+		allComments: make(map[string]*parser.PackageComments),
+
+		typeMappings: make(map[string]*TypeMapping),
+	}
+
+	// Use the packages from comments if available (enables inline type resolution)
+	for _, c := range comments {
+		if c == nil || c.Pkg == nil {
+			continue
+		}
+		if r.pkg == nil {
+			r.pkg = c.Pkg
+			r.comments = c
 		}
+		r.pkgs[c.PkgPath] = c.Pkg
+		r.allComments[c.PkgPath] = c
+	}
 
-		pkgs, err := packages.Load(cfg, packagePath)
+	if r.pkg == nil {
+		// Load the package ourselves
+		pkgs, err := parser.LoadPackages(packagePath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load package: %w", err)
 		}
 
-		if len(pkgs) == 0 {
-			return nil, fmt.Errorf("no packages found")
+		for _, pkg := range pkgs {
+			if r.pkg == nil {
+				r.pkg = pkg
+			}
+			r.pkgs[pkg.PkgPath] = pkg
 		}
+	}
+
+	return r, nil
+}
 
-		pkg = pkgs[0]
-		if len(pkg.Errors) > 0 {
-			return nil, fmt.Errorf("package has errors: %v", pkg.Errors)
+// NewResolverFromComments creates a resolver for the packages already loaded by the parser
+// The first entry with a loaded package is the primary package.
+func NewResolverFromComments(comments ...*parser.PackageComments) (*Resolver, error) {
+	for _, c := range comments {
+		if c != nil && c.Pkg != nil {
+			return NewResolver("", comments...)
 		}
 	}
+	return nil, fmt.Errorf("no loaded packages to resolve")
+}
 
-	return &Resolver{
-		packagePath: packagePath,
-		pkg:         pkg,
-		typeCache:   make(map[string]*TypeInfo),
-		comments:    comments,
-	}, nil
+// packageFor returns the loaded package with the given import path
+// Falls back to the primary package when pkgPath is empty or unknown
+func (r *Resolver) packageFor(pkgPath string) *packages.Package {
+	if pkg, ok := r.pkgs[pkgPath]; ok {
+		return pkg
+	}
+	return r.pkg
+}
+
+// declaringComments returns the extracted comments of the package with the given import path,
+// extracting them on demand for packages that were only loaded as dependencies
+// Returns nil if the package was not loaded
+func (r *Resolver) declaringComments(pkgPath string) *parser.PackageComments {
+	if comments, ok := r.allComments[pkgPath]; ok {
+		return comments
+	}
+
+	if found := r.loadedPackage(pkgPath); found != nil && len(found.Syntax) > 0 {
+		comments := parser.ExtractPackageComments(found)
+		r.allComments[pkgPath] = comments
+		return comments
+	}
+
+	return nil
+}
+
+// loadedPackage returns the package with the given import path among the loaded packages
+// and their dependencies, or nil if it was not loaded
+func (r *Resolver) loadedPackage(pkgPath string) *packages.Package {
+	seen := make(map[string]bool)
+	var find func(pkg *packages.Package) *packages.Package
+	find = func(pkg *packages.Package) *packages.Package {
+		if pkg.PkgPath == pkgPath {
+			return pkg
+		}
+		if seen[pkg.PkgPath] {
+			return nil
+		}
+		seen[pkg.PkgPath] = true
+		for _, imp := range pkg.Imports {
+			if found := find(imp); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+
+	for _, pkg := range r.pkgs {
+		if found := find(pkg); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// isOwnPackage reports whether a package is scanned or belongs to the main module,
+// as opposed to the standard library and third-party dependencies
+func (r *Resolver) isOwnPackage(pkgPath string) bool {
+	if _, ok := r.pkgs[pkgPath]; ok {
+		return true
+	}
+	pkg := r.loadedPackage(pkgPath)
+	return pkg != nil && pkg.Module != nil && pkg.Module.Main
+}
+
+// commentsFor returns the extracted comments of the package with the given import path
+// Falls back to the primary package comments when pkgPath is empty or unknown
+func (r *Resolver) commentsFor(pkgPath string) *parser.PackageComments {
+	if comments, ok := r.allComments[pkgPath]; ok {
+		return comments
+	}
+	return r.comments
 }
 
 // Resolve resolves all types in the parsed package
+// Schemas, parameters and endpoints that fail to resolve are skipped and resolving goes on:
+// the result holds the others and the error is a diagnostic.MultiError listing every problem.
 func (r *Resolver) Resolve(parsed *parser.ParsedPackage) (*ResolvedPackage, error) {
+	var errs []error
+	r.errs = nil
+
 	resolved := &ResolvedPackage{
 		PackageName: parsed.PackageName,
 		Schemas:     make(map[string]*ResolvedSchema),
@@ -129,46 +261,72 @@ func (r *Resolver) Resolve(parsed *parser.ParsedPackage) (*ResolvedPackage, erro
 	// Resolve API info (no type resolution needed, just copy)
 	if parsed.API != nil {
 		resolved.API = r.resolveAPI(parsed.API)
+
+		if err := r.addAnnotationTypeMappings(parsed.API.TypeMappings); err != nil {
+			errs = append(errs, diagnostic.Wrap(err, parsed.API.Position, "invalid-type-mapping", "@api.@typeMapping"))
+		}
 	}
 
 	// Build schema names map for detecting unresolved struct references
+	// Schemas are also keyed by their package-qualified Go name (e.g. "github.com/acme/models.User")
 	schemaNames := make(map[string]bool)
-	for name := range parsed.Schemas {
+	for name, schema := range parsed.Schemas {
 		schemaNames[name] = true
+		if schema.PkgPath != "" {
+			schemaNames[schema.PkgPath+"."+schema.GoTypeName] = true
+		}
 	}
+	r.schemaNames = schemaNames
 
 	// Resolve schemas
-	for name, schema := range parsed.Schemas {
+	for _, name := range slices.Sorted(maps.Keys(parsed.Schemas)) {
+		schema := parsed.Schemas[name]
+		restore := r.at(fmt.Sprintf("@schema[%s]", name))
 		resolvedSchema, err := r.resolveSchema(schema, schemaNames)
+		restore()
 		if err != nil {
-			return nil, fmt.Errorf("failed to resolve schema %s: %w", name, err)
+			errs = append(errs, diagnostic.Wrap(err, schema.Position, "unresolved-type", fmt.Sprintf("@schema[%s]", name)))
+			continue
 		}
 		resolved.Schemas[name] = resolvedSchema
 	}
 
+	// Resolve polymorphic schemas now that all component names are known
+	r.resolveCompositions(resolved.Schemas)
+
 	// Resolve parameters
-	for name, param := range parsed.Parameters {
+	for _, name := range slices.Sorted(maps.Keys(parsed.Parameters)) {
+		param := parsed.Parameters[name]
+		restore := r.at(fmt.Sprintf("@%s[%s]", param.Type, name))
 		resolvedParam, err := r.resolveParameter(param)
+		restore()
 		if err != nil {
-			return nil, fmt.Errorf("failed to resolve parameter %s: %w", name, err)
+			errs = append(errs, diagnostic.Wrap(err, param.Position, "unresolved-type", fmt.Sprintf("@%s[%s]", param.Type, name)))
+			continue
 		}
 		resolved.Parameters[name] = resolvedParam
 	}
 
-	// Resolve endpoints
-	defaultContentType := ""
-	if resolved.API != nil {
-		defaultContentType = resolved.API.DefaultContentType
+	// Resolve the headers of multipart parts now that header structs are known
+	for _, schema := range resolved.Schemas {
+		resolvePartHeaders(schema.Fields, resolved.Parameters)
 	}
+
+	// Resolve endpoints
+	defaults := newContentDefaults(resolved.API)
 	for _, endpoint := range parsed.Endpoints {
-		resolvedEndpoint, err := r.resolveEndpoint(endpoint, resolved.Parameters, resolved.Schemas, defaultContentType)
+		restore := r.at(fmt.Sprintf("@endpoint[%s %s]", endpoint.Method, endpoint.Path))
+		resolvedEndpoint, err := r.resolveEndpoint(endpoint, resolved.Parameters, resolved.Schemas, defaults)
+		restore()
 		if err != nil {
-			return nil, fmt.Errorf("failed to resolve endpoint %s %s: %w", endpoint.Method, endpoint.Path, err)
+			errs = append(errs, diagnostic.Wrap(err, endpoint.Position, "invalid-endpoint", fmt.Sprintf("@endpoint[%s %s]", endpoint.Method, endpoint.Path)))
+			continue
 		}
 		resolved.Endpoints = append(resolved.Endpoints, resolvedEndpoint)
 	}
 
-	return resolved, nil
+	errs = append(errs, r.errs...)
+	return resolved, diagnostic.Join(errs...)
 }
 
 // resolveAPI copies API info (no type resolution needed)
@@ -178,6 +336,8 @@ func (r *Resolver) resolveAPI(api *parser.APIInfo) *ResolvedAPI {
 		Version:         api.Version,
 		Description:     api.Description,
 		TermsOfService:  api.TermsOfService,
+		Self:            api.Self,
+		Position:        api.Position,
 		Servers:         make([]*Server, len(api.Servers)),
 		SecuritySchemes: make(map[string]*SecurityScheme),
 		Security:        make([][]*SecurityRequirement, len(api.Security)),
@@ -238,11 +398,15 @@ func (r *Resolver) resolveAPI(api *parser.APIInfo) *ResolvedAPI {
 		resolved.Tags[i] = &Tag{
 			Name:        tag.Name,
 			Description: tag.Description,
+			Parent:      tag.Parent,
+			Kind:        tag.Kind,
 		}
 	}
 
-	// Copy default content type
+	// Copy default content types
 	resolved.DefaultContentType = api.DefaultContentType
+	resolved.Produces = api.Produces
+	resolved.Consumes = api.Consumes
 
 	return resolved
 }
@@ -253,9 +417,12 @@ func (r *Resolver) resolveSchema(schema *parser.Schema, schemaNames map[string]b
 	resolved := &ResolvedSchema{
 		Name:        schema.Name,
 		GoTypeName:  schema.GoTypeName,
+		PkgPath:     schema.PkgPath,
+		Position:    schema.Position,
 		Description: schema.Description,
 		Deprecated:  schema.Deprecated,
 		Fields:      make([]*ResolvedField, 0),
+		AllOf:       schema.AllOf,
 		IsGeneric:   schema.IsGeneric,
 		IsTypeAlias: schema.IsTypeAlias,
 		AliasOf:     schema.AliasOf,
@@ -266,44 +433,94 @@ func (r *Resolver) resolveSchema(schema *parser.Schema, schemaNames map[string]b
 		resolved.TypeArg = extractTypeArg(schema.AliasOf)
 	}
 
-	// Find the Go struct
-	obj := r.pkg.Types.Scope().Lookup(schema.GoTypeName)
+	// Find the Go type
+	obj := r.packageFor(schema.PkgPath).Types.Scope().Lookup(schema.GoTypeName)
 	if obj == nil {
-		return nil, fmt.Errorf("struct %s not found in package", schema.GoTypeName)
+		return nil, fmt.Errorf("type %s not found in package", schema.GoTypeName)
 	}
 
 	structType, ok := obj.Type().Underlying().(*types.Struct)
 	if !ok {
-		return nil, fmt.Errorf("%s is not a struct", schema.GoTypeName)
+		// Named non-struct types (e.g. type Currency string) are described by a single value
+		value, err := r.resolveValueSchema(obj, schema.Constraints, schemaNames)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Value = value
+		return resolved, nil
 	}
 
-	// Resolve each field
-	for i := 0; i < structType.NumFields(); i++ {
-		field := structType.Field(i)
-		tag := structType.Tag(i)
+	// The XMLName field names the XML element of the schema
+	resolved.XML = xmlNameInfo(structType)
+
+	// Resolve each field, promoting fields of embedded structs like encoding/json
+	named, _ := obj.Type().(*types.Named)
+	structFields, unknown := collectStructMembers(structType, named, "json")
+	for _, sf := range structFields {
+		field := sf.field
 
 		// Find annotation for this field
-		var fieldAnnotation *parser.Field
-		for _, f := range schema.Fields {
-			if f.GoName == field.Name() {
-				fieldAnnotation = f
-				break
-			}
+		fieldAnnotation, err := r.structFieldAnnotation(sf, schema.Fields)
+		if err != nil {
+			return nil, r.fieldError(field, "failed to parse annotation of field", err)
 		}
 
 		// Resolve field type
-		resolvedField, err := r.resolveField(field, tag, fieldAnnotation, schemaNames)
+		resolvedField, err := r.resolveField(field, sf.tag, fieldAnnotation, schemaNames)
 		if err != nil {
-			return nil, fmt.Errorf("failed to resolve field %s: %w", field.Name(), err)
+			return nil, r.fieldError(field, "failed to resolve field", err)
 		}
 		// Skip fields that should be omitted (e.g., json:"-")
 		if resolvedField == nil {
 			continue
 		}
+		resolvedField.EmbeddedFrom = qualifiedTypeName(sf.embedded)
 
 		resolved.Fields = append(resolved.Fields, resolvedField)
 	}
 
+	if unknown != nil {
+		resolved.AdditionalProperties = r.describeUnknownMembers(unknown, schemaNames)
+	}
+
+	return resolved, nil
+}
+
+// resolveValueSchema resolves the value of a named non-struct schema type
+// The underlying type is resolved like a field so that element types referencing other schemas
+// become references (e.g. type Tags []Tag). Type-level constraints are applied as field annotations,
+// and the enum is derived from typed constants unless @enum is given.
+func (r *Resolver) resolveValueSchema(obj types.Object, constraints *parser.Field, schemaNames map[string]bool) (*ResolvedField, error) {
+	// Custom marshaling replaces the underlying type (e.g. an int encoded by MarshalText)
+	named, _ := obj.Type().(*types.Named)
+	marshaled := named != nil && marshalerOf(named) != notMarshaler
+
+	valueType := obj.Type().Underlying()
+	if marshaled {
+		valueType = named
+	}
+	value := types.NewField(obj.Pos(), obj.Pkg(), "Value", valueType, false)
+
+	resolved, err := r.resolveField(value, "", constraints, schemaNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", obj.Name(), err)
+	}
+	resolved.Name = obj.Name()
+	resolved.GoName = obj.Name()
+	if marshaled {
+		// Keep the generator from referencing the schema itself
+		resolved.GoType = resolved.OpenAPIType
+	}
+
+	if constraints == nil || len(constraints.Enum) == 0 {
+		r.applyConstEnum(resolved, obj.Type())
+	}
+
+	// Interfaces are the oneOf of their @schema implementations unless @oneOf or @anyOf lists them
+	if iface, ok := valueType.(*types.Interface); ok && iface.NumMethods() > 0 && resolved.Composition == nil {
+		resolved.Composition = &Composition{Kind: "oneOf", pkgPath: obj.Pkg().Path(), iface: iface}
+	}
+
 	return resolved, nil
 }
 
@@ -324,11 +541,12 @@ func (r *Resolver) resolveParameter(param *parser.Parameter) (*ResolvedParameter
 		Name:       param.Name,
 		Type:       string(param.Type),
 		GoTypeName: param.GoTypeName,
+		Position:   param.Position,
 		Fields:     make([]*ResolvedField, 0),
 	}
 
 	// Find the Go struct
-	obj := r.pkg.Types.Scope().Lookup(param.GoTypeName)
+	obj := r.packageFor(param.PkgPath).Types.Scope().Lookup(param.GoTypeName)
 	if obj == nil {
 		return nil, fmt.Errorf("struct %s not found in package", param.GoTypeName)
 	}
@@ -338,24 +556,21 @@ func (r *Resolver) resolveParameter(param *parser.Parameter) (*ResolvedParameter
 		return nil, fmt.Errorf("%s is not a struct", param.GoTypeName)
 	}
 
-	// Resolve each field
-	for i := 0; i < structType.NumFields(); i++ {
-		field := structType.Field(i)
-		tag := structType.Tag(i)
+	// Resolve each field, promoting fields of embedded structs like encoding/json
+	named, _ := obj.Type().(*types.Named)
+	for _, sf := range collectStructFields(structType, named, string(param.Type)) {
+		field := sf.field
 
 		// Find annotation for this field
-		var fieldAnnotation *parser.Field
-		for _, f := range param.Fields {
-			if f.GoName == field.Name() {
-				fieldAnnotation = f
-				break
-			}
+		fieldAnnotation, err := r.structFieldAnnotation(sf, param.Fields)
+		if err != nil {
+			return nil, r.fieldError(field, "failed to parse annotation of field", err)
 		}
 
 		// Resolve field type
-		resolvedField, err := r.resolveFieldWithParamType(field, tag, fieldAnnotation, string(param.Type))
+		resolvedField, err := r.resolveFieldWithParamType(field, sf.tag, fieldAnnotation, string(param.Type))
 		if err != nil {
-			return nil, fmt.Errorf("failed to resolve field %s: %w", field.Name(), err)
+			return nil, r.fieldError(field, "failed to resolve field", err)
 		}
 		// Skip fields that should be omitted (e.g., json:"-" for schema fields)
 		if resolvedField == nil {
@@ -368,6 +583,11 @@ func (r *Resolver) resolveParameter(param *parser.Parameter) (*ResolvedParameter
 	return resolved, nil
 }
 
+// fieldError returns a field error as a diagnostic at the position of the field
+func (r *Resolver) fieldError(field *types.Var, context string, err error) error {
+	return diagnostic.Wrap(fmt.Errorf("%s %s: %w", context, field.Name(), err), r.position(field.Pos()), "invalid-field", "")
+}
+
 // resolveField resolves a single struct field
 // schemaNames contains the names of all known @schema types for detecting unresolved struct references
 // Returns nil, nil if the field should be skipped (e.g., json:"-" or unexported fields)
@@ -377,6 +597,11 @@ func (r *Resolver) resolveField(field *types.Var, tag string, annotation *parser
 		return nil, nil
 	}
 
+	// XMLName names the element of the struct, it is not a property
+	if isXMLName(field.Name(), field.Type()) {
+		return nil, nil
+	}
+
 	// Resolve field name using tag fallback chain (json -> xml -> Go field name)
 	// Returns "" if field should be skipped
 	fieldName := resolveFieldNameFromTag(tag, field.Name())
@@ -386,90 +611,98 @@ func (r *Resolver) resolveField(field *types.Var, tag string, annotation *parser
 	}
 
 	resolved := &ResolvedField{
-		Name:   fieldName,
-		GoName: field.Name(),
-		GoType: field.Type().String(),
-	}
-
-	// Check if field is required/nullable from JSON tag
-	resolved.Required = !strings.Contains(tag, "omitempty")
-
-	// Check for anonymous struct types and resolve their fields inline
-	if inlineFields := r.resolveAnonymousStruct(field.Type(), schemaNames); inlineFields != nil {
-		resolved.InlineFields = inlineFields
-		resolved.OpenAPIType = "object"
-	} else if itemFields := r.resolveSliceOfAnonymousStruct(field.Type(), schemaNames); itemFields != nil {
-		// Slice of anonymous struct
-		resolved.IsArray = true
-		resolved.OpenAPIType = "array"
-		resolved.ItemsType = "object"
-		resolved.ItemsInlineFields = itemFields
-	} else if mapValueFields := r.resolveMapOfAnonymousStruct(field.Type(), schemaNames); mapValueFields != nil {
-		// Map with anonymous struct values
-		resolved.IsMap = true
-		resolved.OpenAPIType = "object"
-		resolved.MapValueInlineFields = mapValueFields
-	} else {
-		// Check for unresolved struct types (named structs not in @schema)
-		r.checkUnresolvedStruct(field.Type(), resolved, schemaNames)
-
-		// Resolve Go type to OpenAPI type
-		typeInfo := r.resolveType(field.Type())
-		resolved.OpenAPIType = typeInfo.OpenAPIType
-		resolved.Format = typeInfo.Format
-		resolved.IsArray = typeInfo.IsArray
-		resolved.ItemsType = typeInfo.ItemsType
-		resolved.Nullable = typeInfo.IsNullable
-		resolved.IsAnyValue = typeInfo.IsAnyValue
+		Name:     fieldName,
+		GoName:   field.Name(),
+		GoType:   field.Type().String(),
+		Position: r.position(field.Pos()),
 	}
 
+	// Fields tagged omitempty or omitzero are optional
+	resolved.Required = !omitsEmpty(tag)
+
+	// Check for unresolved struct types (named structs not in @schema)
+	r.checkUnresolvedStruct(field.Type(), resolved, schemaNames)
+
+	// Resolve the Go type, including nested containers and anonymous structs
+	r.resolveFieldType(resolved, field.Type(), schemaNames)
+
+	// Numbers and booleans tagged json:",string" are encoded as strings, json/v2 format options
+	// select the encoding of times, durations and byte slices
+	applyJSONStringOption(resolved, tag)
+	r.applyJSONFormat(resolved, tag, field.Pos())
+
+	// Element and attribute names, namespaces and wrapped arrays of xml tags
+	r.applyXMLTag(resolved, tag, field.Pos())
+
+	// Translate validate/binding tag rules (overridden by @field annotations)
+	r.applyValidateTags(resolved, tag, field.Pos())
+
 	// Apply annotation overrides if present
-	if annotation != nil {
-		if annotation.Description != "" {
-			resolved.Description = annotation.Description
-		}
-		if annotation.Format != "" {
-			resolved.Format = annotation.Format
-		}
-		if annotation.Example != "" {
-			resolved.Example = annotation.Example
-		}
-		if annotation.Default != "" {
-			resolved.Default = annotation.Default
-		}
-		if annotation.Pattern != "" {
-			resolved.Pattern = annotation.Pattern
-		}
-		if len(annotation.Enum) > 0 {
-			resolved.Enum = annotation.Enum
-		}
-		if annotation.MinLength != nil {
-			resolved.MinLength = annotation.MinLength
-		}
-		if annotation.MaxLength != nil {
-			resolved.MaxLength = annotation.MaxLength
-		}
-		if annotation.MinItems != nil {
-			resolved.MinItems = annotation.MinItems
-		}
-		if annotation.MaxItems != nil {
-			resolved.MaxItems = annotation.MaxItems
-		}
-		if annotation.UniqueItems {
-			resolved.UniqueItems = true
-		}
-		if annotation.Minimum != nil {
-			resolved.Minimum = annotation.Minimum
-		}
-		if annotation.Maximum != nil {
-			resolved.Maximum = annotation.Maximum
-		}
-		resolved.Deprecated = annotation.Deprecated
+	pkgPath := ""
+	if field.Pkg() != nil {
+		pkgPath = field.Pkg().Path()
 	}
+	r.applyFieldAnnotation(resolved, annotation, field.Type(), field.Pos(), pkgPath)
 
 	return resolved, nil
 }
 
+// applyFieldAnnotation applies the overrides of a @field annotation to a resolved field
+// t, pos and pkgPath are the Go type, position and package of the field, for polymorphic values.
+func (r *Resolver) applyFieldAnnotation(resolved *ResolvedField, annotation *parser.Field, t types.Type, pos token.Pos, pkgPath string) {
+	if annotation == nil {
+		return
+	}
+	if annotation.Description != "" {
+		resolved.Description = annotation.Description
+	}
+	if annotation.Format != "" {
+		resolved.Format = annotation.Format
+	}
+	if annotation.Example != "" {
+		resolved.Example = annotation.Example
+	}
+	if annotation.Default != "" {
+		resolved.Default = annotation.Default
+	}
+	if annotation.Pattern != "" {
+		resolved.Pattern = annotation.Pattern
+	}
+	if len(annotation.Enum) > 0 {
+		resolved.Enum = annotation.Enum
+		resolved.EnumVarNames = nil
+		resolved.EnumDescriptions = nil
+	}
+	if annotation.MinLength != nil {
+		resolved.MinLength = annotation.MinLength
+	}
+	if annotation.MaxLength != nil {
+		resolved.MaxLength = annotation.MaxLength
+	}
+	if annotation.MinItems != nil {
+		resolved.MinItems = annotation.MinItems
+	}
+	if annotation.MaxItems != nil {
+		resolved.MaxItems = annotation.MaxItems
+	}
+	if annotation.UniqueItems {
+		resolved.UniqueItems = true
+	}
+	if annotation.Minimum != nil {
+		resolved.Minimum = annotation.Minimum
+	}
+	if annotation.Maximum != nil {
+		resolved.Maximum = annotation.Maximum
+	}
+	resolved.Deprecated = annotation.Deprecated
+
+	// Polymorphic values (@oneOf, @anyOf, or interfaces with a @discriminator)
+	r.applyComposition(resolved, annotation, t, pos, pkgPath)
+
+	// Content types and headers of the field's part in multipart and form bodies
+	applyPartEncoding(resolved, annotation)
+}
+
 // resolveAnonymousStruct checks if a type is an anonymous struct and resolves its fields inline
 // Returns nil if the type is not an anonymous struct
 func (r *Resolver) resolveAnonymousStruct(t types.Type, schemaNames map[string]bool) []*ResolvedField {
@@ -487,106 +720,63 @@ func (r *Resolver) resolveAnonymousStruct(t types.Type, schemaNames map[string]b
 	// This is an anonymous struct - resolve its fields
 	fields := make([]*ResolvedField, 0, structType.NumFields())
 
-	for i := 0; i < structType.NumFields(); i++ {
-		field := structType.Field(i)
-		tag := structType.Tag(i)
-
-		// Skip unexported (private) fields - they cannot be serialized
-		if !field.Exported() {
-			continue
-		}
-
-		// Resolve field name using tag fallback chain (json -> xml -> Go field name)
-		// Returns "" if field should be skipped
-		fieldName := resolveFieldNameFromTag(tag, field.Name())
-		if fieldName == "" {
-			// Field should be skipped (e.g., json:"-")
-			continue
-		}
+	// Collect exported fields (including promoted fields of embedded structs)
+	// named with the tag fallback chain (json -> xml -> Go field name)
+	for _, sf := range collectStructFields(structType, nil, "json") {
+		field := sf.field
+		tag := sf.tag
 
 		resolvedField := &ResolvedField{
-			Name:   fieldName,
-			GoName: field.Name(),
-			GoType: field.Type().String(),
+			Name:         sf.name,
+			GoName:       field.Name(),
+			GoType:       field.Type().String(),
+			Position:     r.position(field.Pos()),
+			EmbeddedFrom: qualifiedTypeName(sf.embedded),
 		}
 
 		// Check if field is required from JSON tag
-		resolvedField.Required = !strings.Contains(tag, "omitempty")
-
-		// Check for nested anonymous structs (recursive)
-		if nestedFields := r.resolveAnonymousStruct(field.Type(), schemaNames); nestedFields != nil {
-			resolvedField.InlineFields = nestedFields
-			resolvedField.OpenAPIType = "object"
-		} else if itemFields := r.resolveSliceOfAnonymousStruct(field.Type(), schemaNames); itemFields != nil {
-			// Nested slice of anonymous struct
-			resolvedField.IsArray = true
-			resolvedField.OpenAPIType = "array"
-			resolvedField.ItemsType = "object"
-			resolvedField.ItemsInlineFields = itemFields
-		} else if mapValueFields := r.resolveMapOfAnonymousStruct(field.Type(), schemaNames); mapValueFields != nil {
-			// Nested map with anonymous struct values
-			resolvedField.IsMap = true
-			resolvedField.OpenAPIType = "object"
-			resolvedField.MapValueInlineFields = mapValueFields
-		} else {
-			// Check for unresolved struct types
-			r.checkUnresolvedStruct(field.Type(), resolvedField, schemaNames)
+		resolvedField.Required = !omitsEmpty(tag)
 
-			// Resolve Go type to OpenAPI type
-			typeInfo := r.resolveType(field.Type())
-			resolvedField.OpenAPIType = typeInfo.OpenAPIType
-			resolvedField.Format = typeInfo.Format
-			resolvedField.IsArray = typeInfo.IsArray
-			resolvedField.ItemsType = typeInfo.ItemsType
-			resolvedField.Nullable = typeInfo.IsNullable
-			resolvedField.IsAnyValue = typeInfo.IsAnyValue
-		}
-
-		fields = append(fields, resolvedField)
-	}
-
-	return fields
-}
+		// Check for unresolved struct types
+		r.checkUnresolvedStruct(field.Type(), resolvedField, schemaNames)
 
-// resolveSliceOfAnonymousStruct checks if a type is a slice/array of anonymous struct
-// and resolves the element's fields inline. Returns nil if not a slice of anonymous struct.
-func (r *Resolver) resolveSliceOfAnonymousStruct(t types.Type, schemaNames map[string]bool) []*ResolvedField {
-	// Unwrap pointer
-	if ptr, ok := t.(*types.Pointer); ok {
-		t = ptr.Elem()
-	}
+		// Resolve the Go type (nested anonymous structs recursively)
+		r.resolveFieldType(resolvedField, field.Type(), schemaNames)
 
-	// Check if this is a slice
-	slice, ok := t.(*types.Slice)
-	if !ok {
-		return nil
-	}
+		// Numbers and booleans tagged json:",string" are encoded as strings, json/v2 format options
+		// select the encoding of times, durations and byte slices
+		applyJSONStringOption(resolvedField, tag)
+		r.applyJSONFormat(resolvedField, tag, field.Pos())
 
-	// Check if the element type is an anonymous struct
-	return r.resolveAnonymousStruct(slice.Elem(), schemaNames)
-}
+		// Translate validate/binding tag rules
+		r.applyValidateTags(resolvedField, tag, field.Pos())
 
-// resolveMapOfAnonymousStruct checks if a type is a map with anonymous struct values
-// and resolves the value's fields inline. Returns nil if not a map of anonymous struct.
-func (r *Resolver) resolveMapOfAnonymousStruct(t types.Type, schemaNames map[string]bool) []*ResolvedField {
-	// Unwrap pointer
-	if ptr, ok := t.(*types.Pointer); ok {
-		t = ptr.Elem()
-	}
+		// Apply the @field annotation, declared on the field or on the embedded struct promoting it
+		annotation, err := r.anonymousFieldAnnotation(sf)
+		if err != nil {
+			r.errs = append(r.errs, r.fieldError(field, "failed to parse annotation of field", err))
+		}
+		pkgPath := ""
+		if field.Pkg() != nil {
+			pkgPath = field.Pkg().Path()
+		}
+		r.applyFieldAnnotation(resolvedField, annotation, field.Type(), field.Pos(), pkgPath)
 
-	// Check if this is a map
-	mapType, ok := t.(*types.Map)
-	if !ok {
-		return nil
+		fields = append(fields, resolvedField)
 	}
 
-	// Check if the value type is an anonymous struct
-	return r.resolveAnonymousStruct(mapType.Elem(), schemaNames)
+	return fields
 }
 
 // checkUnresolvedStruct checks if a type is a named struct that is not a known @schema
 // and marks the resolved field accordingly
 func (r *Resolver) checkUnresolvedStruct(t types.Type, resolved *ResolvedField, schemaNames map[string]bool) {
+	r.checkUnresolvedStructVisiting(t, resolved, schemaNames, make(map[*types.Named]bool))
+}
+
+// checkUnresolvedStructVisiting checks a type for unresolved structs, skipping named types
+// already visited (e.g. type Tree map[string]Tree)
+func (r *Resolver) checkUnresolvedStructVisiting(t types.Type, resolved *ResolvedField, schemaNames map[string]bool, visiting map[*types.Named]bool) {
 	// Unwrap pointer
 	if ptr, ok := t.(*types.Pointer); ok {
 		t = ptr.Elem()
@@ -594,13 +784,17 @@ func (r *Resolver) checkUnresolvedStruct(t types.Type, resolved *ResolvedField,
 
 	// Check for slice/array - check element type
 	if slice, ok := t.(*types.Slice); ok {
-		r.checkUnresolvedStruct(slice.Elem(), resolved, schemaNames)
+		r.checkUnresolvedStructVisiting(slice.Elem(), resolved, schemaNames, visiting)
+		return
+	}
+	if array, ok := t.(*types.Array); ok {
+		r.checkUnresolvedStructVisiting(array.Elem(), resolved, schemaNames, visiting)
 		return
 	}
 
 	// Check for map - check value type
 	if mapType, ok := t.(*types.Map); ok {
-		r.checkUnresolvedStruct(mapType.Elem(), resolved, schemaNames)
+		r.checkUnresolvedStructVisiting(mapType.Elem(), resolved, schemaNames, visiting)
 		return
 	}
 
@@ -609,17 +803,26 @@ func (r *Resolver) checkUnresolvedStruct(t types.Type, resolved *ResolvedField,
 		obj := named.Obj()
 		underlying := named.Underlying()
 
-		// Skip special standard library types (time.Time, url.URL, etc.)
+		// Skip mapped types (time.Time, url.URL, configured mappings, etc.)
 		// These are handled specially by the resolver
-		if obj.Pkg() != nil && isSpecialType(obj.Pkg().Path(), obj.Name()) {
+		if r.lookupTypeMapping(obj) != nil {
+			return
+		}
+
+		// Skip types with custom marshaling (json.Marshaler, encoding.TextMarshaler)
+		if marshalerOf(named) != notMarshaler {
 			return
 		}
 
 		// Check if underlying is a struct
 		if _, isStruct := underlying.(*types.Struct); isStruct {
 			typeName := obj.Name()
+			qualifiedName := typeName
+			if obj.Pkg() != nil {
+				qualifiedName = obj.Pkg().Path() + "." + typeName
+			}
 			// If not in known schemas, mark as unresolved
-			if !schemaNames[typeName] {
+			if !schemaNames[qualifiedName] && !schemaNames[typeName] {
 				resolved.IsUnresolvedStruct = true
 				resolved.UnresolvedTypeName = typeName
 			}
@@ -628,7 +831,11 @@ func (r *Resolver) checkUnresolvedStruct(t types.Type, resolved *ResolvedField,
 
 		// If underlying is slice/map, recurse into element type
 		// This handles custom types like `type Addresses []Address`
-		r.checkUnresolvedStruct(underlying, resolved, schemaNames)
+		if visiting[named] {
+			return
+		}
+		visiting[named] = true
+		r.checkUnresolvedStructVisiting(underlying, resolved, schemaNames, visiting)
 	}
 }
 
@@ -640,6 +847,11 @@ func (r *Resolver) resolveFieldWithParamType(field *types.Var, tag string, annot
 		return nil, nil
 	}
 
+	// XMLName names the element of the struct, it is not a property
+	if isXMLName(field.Name(), field.Type()) {
+		return nil, nil
+	}
+
 	// Extract name from the appropriate struct tag based on parameter type
 	var tagName string
 	switch paramType {
@@ -666,22 +878,24 @@ func (r *Resolver) resolveFieldWithParamType(field *types.Var, tag string, annot
 	}
 
 	resolved := &ResolvedField{
-		Name:   tagName,
-		GoName: field.Name(),
-		GoType: field.Type().String(),
+		Name:     tagName,
+		GoName:   field.Name(),
+		GoType:   field.Type().String(),
+		Position: r.position(field.Pos()),
 	}
 
 	// Check if field is required/nullable from tag
-	resolved.Required = !strings.Contains(tag, "omitempty")
+	resolved.Required = !omitsEmpty(tag)
 
 	// Resolve Go type to OpenAPI type
 	typeInfo := r.resolveType(field.Type())
-	resolved.OpenAPIType = typeInfo.OpenAPIType
-	resolved.Format = typeInfo.Format
-	resolved.IsArray = typeInfo.IsArray
-	resolved.ItemsType = typeInfo.ItemsType
-	resolved.Nullable = typeInfo.IsNullable
-	resolved.IsAnyValue = typeInfo.IsAnyValue
+	applyTypeInfo(resolved, typeInfo)
+
+	// Derive enum values from typed constants (overridden by an explicit @enum)
+	r.applyConstEnum(resolved, field.Type())
+
+	// Translate validate/binding tag rules (overridden by @field annotations)
+	r.applyValidateTags(resolved, tag, field.Pos())
 
 	// Apply annotation overrides if present
 	if annotation != nil {
@@ -702,6 +916,8 @@ func (r *Resolver) resolveFieldWithParamType(field *types.Var, tag string, annot
 		}
 		if len(annotation.Enum) > 0 {
 			resolved.Enum = annotation.Enum
+			resolved.EnumVarNames = nil
+			resolved.EnumDescriptions = nil
 		}
 		if annotation.MinLength != nil {
 			resolved.MinLength = annotation.MinLength
@@ -751,8 +967,16 @@ func (r *Resolver) resolveType(t types.Type) *TypeInfo {
 		t = ptr.Elem()
 	}
 
-	// Handle slices/arrays
+	// Handle slices
 	if slice, ok := t.(*types.Slice); ok {
+		// encoding/json encodes []byte as a base64 string
+		if isByteType(slice.Elem()) {
+			info.OpenAPIType = "string"
+			info.Format = "byte"
+			r.typeCache[typeStr] = info
+			return info
+		}
+
 		info.IsArray = true
 		elemInfo := r.resolveType(slice.Elem())
 		info.ItemsType = elemInfo.OpenAPIType
@@ -761,6 +985,18 @@ func (r *Resolver) resolveType(t types.Type) *TypeInfo {
 		return info
 	}
 
+	// Handle fixed-size arrays (always encoded as JSON arrays, even [N]byte)
+	if array, ok := t.(*types.Array); ok {
+		info.IsArray = true
+		elemInfo := r.resolveType(array.Elem())
+		info.ItemsType = elemInfo.OpenAPIType
+		info.OpenAPIType = "array"
+		length := int(array.Len())
+		info.Length = &length
+		r.typeCache[typeStr] = info
+		return info
+	}
+
 	// Handle type aliases (e.g., "any" is an alias for interface{})
 	if alias, ok := t.(*types.Alias); ok {
 		// Recurse on the aliased type
@@ -770,15 +1006,29 @@ func (r *Resolver) resolveType(t types.Type) *TypeInfo {
 	// Handle named types
 	if named, ok := t.(*types.Named); ok {
 		obj := named.Obj()
-		pkgPath := ""
-		if obj.Pkg() != nil {
-			pkgPath = obj.Pkg().Path()
+
+		// Check for mapped types (configured mappings and standard library types)
+		if mapping := r.lookupTypeMapping(obj); mapping != nil {
+			mapped := mapping.typeInfo()
+			// Pointers to files (*multipart.FileHeader, *os.File) are absent parts, not nulls
+			mapped.IsNullable = info.IsNullable && !isFileFormat(mapped.Format)
+			r.typeCache[typeStr] = mapped
+			return mapped
 		}
 
-		// Check for special standard library types
-		if specialType := resolveSpecialType(pkgPath, obj.Name()); specialType != nil {
-			info.OpenAPIType = specialType.openAPIType
-			info.Format = specialType.format
+		// Types with custom marshaling are not encoded as their underlying type
+		switch marshalerOf(named) {
+		case textMarshaler:
+			info.OpenAPIType = "string"
+			r.typeCache[typeStr] = info
+			return info
+		case jsonMarshaler:
+			info.IsAnyValue = true
+			// @schema types describe their own JSON shape and are referenced by the generator;
+			// json.RawMessage holds arbitrary JSON by design
+			if !r.schemaNames[qualifiedTypeName(named)] && !isRawMessage(named) {
+				r.warnf(obj.Pos(), "", "json-marshaler", "type %s implements json.Marshaler, its JSON shape is unknown; add a type mapping (@typeMapping or config) to describe it", qualifiedTypeName(named))
+			}
 			r.typeCache[typeStr] = info
 			return info
 		}
@@ -826,12 +1076,30 @@ func (r *Resolver) resolveType(t types.Type) *TypeInfo {
 	return info
 }
 
+// applyTypeInfo copies resolved type information to a field
+func applyTypeInfo(field *ResolvedField, info *TypeInfo) {
+	field.OpenAPIType = info.OpenAPIType
+	field.Format = info.Format
+	field.IsArray = info.IsArray
+	field.ItemsType = info.ItemsType
+	field.Nullable = info.IsNullable
+	field.IsAnyValue = info.IsAnyValue
+	field.Pattern = info.Pattern
+	field.MappedSchema = info.Schema
+	if info.Length != nil {
+		length := *info.Length
+		field.MinItems = &length
+		field.MaxItems = &length
+	}
+}
+
 // resolveEndpoint resolves an endpoint
-func (r *Resolver) resolveEndpoint(endpoint *parser.Endpoint, parameters map[string]*ResolvedParameter, schemas map[string]*ResolvedSchema, defaultContentType string) (*ResolvedEndpoint, error) {
+func (r *Resolver) resolveEndpoint(endpoint *parser.Endpoint, parameters map[string]*ResolvedParameter, schemas map[string]*ResolvedSchema, defaults contentDefaults) (*ResolvedEndpoint, error) {
 	resolved := &ResolvedEndpoint{
 		FuncName:        endpoint.FuncName,
 		Method:          endpoint.Method,
 		Path:            endpoint.Path,
+		Position:        endpoint.Position,
 		OperationID:     endpoint.OperationID,
 		Summary:         endpoint.Summary,
 		Description:     endpoint.Description,
@@ -845,86 +1113,98 @@ func (r *Resolver) resolveEndpoint(endpoint *parser.Endpoint, parameters map[str
 		CookieParams:    make([]*ResolvedParameter, 0),
 		InlineResponses: make(map[string]*ResolvedInlineBody),
 	}
+	endpointPath := fmt.Sprintf("@endpoint[%s %s]", endpoint.Method, endpoint.Path)
 
 	// Resolve request body
-	if endpoint.Request != nil && endpoint.Request.Body != nil {
-		contentType := endpoint.Request.ContentType
-		if contentType == "" {
-			contentType = defaultContentType
-		}
-		if contentType == "" {
-			contentType = "application/json" // Fallback
-		}
+	if request := endpoint.Request; request != nil && (request.Body != nil || len(request.Content) > 0 || slices.Contains(request.ContentTypes, binaryContentType)) {
+		content, body := r.resolveContent(request.ContentTypes, request.Body, request.Content, defaults.Requests, endpoint.PkgPath, schemas)
 		resolved.Request = &ResolvedRequestBody{
-			ContentType: contentType,
-			Body:        r.resolveBody(endpoint.Request.Body, schemas),
-			Required:    true, // Default to required
+			Body:     body,
+			Required: true, // Default to required
+			Content:  content,
+		}
+		if len(content) > 0 {
+			resolved.Request.ContentType = content[0].ContentType
+			resolved.Request.Body = content[0].Body
 		}
 	}
 
 	// Resolve responses
 	for statusCode, response := range endpoint.Responses {
-		contentType := response.ContentType
-		// Only apply default if response has a body
-		if contentType == "" && response.Body != nil {
-			contentType = defaultContentType
-		}
-		if contentType == "" && response.Body != nil {
-			contentType = "application/json" // Fallback
-		}
+		// Defaults only apply if the response has a body
+		content, body := r.resolveContent(streamContentTypes(response), response.Body, response.Content, defaults.Responses, endpoint.PkgPath, schemas)
+		events := r.resolveStreams(content, response.Events, endpoint.PkgPath, schemas)
 
 		resolvedResponse := &ResolvedResponse{
 			StatusCode:  response.StatusCode,
 			Description: response.Description,
-			ContentType: contentType,
-			Body:        r.resolveBody(response.Body, schemas),
+			Body:        body,
+			Content:     content,
+			Stream:      response.Stream,
+			Events:      events,
+		}
+		if len(content) > 0 {
+			resolvedResponse.ContentType = content[0].ContentType
+			resolvedResponse.Body = content[0].Body
 		}
 
-		// Resolve response header references
-		for _, ref := range response.HeaderParams {
-			if param, ok := parameters[ref]; ok {
-				resolvedResponse.Headers = append(resolvedResponse.Headers, param)
-			}
+		// Downloads describe their file name with Content-Disposition
+		if response.Attachment != nil {
+			resolvedResponse.Headers = append(resolvedResponse.Headers, contentDispositionHeader(response.Attachment))
 		}
 
+		// Resolve response header references
+		responsePath := fmt.Sprintf("%s.@response[%s]", endpointPath, statusCode)
+		resolvedResponse.Headers = append(resolvedResponse.Headers, r.resolveParameterRefs(endpoint, responsePath, "header", response.HeaderParams, parameters)...)
+
 		resolved.Responses[statusCode] = resolvedResponse
 	}
 
 	// Resolve parameter references
-	for _, ref := range endpoint.PathParams {
-		if param, ok := parameters[ref]; ok {
-			resolved.PathParams = append(resolved.PathParams, param)
-		}
-	}
+	resolved.PathParams = r.resolveParameterRefs(endpoint, endpointPath, "path", endpoint.PathParams, parameters)
+	resolved.QueryParams = r.resolveParameterRefs(endpoint, endpointPath, "query", endpoint.QueryParams, parameters)
+	resolved.HeaderParams = r.resolveParameterRefs(endpoint, endpointPath, "header", endpoint.HeaderParams, parameters)
+	resolved.CookieParams = r.resolveParameterRefs(endpoint, endpointPath, "cookie", endpoint.CookieParams, parameters)
 
-	for _, ref := range endpoint.QueryParams {
-		if param, ok := parameters[ref]; ok {
-			resolved.QueryParams = append(resolved.QueryParams, param)
-		}
-	}
+	// Resolve inline declarations from function body
+	if comments := r.commentsFor(endpoint.PkgPath); comments != nil && comments.FuncInlines != nil {
+		if inlines := comments.FuncInlines[endpoint.FuncName]; inlines != nil {
+			if err := r.resolveInlineDeclarations(resolved, inlines, comments.Pkg, parameters, schemas, defaults); err != nil {
+				return nil, fmt.Errorf("failed to resolve inline declarations: %w", err)
+			}
 
-	for _, ref := range endpoint.HeaderParams {
-		if param, ok := parameters[ref]; ok {
-			resolved.HeaderParams = append(resolved.HeaderParams, param)
+			// Explicit responses win over inline ones with the same status code
+			for _, statusCode := range slices.Sorted(maps.Keys(resolved.InlineResponses)) {
+				if _, ok := resolved.Responses[statusCode]; ok {
+					r.warn(diagnostic.Warningf(inlines.Responses[statusCode].Comment.PositionOf(""), "shadowed-response", fmt.Sprintf("%s.@response[%s]", endpointPath, statusCode),
+						"inline response is ignored, the endpoint declares @response %s", statusCode))
+				}
+			}
 		}
 	}
 
-	for _, ref := range endpoint.CookieParams {
-		if param, ok := parameters[ref]; ok {
-			resolved.CookieParams = append(resolved.CookieParams, param)
-		}
-	}
+	return resolved, nil
+}
 
-	// Resolve inline declarations from function body
-	if r.comments != nil && r.comments.FuncInlines != nil {
-		if inlines := r.comments.FuncInlines[endpoint.FuncName]; inlines != nil {
-			if err := r.resolveInlineDeclarations(resolved, inlines, parameters, schemas, defaultContentType); err != nil {
-				return nil, fmt.Errorf("failed to resolve inline declarations: %w", err)
+// resolveParameterRefs returns the parameters named by the references of an endpoint
+// References to unknown parameters are reported as warnings and left out.
+func (r *Resolver) resolveParameterRefs(endpoint *parser.Endpoint, path, kind string, refs []string, parameters map[string]*ResolvedParameter) []*ResolvedParameter {
+	result := make([]*ResolvedParameter, 0, len(refs))
+	for _, ref := range refs {
+		param, ok := parameters[ref]
+		if !ok {
+			position := endpoint.Position
+			if comments := r.commentsFor(endpoint.PkgPath); comments != nil {
+				if comment := comments.GetFunctionComment(endpoint.FuncName); comment != nil {
+					position = comment.PositionOf(ref)
+				}
 			}
+			r.warn(diagnostic.Warningf(position, "unknown-parameter", path, "references unknown @%s parameter: %s", kind, ref))
+			continue
 		}
+		result = append(result, param)
 	}
-
-	return resolved, nil
+	return result
 }
 
 // extractJSONName extracts the JSON field name from a struct tag
@@ -947,6 +1227,29 @@ func extractJSONName(tag string) string {
 
 // extractTagName extracts a field name from a specific struct tag key
 func extractTagName(tag string, key string) string {
+	// json names may be quoted (encoding/json/v2)
+	if key == "json" {
+		jt := parseJSONTag(tag)
+		if jt.Skip {
+			return "-"
+		}
+		return jt.Name
+	}
+
+	// xml names may have a namespace and a path (e.g., "urn:example items>item" is named items)
+	if key == "xml" {
+		xt, ok := parseXMLTag(tag)
+		switch {
+		case !ok:
+			return ""
+		case xt.Skip:
+			return "-"
+		case len(xt.Parents) > 0:
+			return xt.Parents[0]
+		}
+		return xt.Name
+	}
+
 	// Parse struct tag
 	st := reflect.StructTag(tag)
 	value := st.Get(key)
@@ -991,10 +1294,7 @@ func resolveFieldNameFromTag(tag string, goFieldName string) string {
 			return ""
 		}
 		// Extract name part (before comma)
-		name := tagValue
-		if idx := strings.Index(tagValue, ","); idx != -1 {
-			name = tagValue[:idx]
-		}
+		name := extractTagName(tag, tagKey)
 		if name == "" {
 			// Name part is empty (e.g., `json:",omitempty"`), continue to next tag
 			continue
@@ -1006,7 +1306,8 @@ func resolveFieldNameFromTag(tag string, goFieldName string) string {
 }
 
 // resolveBody resolves a parser.Body to a ResolvedBody
-func (r *Resolver) resolveBody(body *parser.Body, schemas map[string]*ResolvedSchema) *ResolvedBody {
+// pkgPath is the package of the endpoint, used to resolve unqualified and package-qualified schema names
+func (r *Resolver) resolveBody(body *parser.Body, pkgPath string, schemas map[string]*ResolvedSchema) *ResolvedBody {
 	if body == nil {
 		return nil
 	}
@@ -1015,28 +1316,27 @@ func (r *Resolver) resolveBody(body *parser.Body, schemas map[string]*ResolvedSc
 		Schema: body.Schema,
 	}
 
-	// Parse schema to determine if it's an array or map
-	schema := strings.TrimSpace(body.Schema)
-	if strings.HasPrefix(schema, "[]") {
-		resolved.IsArray = true
-		resolved.ElementType = strings.TrimPrefix(schema, "[]")
-	} else if strings.HasPrefix(schema, "map[string]") {
-		resolved.IsMap = true
-		resolved.ElementType = strings.TrimPrefix(schema, "map[string]")
-	} else {
-		resolved.ElementType = schema
+	// Parse schema into its full shape (e.g. map[string][]User)
+	resolved.Type = r.describeBodyType(body.Schema, pkgPath, schemas)
+	resolved.IsArray = resolved.Type.Items != nil
+	resolved.IsMap = resolved.Type.MapValue != nil
+
+	leaf := resolved.Type.Innermost()
+	resolved.ElementType = leaf.Ref
+	if leaf.Ref == "" && leaf.OneOf == nil {
+		resolved.ElementType = leaf.GoType
 	}
 
 	// Resolve bind target if present
 	if body.Bind != nil {
-		resolved.Bind = r.resolveBindTarget(body.Bind, schemas)
+		resolved.Bind = r.resolveBindTarget(body.Bind, pkgPath, schemas)
 	}
 
 	return resolved
 }
 
 // resolveBindTarget resolves a parser.BindTarget to a ResolvedBindTarget
-func (r *Resolver) resolveBindTarget(bind *parser.BindTarget, schemas map[string]*ResolvedSchema) *ResolvedBindTarget {
+func (r *Resolver) resolveBindTarget(bind *parser.BindTarget, pkgPath string, schemas map[string]*ResolvedSchema) *ResolvedBindTarget {
 	if bind == nil {
 		return nil
 	}
@@ -1047,47 +1347,109 @@ func (r *Resolver) resolveBindTarget(bind *parser.BindTarget, schemas map[string
 	}
 
 	// Look up the wrapper schema
-	if wrapperSchema, ok := schemas[bind.Wrapper]; ok {
+	if wrapperSchema, ok := schemas[r.schemaComponentName(bind.Wrapper, pkgPath, schemas)]; ok {
 		resolved.WrapperSchema = wrapperSchema
 	}
 
 	return resolved
 }
 
+// schemaComponentName maps a schema reference written in an annotation to its component name
+// Unqualified names ("User") prefer the schema declared in pkgPath, then any schema with that Go name.
+// Qualified names ("models.User") are matched against the packages imported by pkgPath,
+// then against schemas whose package name matches the qualifier.
+// Returns typeName unchanged if no schema matches (validation reports unknown schemas).
+func (r *Resolver) schemaComponentName(typeName string, pkgPath string, schemas map[string]*ResolvedSchema) string {
+	if _, ok := schemas[typeName]; ok {
+		return typeName
+	}
+
+	qualifier, goName := "", typeName
+	if idx := strings.LastIndex(typeName, "."); idx >= 0 {
+		qualifier, goName = typeName[:idx], typeName[idx+1:]
+	}
+
+	var candidates []*ResolvedSchema
+	for _, schema := range schemas {
+		if schema.GoTypeName == goName {
+			candidates = append(candidates, schema)
+		}
+	}
+
+	if qualifier == "" {
+		for _, schema := range candidates {
+			if schema.PkgPath == pkgPath {
+				return schema.Name
+			}
+		}
+		if len(candidates) == 1 {
+			return candidates[0].Name
+		}
+		return typeName
+	}
+
+	// Resolve the qualifier through the imports of the endpoint's package
+	if pkg := r.packageFor(pkgPath); pkg != nil {
+		for importPath, imp := range pkg.Imports {
+			if imp.Name != qualifier {
+				continue
+			}
+			for _, schema := range candidates {
+				if schema.PkgPath == importPath {
+					return schema.Name
+				}
+			}
+		}
+	}
+
+	// Fall back to any package whose name matches the qualifier
+	var matches []*ResolvedSchema
+	for _, schema := range candidates {
+		if schema.PkgPath == qualifier || path.Base(schema.PkgPath) == qualifier {
+			matches = append(matches, schema)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0].Name
+	}
+
+	return typeName
+}
+
 // resolveInlineDeclarations resolves inline struct declarations from function body
-func (r *Resolver) resolveInlineDeclarations(endpoint *ResolvedEndpoint, inlines *parser.FuncInlineInfo, parameters map[string]*ResolvedParameter, schemas map[string]*ResolvedSchema, defaultContentType string) error {
+func (r *Resolver) resolveInlineDeclarations(endpoint *ResolvedEndpoint, inlines *parser.FuncInlineInfo, pkg *packages.Package, parameters map[string]*ResolvedParameter, schemas map[string]*ResolvedSchema, defaults contentDefaults) error {
 	// Resolve inline path parameters
 	if inlines.Path != nil {
-		params, err := r.resolveInlineParams(inlines.Path, "path")
+		params, err := r.resolveInlineParams(inlines.Path, pkg, "path")
 		if err != nil {
-			return fmt.Errorf("failed to resolve inline path params: %w", err)
+			return inlineError(inlines.Path, fmt.Errorf("failed to resolve inline path params: %w", err))
 		}
 		endpoint.InlinePathParams = params
 	}
 
 	// Resolve inline query parameters
 	if inlines.Query != nil {
-		params, err := r.resolveInlineParams(inlines.Query, "query")
+		params, err := r.resolveInlineParams(inlines.Query, pkg, "query")
 		if err != nil {
-			return fmt.Errorf("failed to resolve inline query params: %w", err)
+			return inlineError(inlines.Query, fmt.Errorf("failed to resolve inline query params: %w", err))
 		}
 		endpoint.InlineQueryParams = params
 	}
 
 	// Resolve inline header parameters
 	if inlines.Header != nil {
-		params, err := r.resolveInlineParams(inlines.Header, "header")
+		params, err := r.resolveInlineParams(inlines.Header, pkg, "header")
 		if err != nil {
-			return fmt.Errorf("failed to resolve inline header params: %w", err)
+			return inlineError(inlines.Header, fmt.Errorf("failed to resolve inline header params: %w", err))
 		}
 		endpoint.InlineHeaderParams = params
 	}
 
 	// Resolve inline cookie parameters
 	if inlines.Cookie != nil {
-		params, err := r.resolveInlineParams(inlines.Cookie, "cookie")
+		params, err := r.resolveInlineParams(inlines.Cookie, pkg, "cookie")
 		if err != nil {
-			return fmt.Errorf("failed to resolve inline cookie params: %w", err)
+			return inlineError(inlines.Cookie, fmt.Errorf("failed to resolve inline cookie params: %w", err))
 		}
 		endpoint.InlineCookieParams = params
 	}
@@ -1097,14 +1459,17 @@ func (r *Resolver) resolveInlineDeclarations(endpoint *ResolvedEndpoint, inlines
 		// Step 1: Parse inline annotation using InlineAnnotationSchema
 		parsed, err := ParseInlineAnnotation(inlines.Request.Comment, "request")
 		if err != nil {
-			return fmt.Errorf("failed to parse inline request: %w", err)
+			return inlineError(inlines.Request, fmt.Errorf("failed to parse inline request: %w", err))
 		}
 
 		// Step 2: Resolve inline body using parsed annotation
-		body, err := r.resolveInlineBody(inlines.Request, parsed, nil, schemas, defaultContentType)
+		restore := r.at(r.subpath("@request"))
+		body, err := r.resolveInlineBody(inlines.Request, pkg, parsed, nil, schemas, defaults.Requests)
+		restore()
 		if err != nil {
-			return fmt.Errorf("failed to resolve inline request body: %w", err)
+			return inlineError(inlines.Request, fmt.Errorf("failed to resolve inline request body: %w", err))
 		}
+		resolvePartHeaders(body.Fields, parameters)
 		endpoint.InlineRequest = body
 	}
 
@@ -1113,13 +1478,15 @@ func (r *Resolver) resolveInlineDeclarations(endpoint *ResolvedEndpoint, inlines
 		// Step 1: Parse inline annotation using InlineAnnotationSchema
 		parsed, err := ParseInlineAnnotation(respInfo.Comment, "response")
 		if err != nil {
-			return fmt.Errorf("failed to parse inline response %s: %w", statusCode, err)
+			return inlineError(respInfo, fmt.Errorf("failed to parse inline response %s: %w", statusCode, err))
 		}
 
 		// Step 2: Resolve inline body using parsed annotation (with parameters for header resolution)
-		body, err := r.resolveInlineBody(respInfo, parsed, parameters, schemas, defaultContentType)
+		restore := r.at(r.subpath(fmt.Sprintf("@response[%s]", statusCode)))
+		body, err := r.resolveInlineBody(respInfo, pkg, parsed, parameters, schemas, defaults.Responses)
+		restore()
 		if err != nil {
-			return fmt.Errorf("failed to resolve inline response %s: %w", statusCode, err)
+			return inlineError(respInfo, fmt.Errorf("failed to resolve inline response %s: %w", statusCode, err))
 		}
 		endpoint.InlineResponses[statusCode] = body
 	}
@@ -1127,14 +1494,20 @@ func (r *Resolver) resolveInlineDeclarations(endpoint *ResolvedEndpoint, inlines
 	return nil
 }
 
+// inlineError returns an error of an inline declaration as a diagnostic at its comment
+func inlineError(info *parser.InlineStructInfo, err error) error {
+	return diagnostic.Wrap(err, info.Comment.PositionOf(""), "invalid-inline", "")
+}
+
 // resolveInlineParams resolves an inline parameter struct
-func (r *Resolver) resolveInlineParams(info *parser.InlineStructInfo, paramType string) (*ResolvedInlineParams, error) {
+func (r *Resolver) resolveInlineParams(info *parser.InlineStructInfo, pkg *packages.Package, paramType string) (*ResolvedInlineParams, error) {
 	if info == nil || info.StructType == nil {
 		return nil, nil
 	}
+	defer r.at(r.subpath("@" + paramType))()
 
 	// Parameters don't support anonymous struct fields, so pass nil for schemaNames
-	fields, err := r.resolveInlineStructFields(info.StructType, info.FieldComments, paramType, nil)
+	fields, err := r.resolveInlineStructFields(pkg, info.StructType, info.FieldComments, paramType, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1145,7 +1518,7 @@ func (r *Resolver) resolveInlineParams(info *parser.InlineStructInfo, paramType
 }
 
 // resolveInlineBody resolves an inline request/response body struct using parsed annotation
-func (r *Resolver) resolveInlineBody(info *parser.InlineStructInfo, parsed *parser.ParsedAnnotation, parameters map[string]*ResolvedParameter, schemas map[string]*ResolvedSchema, defaultContentType string) (*ResolvedInlineBody, error) {
+func (r *Resolver) resolveInlineBody(info *parser.InlineStructInfo, pkg *packages.Package, parsed *parser.ParsedAnnotation, parameters map[string]*ResolvedParameter, schemas map[string]*ResolvedSchema, defaultContentTypes []string) (*ResolvedInlineBody, error) {
 	if info == nil || info.StructType == nil {
 		return nil, nil
 	}
@@ -1157,10 +1530,11 @@ func (r *Resolver) resolveInlineBody(info *parser.InlineStructInfo, parsed *pars
 	}
 
 	// Resolve AST struct fields (inline resolver's job)
-	fields, err := r.resolveInlineStructFields(info.StructType, info.FieldComments, "json", schemaNames)
+	fields, err := r.resolveInlineStructFields(pkg, info.StructType, info.FieldComments, "json", schemaNames)
 	if err != nil {
 		return nil, err
 	}
+	r.resolveFieldCompositions(fields, schemas)
 
 	resolved := &ResolvedInlineBody{
 		Fields: fields,
@@ -1168,10 +1542,8 @@ func (r *Resolver) resolveInlineBody(info *parser.InlineStructInfo, parsed *pars
 
 	// Use parsed annotation (already validated by inline parser)
 	if parsed != nil {
-		// Content type
-		if ct := parsed.GetChildValue("@contentType"); ct != "" {
-			resolved.ContentType = parser.ExpandContentType(ct)
-		}
+		// Content types
+		resolved.ContentTypes = parser.ExpandContentTypes(parsed.GetChildValue("@contentType"))
 
 		// Description
 		resolved.Description = parsed.GetChildValue("@description")
@@ -1179,25 +1551,32 @@ func (r *Resolver) resolveInlineBody(info *parser.InlineStructInfo, parsed *pars
 		// Bind
 		if bindValue := parsed.GetChildValue("@bind"); bindValue != "" {
 			bindTarget := parser.ParseBindTarget(bindValue)
-			resolved.Bind = r.resolveBindTarget(bindTarget, schemas)
+			var pkgPath string
+			if pkg != nil {
+				pkgPath = pkg.PkgPath
+			}
+			resolved.Bind = r.resolveBindTarget(bindTarget, pkgPath, schemas)
 		}
 
 		// Resolve header references (response only)
 		if parameters != nil {
 			for _, headerChild := range parsed.GetRepeatedChildren("@header") {
-				if param, ok := parameters[headerChild.Value]; ok {
-					resolved.Headers = append(resolved.Headers, param)
+				param, ok := parameters[headerChild.Value]
+				if !ok {
+					r.warn(diagnostic.Warningf(info.Comment.PositionOf(headerChild.Value), "unknown-parameter", r.path, "inline response references unknown @header parameter: %s", headerChild.Value))
+					continue
 				}
+				resolved.Headers = append(resolved.Headers, param)
 			}
 		}
 	}
 
-	// Apply defaults for content type
-	if resolved.ContentType == "" {
-		resolved.ContentType = defaultContentType
+	// Apply defaults for content types
+	if len(resolved.ContentTypes) == 0 {
+		resolved.ContentTypes = defaultContentTypes
 	}
-	if resolved.ContentType == "" {
-		resolved.ContentType = "application/json"
+	if len(resolved.ContentTypes) > 0 {
+		resolved.ContentType = resolved.ContentTypes[0]
 	}
 
 	return resolved, nil
@@ -1205,20 +1584,29 @@ func (r *Resolver) resolveInlineBody(info *parser.InlineStructInfo, parsed *pars
 
 // resolveInlineStructFields resolves fields from an AST struct type
 // schemaNames is optional - if provided, anonymous struct resolution is enabled
-func (r *Resolver) resolveInlineStructFields(structType *ast.StructType, fieldComments map[string]*parser.CommentBlock, tagType string, schemaNames map[string]bool) ([]*ResolvedField, error) {
+func (r *Resolver) resolveInlineStructFields(pkg *packages.Package, structType *ast.StructType, fieldComments map[string]*parser.CommentBlock, tagType string, schemaNames map[string]bool) ([]*ResolvedField, error) {
 	if structType == nil || structType.Fields == nil {
 		return nil, nil
 	}
 
 	fields := make([]*ResolvedField, 0, len(structType.Fields.List))
 
-	for _, astField := range structType.Fields.List {
-		if len(astField.Names) == 0 {
-			continue // Skip embedded fields
+	// Collect fields promoted from embedded structs, keyed by the index of the embedded field
+	promoted := make(map[int][]*structField)
+	if pkg != nil && pkg.TypesInfo != nil {
+		if typeAndValue, ok := pkg.TypesInfo.Types[structType]; ok {
+			if st, ok := typeAndValue.Type.Underlying().(*types.Struct); ok {
+				for _, sf := range collectStructFields(st, nil, tagType) {
+					if sf.depth() > 0 {
+						promoted[sf.index[0]] = append(promoted[sf.index[0]], sf)
+					}
+				}
+			}
 		}
+	}
 
-		fieldName := astField.Names[0].Name
-
+	index := 0
+	for _, astField := range structType.Fields.List {
 		// Get struct tag
 		var tag string
 		if astField.Tag != nil {
@@ -1229,14 +1617,46 @@ func (r *Resolver) resolveInlineStructFields(structType *ast.StructType, fieldCo
 			}
 		}
 
+		var jt jsonTag
+		if tagType == "json" {
+			jt = parseJSONTag(tag)
+		}
+
+		if len(astField.Names) == 0 || jt.Inline || jt.Unknown {
+			// Embedded or inlined field: promote its fields in place
+			// (inlined maps and unknown fields have none, they collect undeclared members)
+			count := max(len(astField.Names), 1)
+			for i := index; i < index+count; i++ {
+				for _, sf := range promoted[i] {
+					resolved, err := r.resolvePromotedField(sf, tagType, schemaNames)
+					if err != nil {
+						return nil, err
+					}
+					if resolved != nil {
+						fields = append(fields, resolved)
+					}
+				}
+			}
+			index += count
+			continue
+		}
+		index += len(astField.Names)
+
+		fieldName := astField.Names[0].Name
+
 		// Resolve field type using TypesInfo
 		var fieldType types.Type
-		if r.pkg != nil && r.pkg.TypesInfo != nil {
-			if typeAndValue, ok := r.pkg.TypesInfo.Types[astField.Type]; ok {
+		if pkg != nil && pkg.TypesInfo != nil {
+			if typeAndValue, ok := pkg.TypesInfo.Types[astField.Type]; ok {
 				fieldType = typeAndValue.Type
 			}
 		}
 
+		// XMLName names the element of the struct, it is not a property
+		if fieldType != nil && isXMLName(fieldName, fieldType) {
+			continue
+		}
+
 		// Extract name from appropriate struct tag
 		var resolvedName string
 		var shouldSkip bool
@@ -1271,145 +1691,55 @@ func (r *Resolver) resolveInlineStructFields(structType *ast.StructType, fieldCo
 		resolved := &ResolvedField{
 			GoName:   fieldName,
 			Name:     resolvedName,
-			Required: !strings.Contains(tag, "omitempty"),
+			Required: !omitsEmpty(tag),
+			Position: r.position(astField.Pos()),
 		}
 
 		// Resolve type info
 		if fieldType != nil {
 			resolved.GoType = fieldType.String()
 
-			// Check for anonymous structs (only when schemaNames is provided)
-			if schemaNames != nil {
-				if inlineFields := r.resolveAnonymousStruct(fieldType, schemaNames); inlineFields != nil {
-					resolved.InlineFields = inlineFields
-					resolved.OpenAPIType = "object"
-				} else if itemFields := r.resolveSliceOfAnonymousStruct(fieldType, schemaNames); itemFields != nil {
-					// Slice of anonymous struct
-					resolved.IsArray = true
-					resolved.OpenAPIType = "array"
-					resolved.ItemsType = "object"
-					resolved.ItemsInlineFields = itemFields
-				} else if mapValueFields := r.resolveMapOfAnonymousStruct(fieldType, schemaNames); mapValueFields != nil {
-					// Map with anonymous struct values
-					resolved.IsMap = true
-					resolved.OpenAPIType = "object"
-					resolved.MapValueInlineFields = mapValueFields
-				}
-			}
-
-			// If not an anonymous struct, resolve type normally
-			if resolved.OpenAPIType == "" {
-				typeInfo := r.resolveType(fieldType)
-				resolved.OpenAPIType = typeInfo.OpenAPIType
-				resolved.Format = typeInfo.Format
-				resolved.IsArray = typeInfo.IsArray
-				resolved.ItemsType = typeInfo.ItemsType
-				resolved.Nullable = typeInfo.IsNullable
-				resolved.IsAnyValue = typeInfo.IsAnyValue
-			}
+			// Resolve the Go type, including nested containers and anonymous structs
+			r.resolveFieldType(resolved, fieldType, schemaNames)
 		} else {
 			// Fallback to string if type resolution fails
 			resolved.GoType = "string"
 			resolved.OpenAPIType = "string"
 		}
 
-		// Apply field annotations if present
-		if comment := fieldComments[fieldName]; comment != nil {
-			r.applyFieldAnnotations(resolved, comment)
-		}
-
-		fields = append(fields, resolved)
-	}
-
-	return fields, nil
-}
-
-// applyFieldAnnotations applies @field annotations to a resolved field
-func (r *Resolver) applyFieldAnnotations(field *ResolvedField, comment *parser.CommentBlock) {
-	if comment == nil {
-		return
-	}
-
-	// Parse inline @field annotation
-	for _, line := range comment.Lines {
-		line = strings.TrimSpace(line)
-		if !strings.HasPrefix(line, "@field") {
-			continue
-		}
+		// Numbers and booleans tagged json:",string" are encoded as strings, json/v2 format options
+		// select the encoding of times, durations and byte slices
+		applyJSONStringOption(resolved, tag)
+		r.applyJSONFormat(resolved, tag, astField.Pos())
 
-		// Extract content between braces
-		start := strings.Index(line, "{")
-		end := strings.LastIndex(line, "}")
-		if start == -1 || end == -1 || end <= start {
-			continue
+		// Element and attribute names, namespaces and wrapped arrays of xml tags
+		if tagType == "json" {
+			r.applyXMLTag(resolved, tag, astField.Pos())
 		}
-		content := line[start+1 : end]
 
-		// Parse individual annotations
-		parts := strings.Split(content, "@")
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			if part == "" {
-				continue
-			}
+		// Translate validate/binding tag rules (overridden by @field annotations)
+		r.applyValidateTags(resolved, tag, astField.Pos())
 
-			// Split into annotation name and value
-			spaceIdx := strings.Index(part, " ")
-			var annotName, annotValue string
-			if spaceIdx > 0 {
-				annotName = "@" + part[:spaceIdx]
-				annotValue = strings.TrimSpace(part[spaceIdx+1:])
-			} else {
-				annotName = "@" + part
+		// Apply field annotations if present
+		annotation, err := parser.ParseField(fieldName, fieldComments[fieldName])
+		if err != nil {
+			return nil, err
+		}
+		if annotation != nil {
+			if fieldType == nil {
+				fieldType = types.Typ[types.String]
 			}
-
-			switch annotName {
-			case "@description":
-				field.Description = annotValue
-			case "@format":
-				field.Format = annotValue
-			case "@example":
-				field.Example = annotValue
-			case "@default":
-				field.Default = annotValue
-			case "@pattern":
-				field.Pattern = annotValue
-			case "@enum":
-				field.Enum = strings.Split(annotValue, ",")
-				for i := range field.Enum {
-					field.Enum[i] = strings.TrimSpace(field.Enum[i])
-				}
-			case "@deprecated":
-				field.Deprecated = true
-			case "@minimum":
-				if val, err := parseFloat(annotValue); err == nil {
-					field.Minimum = &val
-				}
-			case "@maximum":
-				if val, err := parseFloat(annotValue); err == nil {
-					field.Maximum = &val
-				}
-			case "@minLength":
-				if val, err := parseInt(annotValue); err == nil {
-					field.MinLength = &val
-				}
-			case "@maxLength":
-				if val, err := parseInt(annotValue); err == nil {
-					field.MaxLength = &val
-				}
-			case "@minItems":
-				if val, err := parseInt(annotValue); err == nil {
-					field.MinItems = &val
-				}
-			case "@maxItems":
-				if val, err := parseInt(annotValue); err == nil {
-					field.MaxItems = &val
-				}
-			case "@uniqueItems":
-				field.UniqueItems = true
+			pkgPath := ""
+			if pkg != nil {
+				pkgPath = pkg.PkgPath
 			}
+			r.applyFieldAnnotation(resolved, annotation, fieldType, astField.Pos(), pkgPath)
 		}
+
+		fields = append(fields, resolved)
 	}
+
+	return fields, nil
 }
 
 // parseFloat parses a string to float64
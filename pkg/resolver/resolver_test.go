@@ -1,9 +1,18 @@
 package resolver
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/wontaeyang/go-specgen/pkg/diagnostic"
 	"github.com/wontaeyang/go-specgen/pkg/parser"
+	"github.com/wontaeyang/go-specgen/pkg/parser/testdata/jsonenc"
 )
 
 func TestNewResolver(t *testing.T) {
@@ -32,6 +41,25 @@ func TestNewResolver_InvalidPath(t *testing.T) {
 	}
 }
 
+func TestNewResolverFromComments(t *testing.T) {
+	p := parser.NewParser("../parser/testdata")
+	if _, err := p.Parse(); err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolverFromComments(p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolverFromComments() error = %v", err)
+	}
+	if resolver.pkg == nil || resolver.pkg != p.AllComments()[0].Pkg {
+		t.Error("Resolver.pkg should be the package of the first comments")
+	}
+
+	if _, err := NewResolverFromComments(); err == nil {
+		t.Error("NewResolverFromComments() should error without loaded packages")
+	}
+}
+
 func TestResolver_Resolve(t *testing.T) {
 	// Parse the test package first
 	p := parser.NewParser("../parser/testdata")
@@ -77,6 +105,91 @@ func TestResolver_Resolve(t *testing.T) {
 	}
 }
 
+func TestResolver_Positions(t *testing.T) {
+	p := parser.NewParser("../parser/testdata")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata", p.Comments())
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		pos        token.Position
+		wantLine   int
+		wantColumn int
+	}{
+		{name: "api", pos: resolved.API.Position, wantLine: 1, wantColumn: 1},
+		{name: "schema field", pos: resolved.Schemas["User"].Fields[0].Position, wantLine: 13, wantColumn: 2},
+		{name: "parameter", pos: resolved.Parameters["UserIDPath"].Position, wantLine: 24, wantColumn: 1},
+		{name: "parameter field", pos: resolved.Parameters["UserIDPath"].Fields[0].Position, wantLine: 27, wantColumn: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.HasSuffix(tt.pos.Filename, "sample.go") || tt.pos.Line != tt.wantLine || tt.pos.Column != tt.wantColumn {
+				t.Errorf("Position = %s, want sample.go:%d:%d", tt.pos, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+}
+
+func TestResolver_Resolve_CollectsErrors(t *testing.T) {
+	p := parser.NewParser("../parser/testdata")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	// Declarations without a Go type fail to resolve
+	ghostPos := token.Position{Filename: "sample.go", Line: 90, Column: 1}
+	parsed.Schemas["Ghost"] = &parser.Schema{Name: "Ghost", GoTypeName: "Ghost", Position: ghostPos}
+	parsed.Parameters["GhostPath"] = &parser.Parameter{Name: "GhostPath", Type: parser.PathParameter, GoTypeName: "GhostPath", Position: ghostPos}
+
+	resolver, err := NewResolver("../parser/testdata", p.Comments())
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if resolved == nil {
+		t.Fatalf("Resolve() result = nil, want the declarations without errors (error = %v)", err)
+	}
+
+	diagnostics := diagnostic.List(err)
+	if len(diagnostics) != 2 {
+		t.Fatalf("Resolve() error = %v, want 2 diagnostics", err)
+	}
+	wantPaths := []string{"@schema[Ghost]", "@path[GhostPath]"}
+	for i, d := range diagnostics {
+		if d.Path != wantPaths[i] || d.Rule != "unresolved-type" || d.Pos != ghostPos {
+			t.Errorf("diagnostic[%d] = %s, want an unresolved-type error at %s in %s", i, d, ghostPos, wantPaths[i])
+		}
+	}
+
+	if _, ok := resolved.Schemas["Ghost"]; ok {
+		t.Error("schema Ghost should be skipped")
+	}
+	if _, ok := resolved.Schemas["User"]; !ok {
+		t.Error("schema User should be resolved")
+	}
+	if _, ok := resolved.Parameters["UserIDPath"]; !ok {
+		t.Error("parameter UserIDPath should be resolved")
+	}
+	if len(resolved.Endpoints) != len(parsed.Endpoints) {
+		t.Errorf("endpoints = %d, want %d", len(resolved.Endpoints), len(parsed.Endpoints))
+	}
+}
+
 func TestResolver_ResolveSchema(t *testing.T) {
 	// Parse the test package first
 	p := parser.NewParser("../parser/testdata")
@@ -370,7 +483,7 @@ func TestResolver_ResolveEndpoint(t *testing.T) {
 	endpoint := parsed.Endpoints[0]
 
 	// Resolve it
-	resolved, err := resolver.resolveEndpoint(endpoint, parameters, schemas, "")
+	resolved, err := resolver.resolveEndpoint(endpoint, parameters, schemas, newContentDefaults(nil))
 	if err != nil {
 		t.Fatalf("resolveEndpoint() error = %v", err)
 	}
@@ -678,32 +791,52 @@ func TestParseInlineAnnotation(t *testing.T) {
 func TestContentTypePrecedence(t *testing.T) {
 	tests := []struct {
 		name             string
-		explicitType     string
-		defaultType      string
+		explicitTypes    []string
+		api              *ResolvedAPI
 		expectedRequest  string
 		expectedResponse string
 	}{
 		{
 			name:             "explicit overrides default",
-			explicitType:     "application/xml",
-			defaultType:      "application/json",
+			explicitTypes:    []string{"application/xml"},
+			api:              &ResolvedAPI{DefaultContentType: "application/json"},
 			expectedRequest:  "application/xml",
 			expectedResponse: "application/xml",
 		},
 		{
 			name:             "default used when no explicit",
-			explicitType:     "",
-			defaultType:      "application/xml",
+			api:              &ResolvedAPI{DefaultContentType: "application/xml"},
 			expectedRequest:  "application/xml",
 			expectedResponse: "application/xml",
 		},
 		{
 			name:             "fallback to json when no explicit or default",
-			explicitType:     "",
-			defaultType:      "",
 			expectedRequest:  "application/json",
 			expectedResponse: "application/json",
 		},
+		{
+			name:             "explicit list",
+			explicitTypes:    []string{"application/json", "application/xml"},
+			api:              &ResolvedAPI{Produces: []string{"text/csv"}},
+			expectedRequest:  "application/json,application/xml",
+			expectedResponse: "application/json,application/xml",
+		},
+		{
+			name: "consumes and produces override default",
+			api: &ResolvedAPI{
+				DefaultContentType: "application/json",
+				Consumes:           []string{"application/json", "application/x-www-form-urlencoded"},
+				Produces:           []string{"application/json", "application/xml", "text/csv"},
+			},
+			expectedRequest:  "application/json,application/x-www-form-urlencoded",
+			expectedResponse: "application/json,application/xml,text/csv",
+		},
+		{
+			name:             "produces only",
+			api:              &ResolvedAPI{DefaultContentType: "application/xml", Produces: []string{"text/csv"}},
+			expectedRequest:  "application/xml",
+			expectedResponse: "text/csv",
+		},
 	}
 
 	for _, tt := range tests {
@@ -717,15 +850,15 @@ func TestContentTypePrecedence(t *testing.T) {
 				Method: "POST",
 				Path:   "/test",
 				Request: &parser.RequestBody{
-					ContentType: tt.explicitType,
+					ContentTypes: tt.explicitTypes,
 					Body: &parser.Body{
 						Schema: "TestBody",
 					},
 				},
 				Responses: map[string]*parser.Response{
 					"200": {
-						StatusCode:  "200",
-						ContentType: tt.explicitType,
+						StatusCode:   "200",
+						ContentTypes: tt.explicitTypes,
 						Body: &parser.Body{
 							Schema: "TestBody",
 						},
@@ -733,22 +866,102 @@ func TestContentTypePrecedence(t *testing.T) {
 				},
 			}
 
-			resolved, err := resolver.resolveEndpoint(endpoint, map[string]*ResolvedParameter{}, map[string]*ResolvedSchema{}, tt.defaultType)
+			resolved, err := resolver.resolveEndpoint(endpoint, map[string]*ResolvedParameter{}, map[string]*ResolvedSchema{}, newContentDefaults(tt.api))
 			if err != nil {
 				t.Fatalf("resolveEndpoint() error = %v", err)
 			}
 
-			if resolved.Request != nil && resolved.Request.ContentType != tt.expectedRequest {
-				t.Errorf("Request.ContentType = %q, want %q", resolved.Request.ContentType, tt.expectedRequest)
+			if got := contentTypesString(resolved.Request.MediaTypes()); got != tt.expectedRequest {
+				t.Errorf("Request content types = %q, want %q", got, tt.expectedRequest)
+			}
+			if got := resolved.Request.ContentType; got != strings.Split(tt.expectedRequest, ",")[0] {
+				t.Errorf("Request.ContentType = %q, want first of %q", got, tt.expectedRequest)
+			}
+
+			if got := contentTypesString(resolved.Responses["200"].MediaTypes()); got != tt.expectedResponse {
+				t.Errorf("Response content types = %q, want %q", got, tt.expectedResponse)
 			}
+		})
+	}
+}
+
+func TestResolveEndpoint_ContentBlocks(t *testing.T) {
+	resolver, err := NewResolver("../parser/testdata", nil)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
 
-			if resp, ok := resolved.Responses["200"]; ok {
-				if resp.ContentType != tt.expectedResponse {
-					t.Errorf("Response.ContentType = %q, want %q", resp.ContentType, tt.expectedResponse)
+	endpoint := &parser.Endpoint{
+		Method: "GET",
+		Path:   "/users/export",
+		Responses: map[string]*parser.Response{
+			// Shared body with the default content types, plus blocks
+			"200": {
+				StatusCode: "200",
+				Body:       &parser.Body{Schema: "[]User"},
+				Content: []*parser.Content{
+					{ContentType: "application/xml"},
+					{ContentType: "text/csv", Body: &parser.Body{Schema: "string"}},
+				},
+			},
+			// Blocks only
+			"202": {
+				StatusCode: "202",
+				Content: []*parser.Content{
+					{ContentType: "text/plain", Body: &parser.Body{Schema: "string"}},
+				},
+			},
+			// No body
+			"204": {
+				StatusCode: "204",
+			},
+		},
+	}
+
+	defaults := newContentDefaults(&ResolvedAPI{Produces: []string{"application/json", "application/xml"}})
+	resolved, err := resolver.resolveEndpoint(endpoint, map[string]*ResolvedParameter{}, map[string]*ResolvedSchema{}, defaults)
+	if err != nil {
+		t.Fatalf("resolveEndpoint() error = %v", err)
+	}
+
+	tests := []struct {
+		status string
+		want   string
+	}{
+		// application/xml is given by a block, so only application/json is a default
+		{status: "200", want: "application/json:[]User application/xml:[]User text/csv:string"},
+		{status: "202", want: "text/plain:string"},
+		{status: "204", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			var parts []string
+			for _, content := range resolved.Responses[tt.status].Content {
+				schema := "-"
+				if content.Body != nil {
+					schema = content.Body.Schema
 				}
+				parts = append(parts, content.ContentType+":"+schema)
+			}
+			if got := strings.Join(parts, " "); got != tt.want {
+				t.Errorf("Content = %q, want %q", got, tt.want)
 			}
 		})
 	}
+
+	if got := resolved.Responses["202"]; got.ContentType != "text/plain" || got.Body == nil || got.Body.Schema != "string" {
+		t.Errorf("202 ContentType/Body = %q/%+v, want the first block", got.ContentType, got.Body)
+	}
+}
+
+// contentTypesString joins the content types of media types with commas
+func contentTypesString(content []*ResolvedContent) string {
+	var contentTypes []string
+	for _, c := range content {
+		contentTypes = append(contentTypes, c.ContentType)
+	}
+	return strings.Join(contentTypes, ",")
 }
 
 func TestResolveFieldNameFromTag(t *testing.T) {
@@ -830,6 +1043,18 @@ func TestResolveFieldNameFromTag(t *testing.T) {
 			goFieldName: "Field",
 			want:        "FieldName",
 		},
+		{
+			name:        "xml path - uses the outer element",
+			tag:         `xml:"items>item"`,
+			goFieldName: "Items",
+			want:        "items",
+		},
+		{
+			name:        "xml namespace - uses the local name",
+			tag:         `xml:"urn:example id,attr"`,
+			goFieldName: "ID",
+			want:        "id",
+		},
 		{
 			name:        "json name with xml takes priority",
 			tag:         `json:"json_name" xml:"XmlName"`,
@@ -842,6 +1067,12 @@ func TestResolveFieldNameFromTag(t *testing.T) {
 			goFieldName: "MyField",
 			want:        "MyField",
 		},
+		{
+			name:        "json/v2 quoted name with comma",
+			tag:         `json:"'first,last',omitzero"`,
+			goFieldName: "Name",
+			want:        "first,last",
+		},
 	}
 
 	for _, tt := range tests {
@@ -853,3 +1084,1505 @@ func TestResolveFieldNameFromTag(t *testing.T) {
 		})
 	}
 }
+
+func TestResolver_MultiPackage(t *testing.T) {
+	p := parser.NewMultiParser([]string{"../parser/testdata/multi/..."}, "")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse packages: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/multi/...", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// Schemas are looked up in their declaring package
+	user, ok := resolved.Schemas["User"]
+	if !ok {
+		t.Fatal("User schema not resolved")
+	}
+	if len(user.Fields) != 2 {
+		t.Errorf("User has %d fields, want 2", len(user.Fields))
+	}
+
+	if _, ok := resolved.Parameters["UserIDPath"]; !ok {
+		t.Error("UserIDPath parameter not resolved")
+	}
+
+	// Inline declarations are resolved with the endpoint's own package
+	var createOrder *ResolvedEndpoint
+	for _, ep := range resolved.Endpoints {
+		if ep.FuncName == "CreateOrder" {
+			createOrder = ep
+			break
+		}
+	}
+	if createOrder == nil {
+		t.Fatal("CreateOrder endpoint not found")
+	}
+	if createOrder.InlineQueryParams == nil || len(createOrder.InlineQueryParams.Fields) != 1 {
+		t.Fatal("CreateOrder should have one inline query param")
+	}
+	if got := createOrder.InlineQueryParams.Fields[0].OpenAPIType; got != "boolean" {
+		t.Errorf("dryRun type = %q, want %q", got, "boolean")
+	}
+}
+
+func TestResolver_ImportedSchemas(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/imports/handlers")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/imports/handlers", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// Fields typed with an imported @schema are not reported as unresolved
+	user, ok := resolved.Schemas["handlers.User"]
+	if !ok {
+		t.Fatal("handlers.User schema not resolved")
+	}
+	for _, field := range user.Fields {
+		if field.IsUnresolvedStruct {
+			t.Errorf("field %s should reference an imported schema, got unresolved %s", field.Name, field.UnresolvedTypeName)
+		}
+	}
+
+	if _, ok := resolved.Schemas["models.User"]; !ok {
+		t.Error("models.User schema not resolved")
+	}
+
+	// Body references resolve to component names
+	tests := []struct {
+		funcName    string
+		elementType string
+	}{
+		{"GetUser", "handlers.User"},
+		{"ListProfiles", "models.User"},
+		{"GetAddress", "Address"},
+	}
+	for _, tt := range tests {
+		var endpoint *ResolvedEndpoint
+		for _, ep := range resolved.Endpoints {
+			if ep.FuncName == tt.funcName {
+				endpoint = ep
+			}
+		}
+		if endpoint == nil {
+			t.Errorf("%s endpoint not found", tt.funcName)
+			continue
+		}
+		body := endpoint.Responses["200"].Body
+		if body.ElementType != tt.elementType {
+			t.Errorf("%s body ElementType = %q, want %q", tt.funcName, body.ElementType, tt.elementType)
+		}
+	}
+}
+
+func TestResolver_EmbeddedStructs(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/embedded")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/embedded", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	const basePath = "github.com/wontaeyang/go-specgen/pkg/parser/testdata/embedded"
+
+	fieldNames := func(fields []*ResolvedField) []string {
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = f.Name
+		}
+		return names
+	}
+
+	tests := []struct {
+		schema string
+		fields []string
+	}{
+		// Promoted fields appear at the position of the embedded field
+		{"User", []string{"id", "createdAt", "updatedBy", "name"}},
+		{"Admin", []string{"id", "createdAt", "level"}},
+		// Shallower fields shadow promoted ones
+		{"Override", []string{"createdAt", "id"}},
+		// Conflicting names are dropped, a single tagged field wins, tagged embeds are regular fields
+		{"Conflict", []string{"Title", "base"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			schema, ok := resolved.Schemas[tt.schema]
+			if !ok {
+				t.Fatalf("schema %s not resolved", tt.schema)
+			}
+			got := fieldNames(schema.Fields)
+			if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+
+	user := resolved.Schemas["User"]
+	if user.Fields[0].EmbeddedFrom != basePath+".Base" {
+		t.Errorf("id EmbeddedFrom = %q, want %q", user.Fields[0].EmbeddedFrom, basePath+".Base")
+	}
+	if user.Fields[0].Description != "Unique identifier" {
+		t.Errorf("id Description = %q, want promoted annotation", user.Fields[0].Description)
+	}
+	if user.Fields[2].Description != "Last editor" {
+		t.Errorf("updatedBy Description = %q, want promoted annotation", user.Fields[2].Description)
+	}
+	if user.Fields[3].EmbeddedFrom != "" {
+		t.Errorf("name EmbeddedFrom = %q, want empty", user.Fields[3].EmbeddedFrom)
+	}
+
+	if !resolved.Schemas["Admin"].AllOf {
+		t.Error("Admin should have AllOf set")
+	}
+
+	// Parameter structs promote embedded fields using the parameter tag
+	query := resolved.Parameters["ListQuery"]
+	if got := fieldNames(query.Fields); strings.Join(got, ",") != "limit,offset,q" {
+		t.Errorf("ListQuery fields = %v, want [limit offset q]", got)
+	}
+	if query.Fields[0].Description != "Maximum results" {
+		t.Errorf("limit Description = %q, want promoted annotation", query.Fields[0].Description)
+	}
+
+	// Inline structs promote embedded fields too
+	for _, ep := range resolved.Endpoints {
+		if ep.FuncName != "ListAdmins" {
+			continue
+		}
+		if ep.InlineQueryParams == nil {
+			t.Fatal("ListAdmins should have inline query params")
+		}
+		if got := fieldNames(ep.InlineQueryParams.Fields); strings.Join(got, ",") != "limit,offset,level" {
+			t.Errorf("inline query fields = %v, want [limit offset level]", got)
+		}
+	}
+}
+
+func TestResolver_ConstEnums(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/enums")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/enums", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	fields := make(map[string]*ResolvedField)
+	for _, f := range resolved.Schemas["Task"].Fields {
+		fields[f.Name] = f
+	}
+	fields["query.status"] = resolved.Parameters["TaskQuery"].Fields[0]
+
+	tests := []struct {
+		field        string
+		enum         []string
+		varNames     []string
+		descriptions []string
+	}{
+		{
+			field:        "status",
+			enum:         []string{"pending", "running", "done"},
+			varNames:     []string{"TaskPending", "TaskRunning", "TaskDone"},
+			descriptions: []string{"Task is waiting to be picked up", "Task is being processed", ""},
+		},
+		{
+			field:        "priority",
+			enum:         []string{"0", "1", "2"},
+			varNames:     []string{"PriorityLow", "PriorityMedium", "PriorityHigh"},
+			descriptions: []string{"", "", ""},
+		},
+		{
+			field:        "labels",
+			enum:         []string{"pending", "running", "done"},
+			varNames:     []string{"TaskPending", "TaskRunning", "TaskDone"},
+			descriptions: []string{"Task is waiting to be picked up", "Task is being processed", ""},
+		},
+		{
+			// Explicit @enum wins over derived values
+			field: "filter",
+			enum:  []string{"pending", "done"},
+		},
+		{field: "timeout"},
+		{field: "mode"},
+		{
+			field:        "query.status",
+			enum:         []string{"pending", "running", "done"},
+			varNames:     []string{"TaskPending", "TaskRunning", "TaskDone"},
+			descriptions: []string{"Task is waiting to be picked up", "Task is being processed", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			f, ok := fields[tt.field]
+			if !ok {
+				t.Fatalf("field %s not resolved", tt.field)
+			}
+			if strings.Join(f.Enum, ",") != strings.Join(tt.enum, ",") {
+				t.Errorf("Enum = %v, want %v", f.Enum, tt.enum)
+			}
+			if strings.Join(f.EnumVarNames, ",") != strings.Join(tt.varNames, ",") {
+				t.Errorf("EnumVarNames = %v, want %v", f.EnumVarNames, tt.varNames)
+			}
+			if strings.Join(f.EnumDescriptions, "|") != strings.Join(tt.descriptions, "|") {
+				t.Errorf("EnumDescriptions = %q, want %q", f.EnumDescriptions, tt.descriptions)
+			}
+		})
+	}
+}
+
+func TestResolver_ValueSchemas(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/valuetypes")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/valuetypes", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	tests := []struct {
+		schema      string
+		openAPIType string
+		itemsType   string
+		goType      string
+		enum        []string
+	}{
+		{schema: "Currency", openAPIType: "string", goType: "string", enum: []string{"USD", "EUR"}},
+		{schema: "Money", openAPIType: "integer", goType: "int64"},
+		// Explicit @enum wins over typed constants
+		{schema: "Color", openAPIType: "string", goType: "string", enum: []string{"red", "green"}},
+		{schema: "Tags", openAPIType: "array", itemsType: "string", goType: "[]string"},
+		{
+			schema:      "Currencies",
+			openAPIType: "array",
+			itemsType:   "string",
+			goType:      "[]github.com/wontaeyang/go-specgen/pkg/parser/testdata/valuetypes.Currency",
+			enum:        []string{"USD", "EUR"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			schema, ok := resolved.Schemas[tt.schema]
+			if !ok {
+				t.Fatalf("schema %s not resolved", tt.schema)
+			}
+			if schema.Value == nil {
+				t.Fatal("Value should be set for a named non-struct type")
+			}
+			if len(schema.Fields) != 0 {
+				t.Errorf("Fields = %d, want none", len(schema.Fields))
+			}
+			if schema.Value.OpenAPIType != tt.openAPIType {
+				t.Errorf("OpenAPIType = %q, want %q", schema.Value.OpenAPIType, tt.openAPIType)
+			}
+			if schema.Value.ItemsType != tt.itemsType {
+				t.Errorf("ItemsType = %q, want %q", schema.Value.ItemsType, tt.itemsType)
+			}
+			if schema.Value.GoType != tt.goType {
+				t.Errorf("GoType = %q, want %q", schema.Value.GoType, tt.goType)
+			}
+			if strings.Join(schema.Value.Enum, ",") != strings.Join(tt.enum, ",") {
+				t.Errorf("Enum = %v, want %v", schema.Value.Enum, tt.enum)
+			}
+		})
+	}
+
+	currency := resolved.Schemas["Currency"]
+	if currency.Description != "ISO 4217 currency code" || currency.Value.Pattern != "^[A-Z]{3}$" {
+		t.Errorf("Currency description/pattern = %q/%q", currency.Description, currency.Value.Pattern)
+	}
+	money := resolved.Schemas["Money"].Value
+	if money.Format != "int64" || money.Minimum == nil || *money.Minimum != 0 {
+		t.Errorf("Money format/minimum = %q/%v", money.Format, money.Minimum)
+	}
+	tags := resolved.Schemas["Tags"].Value
+	if tags.MaxItems == nil || *tags.MaxItems != 10 || !tags.UniqueItems {
+		t.Errorf("Tags maxItems/uniqueItems = %v/%v", tags.MaxItems, tags.UniqueItems)
+	}
+}
+
+func TestApplyValidateTags(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name         string
+		field        ResolvedField
+		tag          string
+		want         ResolvedField
+		wantWarnings int
+	}{
+		{
+			name:  "string rules",
+			field: ResolvedField{GoName: "Email", OpenAPIType: "string"},
+			tag:   `json:"email,omitempty" validate:"required,min=3,max=100,email"`,
+			want:  ResolvedField{GoName: "Email", OpenAPIType: "string", Required: true, MinLength: intPtr(3), MaxLength: intPtr(100), Format: "email"},
+		},
+		{
+			name:  "numeric bounds",
+			field: ResolvedField{GoName: "Age", OpenAPIType: "integer"},
+			tag:   `validate:"gt=0,lte=150"`,
+			want:  ResolvedField{GoName: "Age", OpenAPIType: "integer", Minimum: floatPtr(1), Maximum: floatPtr(150)},
+		},
+		{
+			name:  "array bounds and unique",
+			field: ResolvedField{GoName: "Tags", OpenAPIType: "array", IsArray: true, ItemsType: "string"},
+			tag:   `validate:"min=1,unique,dive,oneof=a 'b c'"`,
+			want:  ResolvedField{GoName: "Tags", OpenAPIType: "array", IsArray: true, ItemsType: "string", MinItems: intPtr(1), UniqueItems: true, Enum: []string{"a", "b c"}},
+		},
+		{
+			name:  "oneof and binding tag",
+			field: ResolvedField{GoName: "Status", OpenAPIType: "string"},
+			tag:   `binding:"required,oneof=active inactive"`,
+			want:  ResolvedField{GoName: "Status", OpenAPIType: "string", Required: true, Enum: []string{"active", "inactive"}},
+		},
+		{
+			name:  "len and datetime",
+			field: ResolvedField{GoName: "Day", OpenAPIType: "string"},
+			tag:   `validate:"len=10,datetime=2006-01-02"`,
+			want:  ResolvedField{GoName: "Day", OpenAPIType: "string", MinLength: intPtr(10), MaxLength: intPtr(10), Format: "date"},
+		},
+		{
+			name:         "untranslatable rules warn",
+			field:        ResolvedField{GoName: "Ratio", OpenAPIType: "number"},
+			tag:          `validate:"gt=0,required_with=Other,email|url"`,
+			want:         ResolvedField{GoName: "Ratio", OpenAPIType: "number"},
+			wantWarnings: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Resolver{}
+			field := tt.field
+			r.applyValidateTags(&field, tt.tag, token.NoPos)
+
+			if !reflect.DeepEqual(field, tt.want) {
+				t.Errorf("field = %+v, want %+v", field, tt.want)
+			}
+			if len(r.Warnings()) != tt.wantWarnings {
+				t.Errorf("warnings = %v, want %d", r.Warnings(), tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestResolver_ValidateTags(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/validate")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/validate", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	fields := resolved.Schemas["CreateUser"].Fields
+
+	name := fields[0]
+	if !name.Required || name.MinLength == nil || *name.MinLength != 1 || name.MaxLength == nil || *name.MaxLength != 100 {
+		t.Errorf("name = required %v, minLength %v, maxLength %v", name.Required, name.MinLength, name.MaxLength)
+	}
+
+	// @field overrides the validate tag
+	if nickname := fields[1]; nickname.MaxLength == nil || *nickname.MaxLength != 50 {
+		t.Errorf("nickname maxLength = %v, want 50", nickname.MaxLength)
+	}
+
+	if email := fields[2]; email.Required || email.Format != "email" {
+		t.Errorf("email = required %v, format %q", email.Required, email.Format)
+	}
+
+	warnings := resolver.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want 1", warnings)
+	}
+	if !strings.Contains(warnings[0].Error(), "validate.go:18:2") || !strings.Contains(warnings[0].Error(), `"excludesall=!@"`) {
+		t.Errorf("warning = %q, want position and rule", warnings[0].Error())
+	}
+	if warnings[0].Severity != diagnostic.SeverityWarning || warnings[0].Rule != "unsupported-validate-rule" {
+		t.Errorf("warning = %s, want an unsupported-validate-rule warning", warnings[0])
+	}
+}
+
+func TestResolver_Warnings(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/warnings")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/warnings", p.Comments())
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// Unknown references are left out
+	endpoint := resolved.Endpoints[0]
+	if len(endpoint.QueryParams) != 0 || len(endpoint.Responses["200"].Headers) != 0 {
+		t.Errorf("query params = %v, headers = %v, want none", endpoint.QueryParams, endpoint.Responses["200"].Headers)
+	}
+
+	var got []string
+	for _, w := range resolver.Warnings() {
+		got = append(got, fmt.Sprintf("%d:%s", w.Pos.Line, w.Rule))
+	}
+	want := []string{
+		"20:unsupported-validate-rule",
+		"12:unsupported-validate-rule",
+		"14:unsupported-validate-rule",
+		"28:unknown-parameter",
+		"24:unknown-parameter",
+		"32:shadowed-response",
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("warnings = %v, want %v", got, want)
+	}
+
+	// Field warnings are located by annotation path, not in their message
+	paths := map[int]string{20: "@schema[Profile].Blog", 12: "@schema[User].Website", 14: "@schema[User].Homepage"}
+	for _, w := range resolver.Warnings() {
+		if w.Rule != "unsupported-validate-rule" {
+			continue
+		}
+		if w.Path != paths[w.Pos.Line] {
+			t.Errorf("line %d path = %q, want %q", w.Pos.Line, w.Path, paths[w.Pos.Line])
+		}
+		if strings.Contains(w.Message, "field") {
+			t.Errorf("line %d message = %q, want no field name", w.Pos.Line, w.Message)
+		}
+	}
+
+	// specgen:ignore comments silence the warnings of their declaration
+	filter := &diagnostic.Filter{Suppressions: p.Suppressions()}
+	got = nil
+	for _, w := range filter.Apply(resolver.Warnings()) {
+		got = append(got, fmt.Sprintf("%d:%s", w.Pos.Line, w.Rule))
+	}
+	want = []string{"14:unsupported-validate-rule", "28:unknown-parameter", "24:unknown-parameter", "32:shadowed-response"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("filtered warnings = %v, want %v", got, want)
+	}
+}
+
+func TestResolver_TypeMappings(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/typemap")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/typemap", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	const pkgPath = "github.com/wontaeyang/go-specgen/pkg/parser/testdata/typemap"
+	moneySchema := map[string]any{"type": "object", "properties": map[string]any{"amount": map[string]any{"type": "integer"}}}
+	mappings := map[string]*TypeMapping{
+		pkgPath + ".Decimal": {Type: "string", Pattern: `^-?[0-9]+(\.[0-9]+)?$`},
+		pkgPath + ".Money":   {Schema: moneySchema},
+		// Configured mappings win over @typeMapping
+		"time.Time": {Type: "string", Format: "date-time"},
+	}
+	for goType, mapping := range mappings {
+		if err := resolver.AddTypeMapping(goType, mapping); err != nil {
+			t.Fatalf("AddTypeMapping(%s) error = %v", goType, err)
+		}
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	fields := make(map[string]*ResolvedField)
+	for _, f := range resolved.Schemas["Invoice"].Fields {
+		fields[f.Name] = f
+	}
+
+	tests := []struct {
+		field       string
+		openAPIType string
+		format      string
+		pattern     string
+		itemsType   string
+	}{
+		{field: "id", openAPIType: "string", format: "uuid"},
+		{field: "total", openAPIType: "string", pattern: `^-?[0-9]+(\.[0-9]+)?$`},
+		{field: "price", openAPIType: "object"},
+		{field: "due", openAPIType: "string", format: "date-time"},
+		{field: "tags", openAPIType: "array", itemsType: "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			f := fields[tt.field]
+			if f.OpenAPIType != tt.openAPIType || f.Format != tt.format || f.Pattern != tt.pattern || f.ItemsType != tt.itemsType {
+				t.Errorf("got type=%q format=%q pattern=%q items=%q, want type=%q format=%q pattern=%q items=%q",
+					f.OpenAPIType, f.Format, f.Pattern, f.ItemsType, tt.openAPIType, tt.format, tt.pattern, tt.itemsType)
+			}
+			if f.IsUnresolvedStruct {
+				t.Error("mapped struct types should not be reported as unresolved")
+			}
+		})
+	}
+
+	if price := fields["price"]; price.MappedSchema == nil || !price.Nullable {
+		t.Errorf("price MappedSchema = %v, Nullable = %v", price.MappedSchema, price.Nullable)
+	}
+}
+
+func TestResolver_AddTypeMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		goType  string
+		mapping *TypeMapping
+		wantErr bool
+	}{
+		{name: "qualified type", goType: "github.com/google/uuid.UUID", mapping: &TypeMapping{Type: "string", Format: "uuid"}},
+		{name: "package name", goType: "decimal.Decimal", mapping: &TypeMapping{Type: "string"}},
+		{name: "schema", goType: "example.com/types.Money", mapping: &TypeMapping{Schema: map[string]any{"type": "object"}}},
+		{name: "unqualified type", goType: "UUID", mapping: &TypeMapping{Type: "string"}, wantErr: true},
+		{name: "missing type", goType: "uuid.UUID", mapping: &TypeMapping{Format: "uuid"}, wantErr: true},
+		{name: "type and schema", goType: "uuid.UUID", mapping: &TypeMapping{Type: "string", Schema: map[string]any{}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Resolver{typeMappings: make(map[string]*TypeMapping)}
+			err := r.AddTypeMapping(tt.goType, tt.mapping)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddTypeMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolver_Marshalers(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/marshal")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/marshal", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	fields := make(map[string]*ResolvedField)
+	for _, f := range resolved.Schemas["Event"].Fields {
+		fields[f.Name] = f
+	}
+
+	tests := []struct {
+		field       string
+		openAPIType string
+		itemsType   string
+		anyValue    bool
+	}{
+		// TextMarshaler types are strings, and typed constants are not their JSON values
+		{field: "level", openAPIType: "string"},
+		{field: "origin", openAPIType: "string"},
+		{field: "blob", anyValue: true},
+		{field: "raw", anyValue: true},
+		{field: "levels", openAPIType: "array", itemsType: "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			f := fields[tt.field]
+			if f.OpenAPIType != tt.openAPIType || f.ItemsType != tt.itemsType || f.IsAnyValue != tt.anyValue {
+				t.Errorf("got type=%q items=%q any=%v, want type=%q items=%q any=%v",
+					f.OpenAPIType, f.ItemsType, f.IsAnyValue, tt.openAPIType, tt.itemsType, tt.anyValue)
+			}
+			if f.IsUnresolvedStruct {
+				t.Error("marshaler types should not be reported as unresolved")
+			}
+			if len(f.Enum) > 0 {
+				t.Errorf("Enum = %v, want none", f.Enum)
+			}
+		})
+	}
+
+	// A MarshalJSON method with the wrong signature is not a json.Marshaler
+	if other := resolved.Schemas["Wrong"].Fields[0]; !other.IsUnresolvedStruct {
+		t.Error("NotMarshaler should be reported as an unresolved struct")
+	}
+
+	// Only the json.Marshaler without a @schema is reported
+	warnings := resolver.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "marshal.Blob implements json.Marshaler") {
+		t.Errorf("warnings = %v, want one for Blob", warnings)
+	}
+}
+
+// TestResolver_JSONConformance encodes real values with encoding/json and checks that
+// every property matches the type the resolver derived for its field
+func TestResolver_JSONConformance(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/jsonenc")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/jsonenc", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	data, err := json.Marshal(jsonenc.Sample)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var encoded map[string]any
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	fields := resolved.Schemas["Values"].Fields
+	if len(fields) != len(encoded) {
+		t.Fatalf("resolved %d fields, encoded %d properties", len(fields), len(encoded))
+	}
+
+	for _, f := range fields {
+		t.Run(f.Name, func(t *testing.T) {
+			value, ok := encoded[f.Name]
+			if !ok {
+				t.Fatalf("property %q not encoded", f.Name)
+			}
+			if f.IsAnyValue {
+				return
+			}
+			if err := conformsTo(value, f.OpenAPIType, f.Format); err != "" {
+				t.Errorf("%s (value %s)", err, data)
+			}
+			if f.OpenAPIType != "array" {
+				return
+			}
+
+			items := value.([]any)
+			if f.MinItems != nil && (len(items) < *f.MinItems || f.MaxItems == nil || len(items) > *f.MaxItems) {
+				t.Errorf("%d items outside minItems/maxItems", len(items))
+			}
+			for _, item := range items {
+				if err := conformsTo(item, f.ItemsType, ""); err != "" {
+					t.Errorf("item: %s", err)
+				}
+			}
+		})
+	}
+
+	// Fixed-size arrays have an exact length
+	for _, f := range fields {
+		if f.Name == "window" && (f.MinItems == nil || *f.MinItems != 3 || f.MaxItems == nil || *f.MaxItems != 3) {
+			t.Errorf("window minItems/maxItems = %v/%v, want 3", f.MinItems, f.MaxItems)
+		}
+	}
+
+	if warnings := resolver.Warnings(); len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none", warnings)
+	}
+}
+
+// conformsTo checks a decoded JSON value against an OpenAPI type and format
+// Returns a description of the mismatch, or "" if the value conforms
+func conformsTo(value any, openAPIType, format string) string {
+	switch openAPIType {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("got %T, want string", value)
+		}
+		if format == "byte" {
+			if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+				return fmt.Sprintf("%q is not base64: %v", s, err)
+			}
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Sprintf("got %v (%T), want integer", value, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Sprintf("got %T, want number", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("got %T, want boolean", value)
+		}
+	case "array":
+		if _, ok := value.([]any); !ok {
+			return fmt.Sprintf("got %T, want array", value)
+		}
+	case "object":
+		if _, ok := value.(map[string]any); !ok {
+			return fmt.Sprintf("got %T, want object", value)
+		}
+	default:
+		return fmt.Sprintf("unexpected type %q", openAPIType)
+	}
+	return ""
+}
+
+func TestParseJSONTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want jsonTag
+	}{
+		{tag: ``, want: jsonTag{}},
+		{tag: `json:"-"`, want: jsonTag{Skip: true}},
+		{tag: `json:"-,"`, want: jsonTag{Name: "-"}},
+		{tag: `json:"id,omitempty,string"`, want: jsonTag{Name: "id", OmitEmpty: true, String: true}},
+		{tag: `json:"at,omitzero,format:RFC3339"`, want: jsonTag{Name: "at", OmitZero: true, Format: "RFC3339"}},
+		{tag: `json:",inline"`, want: jsonTag{Inline: true}},
+		{tag: `json:",unknown"`, want: jsonTag{Unknown: true}},
+		{tag: `json:"name,case:ignore"`, want: jsonTag{Name: "name", Case: "ignore"}},
+		{tag: `json:"'a,b',format:'2006-01-02'"`, want: jsonTag{Name: "a,b", Format: "2006-01-02"}},
+		{tag: `json:"'it\\'s'"`, want: jsonTag{Name: "it's"}},
+		{tag: `xml:"name,omitempty"`, want: jsonTag{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := parseJSONTag(tt.tag); got != tt.want {
+				t.Errorf("parseJSONTag(%q) = %+v, want %+v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOmitsEmpty(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{tag: `json:"name"`, want: false},
+		{tag: `json:"name,omitempty"`, want: true},
+		{tag: `json:"name,omitzero"`, want: true},
+		{tag: `json:"'omitempty'"`, want: false},
+		{tag: `json:"name" xml:"name,omitempty"`, want: true},
+		{tag: `query:"limit,omitempty"`, want: true},
+		{tag: `json:"email" validate:"omitempty,email"`, want: true},
+		{tag: `json:"name" yaml:"omitempty"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := omitsEmpty(tt.tag); got != tt.want {
+				t.Errorf("omitsEmpty(%q) = %v, want %v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_JSONTagOptions(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/jsontags")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/jsontags", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	var names []string
+	fields := make(map[string]*ResolvedField)
+	for _, f := range resolved.Schemas["Document"].Fields {
+		names = append(names, f.Name)
+		fields[f.Name] = f
+	}
+
+	// Inlined structs are flattened in place; inlined maps and unknown fields are not properties
+	want := "title,summary,notes,a,b,created_by,created_at,name,published,expires,day,timeout,checksum,raw,bad"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("fields = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		field       string
+		required    bool
+		openAPIType string
+		format      string
+	}{
+		{field: "title", required: true, openAPIType: "string"},
+		{field: "summary", openAPIType: "string"},
+		{field: "notes", openAPIType: "string"},
+		{field: "created_at", openAPIType: "string", format: "date-time"},
+		{field: "published", required: true, openAPIType: "string", format: "date"},
+		{field: "expires", required: true, openAPIType: "number"},
+		{field: "day", required: true, openAPIType: "string", format: "date"},
+		{field: "timeout", required: true, openAPIType: "string", format: "duration"},
+		{field: "checksum", required: true, openAPIType: "string", format: "base64url"},
+		{field: "raw", required: true, openAPIType: "array"},
+		{field: "bad", required: true, openAPIType: "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			f := fields[tt.field]
+			if f == nil {
+				t.Fatalf("field %s not resolved", tt.field)
+			}
+			if f.Required != tt.required || f.OpenAPIType != tt.openAPIType || f.Format != tt.format {
+				t.Errorf("got required=%v type=%q format=%q, want required=%v type=%q format=%q",
+					f.Required, f.OpenAPIType, f.Format, tt.required, tt.openAPIType, tt.format)
+			}
+		})
+	}
+
+	if created := fields["created_by"]; !strings.HasSuffix(created.EmbeddedFrom, "jsontags.Audit") {
+		t.Errorf("created_by EmbeddedFrom = %q, want the inlined Audit type", created.EmbeddedFrom)
+	}
+
+	// The first inlined map or unknown field describes the undeclared members
+	for name, want := range map[string]string{"Document": "any", "Tagged": "string"} {
+		additional := resolved.Schemas[name].AdditionalProperties
+		if additional == nil {
+			t.Errorf("%s additional properties not set", name)
+		} else if got := descriptorString(additional); got != want {
+			t.Errorf("%s additional properties = %s, want %s", name, got, want)
+		}
+	}
+	if additional := resolved.Schemas["Inline"].AdditionalProperties; additional != nil {
+		t.Errorf("Inline additional properties = %s, want none", descriptorString(additional))
+	}
+
+	// Inline options also apply to anonymous structs
+	body := resolved.Schemas["Inline"].Fields[0]
+	var inlineNames []string
+	for _, f := range body.InlineFields {
+		inlineNames = append(inlineNames, f.Name)
+	}
+	if got := strings.Join(inlineNames, ","); got != "title,created_by,created_at" {
+		t.Errorf("inline fields = %s, want title,created_by,created_at", got)
+	}
+
+	warnings := resolver.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), `json format "upper"`) {
+		t.Errorf("warnings = %v, want one for the upper format", warnings)
+	}
+}
+
+// descriptorString renders a type descriptor in Go-like notation for comparisons
+func descriptorString(d *TypeDescriptor) string {
+	prefix := ""
+	if d.Nullable {
+		prefix = "*"
+	}
+	switch {
+	case d.OneOf != nil:
+		var alternatives []string
+		for _, alternative := range d.OneOf {
+			alternatives = append(alternatives, descriptorString(alternative))
+		}
+		return strings.Join(alternatives, " | ")
+	case d.Items != nil && d.Length != nil:
+		return fmt.Sprintf("%s[%d]%s", prefix, *d.Length, descriptorString(d.Items))
+	case d.Items != nil:
+		return prefix + "[]" + descriptorString(d.Items)
+	case d.MapValue != nil:
+		return prefix + "map[string]" + descriptorString(d.MapValue)
+	case d.InlineFields != nil:
+		var names []string
+		for _, f := range d.InlineFields {
+			names = append(names, f.Name)
+		}
+		return prefix + "{" + strings.Join(names, ",") + "}"
+	case d.IsAnyValue:
+		return prefix + "any"
+	case d.Ref != "":
+		return prefix + "$" + d.Ref
+	case d.OpenAPIType == "object":
+		return prefix + d.GoType[strings.LastIndex(d.GoType, ".")+1:]
+	}
+	return prefix + d.OpenAPIType
+}
+
+func TestResolver_TypeDescriptors(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/containers")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/containers", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	fields := make(map[string]*ResolvedField)
+	for _, f := range resolved.Schemas["Catalog"].Fields {
+		fields[f.Name] = f
+	}
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{field: "matrix", want: "[][]string"},
+		{field: "groups", want: "map[string][]User"},
+		{field: "rows", want: "[]map[string]*Item"},
+		{field: "owners", want: "*[]User"},
+		{field: "scores", want: "[]*integer"},
+		{field: "grid", want: "[][3]integer"},
+		{field: "values", want: "[]any"},
+		{field: "tree", want: "map[string]any"},
+		{field: "sections", want: "map[string][]{title}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			f := fields[tt.field]
+			if f == nil || f.Type == nil {
+				t.Fatalf("field %s has no type descriptor", tt.field)
+			}
+			if got := descriptorString(f.Type); got != tt.want {
+				t.Errorf("type = %s, want %s", got, tt.want)
+			}
+			if f.IsUnresolvedStruct {
+				t.Errorf("field %s reported as unresolved struct %s", tt.field, f.UnresolvedTypeName)
+			}
+		})
+	}
+
+	// The flattened fields still describe the top level
+	if matrix := fields["matrix"]; !matrix.IsArray || matrix.ItemsType != "array" {
+		t.Errorf("matrix IsArray=%v ItemsType=%q, want array of arrays", matrix.IsArray, matrix.ItemsType)
+	}
+
+	bodies := map[string]*ResolvedBody{}
+	for _, ep := range resolved.Endpoints {
+		if ep.Request != nil {
+			bodies[ep.Path+" request"] = ep.Request.Body
+		}
+		bodies[ep.Path+" response"] = ep.Responses["200"].Body
+	}
+
+	bodyTests := []struct {
+		body        string
+		want        string
+		elementType string
+	}{
+		{body: "/catalog response", want: "map[string][]$Item", elementType: "Item"},
+		{body: "/matrix request", want: "[][]$User", elementType: "User"},
+		{body: "/matrix response", want: "*[]string", elementType: "string"},
+		// Repeated @body and @body A | B are alternatives
+		{body: "/accounts request", want: "$User | []$Item"},
+		{body: "/accounts response", want: "$User | map[string]$Item | string"},
+	}
+
+	for _, tt := range bodyTests {
+		t.Run(tt.body, func(t *testing.T) {
+			body := bodies[tt.body]
+			if body == nil || body.Type == nil {
+				t.Fatalf("body %s has no type descriptor", tt.body)
+			}
+			if got := descriptorString(body.Type); got != tt.want {
+				t.Errorf("type = %s, want %s", got, tt.want)
+			}
+			if body.ElementType != tt.elementType {
+				t.Errorf("ElementType = %q, want %q", body.ElementType, tt.elementType)
+			}
+		})
+	}
+}
+
+// compositionString renders a composition as e.g. "oneOf[Card,BankTransfer] type{card=Card}"
+func compositionString(c *Composition) string {
+	if c == nil {
+		return ""
+	}
+	s := c.Kind + "[" + strings.Join(c.Schemas, ",") + "]"
+	if c.Discriminator != nil {
+		var mapping []string
+		for _, m := range c.Discriminator.Mapping {
+			mapping = append(mapping, m.Value+"="+m.Schema)
+		}
+		s += " " + c.Discriminator.PropertyName + "{" + strings.Join(mapping, ",") + "}"
+	}
+	return s
+}
+
+func TestResolver_Compositions(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/polymorphic")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/polymorphic", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// Interface schemas discover their implementations; values come from single-valued enums
+	payment := resolved.Schemas["PaymentMethod"]
+	if payment == nil || payment.Value == nil {
+		t.Fatal("PaymentMethod should resolve to a value schema")
+	}
+	want := "oneOf[BankTransfer,Card] type{bank_transfer=BankTransfer,card=Card}"
+	if got := compositionString(payment.Value.Composition); got != want {
+		t.Errorf("PaymentMethod composition = %q, want %q", got, want)
+	}
+
+	fields := make(map[string]*ResolvedField)
+	for _, f := range resolved.Schemas["Order"].Fields {
+		fields[f.GoName] = f
+	}
+
+	tests := []struct {
+		field string
+		items bool // composition of the array items
+		want  string
+	}{
+		// Fields of an interface @schema reference it
+		{field: "Payment", want: ""},
+		// Explicit mappings come first
+		{field: "Refund", want: "oneOf[Card,BankTransfer] type{card=Card,bank_transfer=BankTransfer}"},
+		{field: "Fallback", want: "anyOf[Card,Wallet]"},
+		{field: "History", items: true, want: "oneOf[Card,Wallet]"},
+		// Unmapped alternatives default to their schema name
+		{field: "Events", items: true, want: "oneOf[Cancelled,Created] kind{Cancelled=Cancelled,Created=Created}"},
+	}
+
+	// Fields of anonymous structs and inline bodies take their compositions too
+	if billing := fields["Billing"]; billing == nil || len(billing.InlineFields) != 1 {
+		t.Errorf("Billing should have one inline field")
+	} else if got, want := compositionString(billing.InlineFields[0].Composition), "oneOf[Card,BankTransfer]"; got != want {
+		t.Errorf("Billing.Method composition = %q, want %q", got, want)
+	}
+	if len(resolved.Endpoints) != 1 || resolved.Endpoints[0].InlineRequest == nil {
+		t.Fatal("CreateRefund should have an inline request")
+	}
+	if body := resolved.Endpoints[0].InlineRequest; len(body.Fields) != 1 {
+		t.Errorf("inline request fields = %d, want 1", len(body.Fields))
+	} else if got, want := compositionString(body.Fields[0].Composition), "oneOf[Card,Wallet]"; got != want {
+		t.Errorf("inline request composition = %q, want %q", got, want)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field := fields[tt.field]
+			if field == nil {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			c := field.Composition
+			if tt.items {
+				if field.Composition != nil {
+					t.Errorf("composition of %s should be set on its items", tt.field)
+				}
+				c = field.Type.Items.Composition
+			}
+			if got := compositionString(c); got != tt.want {
+				t.Errorf("composition = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if warnings := resolver.Warnings(); len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestResolver_XMLTags(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/xmltags")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/xmltags", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	schemaTests := []struct {
+		schema string
+		want   *XMLInfo
+	}{
+		{schema: "Feed", want: &XMLInfo{Name: "feed", Namespace: "http://www.w3.org/2005/Atom"}},
+		// XMLName without a tag keeps the default element name
+		{schema: "Entry", want: nil},
+		{schema: "Item", want: nil},
+	}
+	for _, tt := range schemaTests {
+		t.Run(tt.schema, func(t *testing.T) {
+			if got := resolved.Schemas[tt.schema].XML; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("XML = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	fields := make(map[string]*ResolvedField)
+	for _, f := range resolved.Schemas["Feed"].Fields {
+		fields[f.GoName] = f
+	}
+	if _, ok := fields["XMLName"]; ok {
+		t.Error("XMLName should not be a property")
+	}
+	for _, f := range resolved.Schemas["Entry"].Fields {
+		if f.GoName == "XMLName" {
+			t.Error("XMLName should not be a property of Entry")
+		}
+	}
+
+	tests := []struct {
+		field string
+		want  *XMLInfo
+	}{
+		{field: "ID", want: &XMLInfo{NodeType: "attribute"}},
+		{field: "Title", want: &XMLInfo{Prefix: "atom"}},
+		{field: "Lang", want: &XMLInfo{Namespace: "http://www.w3.org/XML/1998/namespace", NodeType: "attribute"}},
+		{field: "Note", want: &XMLInfo{NodeType: "text"}},
+		{field: "Raw", want: &XMLInfo{NodeType: "none"}},
+		{field: "Items", want: &XMLInfo{Wrapped: true, ItemsName: "item"}},
+		{field: "Tags", want: &XMLInfo{ItemsName: "tag"}},
+		{field: "Links", want: nil},
+		{field: "City", want: nil},
+		{field: "Hidden", want: nil},
+		{field: "Comment", want: nil},
+		{field: "Plain", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field := fields[tt.field]
+			if field == nil {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			if !reflect.DeepEqual(field.XML, tt.want) {
+				t.Errorf("XML = %+v, want %+v", field.XML, tt.want)
+			}
+		})
+	}
+
+	// Paths other than wrapped arrays cannot be expressed
+	warnings := resolver.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), `xml path "address>city"`) {
+		t.Errorf("warnings = %v, want one for address>city", warnings)
+	}
+}
+
+func TestResolver_FileUploads(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/uploads")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/uploads", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	fields := make(map[string]*ResolvedField)
+	for _, f := range resolved.Schemas["UploadForm"].Fields {
+		fields[f.GoName] = f
+	}
+
+	tests := []struct {
+		field        string
+		wantType     string
+		wantFormat   string
+		wantItems    string
+		wantEncoding *PartEncoding
+	}{
+		{
+			field:        "Avatar",
+			wantType:     "string",
+			wantFormat:   "binary",
+			wantEncoding: &PartEncoding{ContentTypes: []string{"image/png", "image/jpeg"}},
+		},
+		{field: "Attachments", wantType: "array", wantItems: "binary"},
+		// []byte stays a base64 string, its part is sent as raw bytes by the generator
+		{field: "Thumbnail", wantType: "string", wantFormat: "byte"},
+		{
+			field:        "Metadata",
+			wantType:     "string",
+			wantEncoding: &PartEncoding{ContentTypes: []string{"application/json"}},
+		},
+		{
+			field:        "Document",
+			wantType:     "string",
+			wantFormat:   "binary",
+			wantEncoding: &PartEncoding{HeaderParams: []string{"PartHeaders"}, Headers: []*ResolvedParameter{resolved.Parameters["PartHeaders"]}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field := fields[tt.field]
+			if field == nil {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			if field.OpenAPIType != tt.wantType || field.Format != tt.wantFormat {
+				t.Errorf("type = %s/%s, want %s/%s", field.OpenAPIType, field.Format, tt.wantType, tt.wantFormat)
+			}
+			if tt.wantItems != "" && (field.Type == nil || field.Type.Items == nil || field.Type.Items.Format != tt.wantItems) {
+				t.Errorf("Type = %+v, want items with format %s", field.Type, tt.wantItems)
+			}
+			// Files are absent parts, never null
+			if field.Nullable || (field.Type != nil && field.Type.Items != nil && field.Type.Items.Nullable) {
+				t.Error("file fields should not be nullable")
+			}
+			if !reflect.DeepEqual(field.Encoding, tt.wantEncoding) {
+				t.Errorf("Encoding = %+v, want %+v", field.Encoding, tt.wantEncoding)
+			}
+		})
+	}
+}
+
+func TestResolver_FileDownloads(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/uploads")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/uploads", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	endpoints := make(map[string]*ResolvedEndpoint)
+	for _, e := range resolved.Endpoints {
+		endpoints[e.Path] = e
+	}
+
+	// @contentType binary without @body is a raw file
+	raw := endpoints["/uploads/raw"]
+	if raw == nil || raw.Request == nil {
+		t.Fatal("PUT /uploads/raw should have a request body")
+	}
+	if body := raw.Request.Body; body == nil || body.Type.Format != "binary" {
+		t.Errorf("request body = %+v, want a binary body", body)
+	}
+
+	tests := []struct {
+		path            string
+		wantContentType string
+		wantFormat      string
+		wantDisposition string
+	}{
+		{path: "/reports/latest", wantContentType: "application/pdf", wantFormat: "binary", wantDisposition: `attachment; filename="report.pdf"`},
+		// Files named by Go type are binary
+		{path: "/exports/latest", wantContentType: "application/zip", wantFormat: "binary", wantDisposition: "attachment"},
+		// []byte is only raw outside JSON, which the generator decides per media type
+		{path: "/thumbnails/latest", wantContentType: "image/png", wantFormat: "byte"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			endpoint := endpoints[tt.path]
+			if endpoint == nil {
+				t.Fatalf("endpoint %s not found", tt.path)
+			}
+			response := endpoint.Responses["200"]
+			if response.ContentType != tt.wantContentType {
+				t.Errorf("ContentType = %s, want %s", response.ContentType, tt.wantContentType)
+			}
+			if desc := response.Body.Type; desc.OpenAPIType != "string" || desc.Format != tt.wantFormat || desc.Ref != "" {
+				t.Errorf("body type = %+v, want string/%s", desc, tt.wantFormat)
+			}
+
+			if tt.wantDisposition == "" {
+				if len(response.Headers) != 0 {
+					t.Errorf("Headers = %v, want none", response.Headers)
+				}
+				return
+			}
+			if len(response.Headers) != 1 || response.Headers[0].Fields[0].Name != "Content-Disposition" {
+				t.Fatalf("Headers = %v, want Content-Disposition", response.Headers)
+			}
+			if got := response.Headers[0].Fields[0].Example; got != tt.wantDisposition {
+				t.Errorf("Content-Disposition example = %s, want %s", got, tt.wantDisposition)
+			}
+		})
+	}
+
+	// Standard library types resolve without being imported by the package
+	pkgPath := resolved.Schemas["Upload"].PkgPath
+	typeTests := []struct {
+		body       string
+		wantType   string
+		wantFormat string
+		wantRef    string
+	}{
+		{body: "*os.File", wantType: "string", wantFormat: "binary"},
+		{body: "multipart.File", wantType: "string", wantFormat: "binary"},
+		{body: "time.Time", wantType: "string", wantFormat: "date-time"},
+		{body: "Upload", wantType: "object", wantRef: "Upload"},
+	}
+	for _, tt := range typeTests {
+		t.Run(tt.body, func(t *testing.T) {
+			desc := resolver.describeBodyType(tt.body, pkgPath, resolved.Schemas)
+			if desc.OpenAPIType != tt.wantType || desc.Format != tt.wantFormat || desc.Ref != tt.wantRef || desc.Nullable {
+				t.Errorf("describeBodyType(%s) = %+v, want %s/%s ref %q", tt.body, desc, tt.wantType, tt.wantFormat, tt.wantRef)
+			}
+		})
+	}
+}
+
+func TestResolver_Streams(t *testing.T) {
+	p := parser.NewParser("../parser/testdata/streams")
+	parsed, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+
+	resolver, err := NewResolver("../parser/testdata/streams", p.AllComments()...)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	resolved, err := resolver.Resolve(parsed)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	endpoints := make(map[string]*ResolvedEndpoint)
+	for _, e := range resolved.Endpoints {
+		endpoints[e.Path] = e
+	}
+
+	tests := []struct {
+		path       string
+		wantTypes  []string
+		wantStream []string
+		wantBody   string
+		wantEvents []string
+	}{
+		{path: "/messages/stream", wantTypes: []string{"text/event-stream"}, wantStream: []string{"sse"}, wantBody: "Message"},
+		{path: "/jobs/events", wantTypes: []string{"text/event-stream"}, wantStream: []string{"sse"}, wantEvents: []string{"progress:Progress", "done:Done"}},
+		// @stream adds its media type to the listed content types
+		{path: "/logs", wantTypes: []string{"application/json", "application/x-ndjson"}, wantStream: []string{"", "ndjson"}, wantBody: "LogLine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			endpoint := endpoints[tt.path]
+			if endpoint == nil {
+				t.Fatalf("endpoint %s not found", tt.path)
+			}
+			mediaTypes := endpoint.Responses["200"].MediaTypes()
+			if len(mediaTypes) != len(tt.wantTypes) {
+				t.Fatalf("media types = %d, want %d", len(mediaTypes), len(tt.wantTypes))
+			}
+			for i, mediaType := range mediaTypes {
+				if mediaType.ContentType != tt.wantTypes[i] || mediaType.Stream != tt.wantStream[i] {
+					t.Errorf("media type %d = %s (stream %q), want %s (stream %q)", i, mediaType.ContentType, mediaType.Stream, tt.wantTypes[i], tt.wantStream[i])
+				}
+				if tt.wantBody != "" && (mediaType.Body == nil || mediaType.Body.Schema != tt.wantBody) {
+					t.Errorf("media type %d body = %+v, want %s", i, mediaType.Body, tt.wantBody)
+				}
+			}
+
+			sse := mediaTypes[0]
+			var events []string
+			for _, event := range sse.Events {
+				events = append(events, event.Name+":"+event.Body.Schema)
+			}
+			if strings.Join(events, ",") != strings.Join(tt.wantEvents, ",") {
+				t.Errorf("events = %v, want %v", events, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package resolver
+
+import (
+	"slices"
+
+	"github.com/wontaeyang/go-specgen/pkg/parser"
+)
+
+// streamFormats maps sequential media types to their stream format
+// The body of a sequential media type describes each item of the stream.
+var streamFormats = map[string]string{
+	"text/event-stream":    "sse",
+	"application/x-ndjson": "ndjson",
+	"application/jsonl":    "jsonl",
+	"application/json-seq": "json-seq",
+}
+
+// streamContentTypes returns the content types of a response, adding the media type of its @stream
+// (e.g. @stream sse adds text/event-stream) when not listed by @contentType
+func streamContentTypes(response *parser.Response) []string {
+	if response.Stream == "" {
+		return response.ContentTypes
+	}
+
+	streamType := parser.ExpandContentType(response.Stream)
+	if slices.Contains(response.ContentTypes, streamType) {
+		return response.ContentTypes
+	}
+	return append(slices.Clone(response.ContentTypes), streamType)
+}
+
+// resolveStreams marks the sequential media types of a response as streams, and gives
+// Server-Sent Event streams their named events
+// Returns the resolved events.
+func (r *Resolver) resolveStreams(content []*ResolvedContent, events []*parser.Event, pkgPath string, schemas map[string]*ResolvedSchema) []*ResolvedEvent {
+	var resolvedEvents []*ResolvedEvent
+	for _, event := range events {
+		resolved := &ResolvedEvent{Name: event.Name}
+		if event.Schema != "" {
+			resolved.Body = r.resolveBody(&parser.Body{Schema: event.Schema}, pkgPath, schemas)
+		}
+		resolvedEvents = append(resolvedEvents, resolved)
+	}
+
+	for _, mediaType := range content {
+		format, ok := streamFormats[mediaType.ContentType]
+		if !ok {
+			continue
+		}
+		mediaType.Stream = format
+		if format == "sse" {
+			mediaType.Events = resolvedEvents
+		}
+	}
+	return resolvedEvents
+}
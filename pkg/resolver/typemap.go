@@ -0,0 +1,126 @@
+package resolver
+
+import (
+	"fmt"
+	"go/types"
+	"path"
+	"strings"
+
+	"github.com/wontaeyang/go-specgen/pkg/parser"
+)
+
+// TypeMapping maps a Go type to an OpenAPI schema
+// Either Type (with optional Format and Pattern) or a full Schema is set
+type TypeMapping struct {
+	Type    string         `yaml:"type" json:"type"`
+	Format  string         `yaml:"format" json:"format"`
+	Pattern string         `yaml:"pattern" json:"pattern"`
+	Schema  map[string]any `yaml:"schema" json:"schema"`
+}
+
+// AddTypeMapping registers a mapping for a Go type, replacing any existing mapping
+// goType is package-qualified: "github.com/google/uuid.UUID" or, matched by package name, "uuid.UUID".
+// Mappings take precedence over the built-in standard library types (time.Time, url.URL, ...).
+// They must be added before Resolve is called.
+func (r *Resolver) AddTypeMapping(goType string, mapping *TypeMapping) error {
+	dot := strings.LastIndex(goType, ".")
+	if dot <= 0 || dot == len(goType)-1 {
+		return fmt.Errorf("type mapping %q: Go type must be package-qualified (e.g. github.com/google/uuid.UUID)", goType)
+	}
+	if mapping == nil || (mapping.Type == "" && mapping.Schema == nil) {
+		return fmt.Errorf("type mapping %q: a type or schema is required", goType)
+	}
+	if mapping.Type != "" && mapping.Schema != nil {
+		return fmt.Errorf("type mapping %q: type and schema are mutually exclusive", goType)
+	}
+
+	r.typeMappings[goType] = mapping
+	return nil
+}
+
+// addAnnotationTypeMappings registers @typeMapping annotations of the @api block
+// Mappings already registered (e.g. from a config file) take precedence
+func (r *Resolver) addAnnotationTypeMappings(mappings []*parser.TypeMapping) error {
+	for _, m := range mappings {
+		if _, exists := r.typeMappings[m.GoType]; exists {
+			continue
+		}
+		if err := r.AddTypeMapping(m.GoType, &TypeMapping{Type: m.Type, Format: m.Format}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupTypeMapping returns the mapping of a named type: a registered mapping by import path,
+// then by package name, then a built-in standard library mapping. Returns nil if the type is not mapped.
+func (r *Resolver) lookupTypeMapping(obj *types.TypeName) *TypeMapping {
+	if obj.Pkg() == nil {
+		return nil
+	}
+
+	if mapping, ok := r.typeMappings[obj.Pkg().Path()+"."+obj.Name()]; ok {
+		return mapping
+	}
+	if mapping, ok := r.typeMappings[obj.Pkg().Name()+"."+obj.Name()]; ok {
+		return mapping
+	}
+
+	if special := resolveSpecialType(obj.Pkg().Path(), obj.Name()); special != nil {
+		return &TypeMapping{Type: special.openAPIType, Format: special.format}
+	}
+	return nil
+}
+
+// lookupAnnotationTypeMapping returns the mapping of a package-qualified type named in an annotation
+// (e.g. "@body io.Reader"): the package name is resolved through the imports of pkgPath, then
+// registered mappings and built-in standard library types are matched by package name.
+// Returns nil if the type is not mapped.
+func (r *Resolver) lookupAnnotationTypeMapping(typeName, pkgPath string) *TypeMapping {
+	idx := strings.LastIndex(typeName, ".")
+	if idx <= 0 {
+		return nil
+	}
+	qualifier, name := typeName[:idx], typeName[idx+1:]
+
+	if pkg := r.packageFor(pkgPath); pkg != nil {
+		for _, imp := range pkg.Imports {
+			if imp.Name != qualifier || imp.Types == nil {
+				continue
+			}
+			if obj, ok := imp.Types.Scope().Lookup(name).(*types.TypeName); ok {
+				return r.lookupTypeMapping(obj)
+			}
+		}
+	}
+
+	if mapping, ok := r.typeMappings[typeName]; ok {
+		return mapping
+	}
+	for specialPath, pkgTypes := range specialTypes {
+		if path.Base(specialPath) != qualifier {
+			continue
+		}
+		if special, ok := pkgTypes[name]; ok {
+			return &TypeMapping{Type: special.openAPIType, Format: special.format}
+		}
+	}
+	return nil
+}
+
+// typeInfo converts a mapping to resolved type information
+func (m *TypeMapping) typeInfo() *TypeInfo {
+	info := &TypeInfo{
+		OpenAPIType: m.Type,
+		Format:      m.Format,
+		Pattern:     m.Pattern,
+	}
+	if m.Schema != nil {
+		info.Schema = m.Schema
+		info.OpenAPIType = "object"
+		if t, ok := m.Schema["type"].(string); ok {
+			info.OpenAPIType = t
+		}
+	}
+	return info
+}
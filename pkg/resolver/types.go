@@ -1,5 +1,10 @@
 package resolver
 
+import (
+	"go/token"
+	"go/types"
+)
+
 // ResolvedPackage contains the fully resolved parsed package with type information
 type ResolvedPackage struct {
 	// Original parsed package
@@ -16,6 +21,8 @@ type ResolvedAPI struct {
 	Version            string
 	Description        string
 	TermsOfService     string
+	Self               string         // URI of the document ($self)
+	Position           token.Position // Position of the package comment holding @api
 	Contact            *Contact
 	License            *License
 	Servers            []*Server
@@ -23,6 +30,8 @@ type ResolvedAPI struct {
 	Security           [][]*SecurityRequirement
 	Tags               []*Tag
 	DefaultContentType string
+	Produces           []string // Default response content types
+	Consumes           []string // Default request content types
 }
 
 // Contact information
@@ -65,16 +74,33 @@ type SecurityRequirement struct {
 type Tag struct {
 	Name        string
 	Description string
+	Parent      string // Name of the parent tag
+	Kind        string // Kind of tag, e.g. nav, badge or audience
 }
 
 // ResolvedSchema contains a schema with resolved type information
 type ResolvedSchema struct {
-	Name        string
+	Name        string // Component name, package-qualified when the type name is ambiguous
 	GoTypeName  string
+	PkgPath     string         // Import path of the package declaring the type
+	Position    token.Position // Position of the @schema comment, for source ordering
 	Description string
 	Deprecated  bool
 	Fields      []*ResolvedField
 
+	// Value describes a named non-struct type (e.g. type Currency string); nil for structs
+	Value *ResolvedField
+
+	// AllOf composes embedded @schema types with allOf instead of flattening their fields
+	AllOf bool
+
+	// AdditionalProperties describes the values of undeclared members, collected by an inlined
+	// map or unknown field (encoding/json/v2); nil if the struct has none
+	AdditionalProperties *TypeDescriptor
+
+	// XML is the XML element of the schema, named by its XMLName field; nil if unnamed
+	XML *XMLInfo
+
 	// IsGeneric indicates this is a generic struct (has type parameters)
 	// Generic structs are templates and should not be emitted to components
 	IsGeneric bool
@@ -97,6 +123,7 @@ type ResolvedParameter struct {
 	Name       string
 	Type       string // "path", "query", "header", "cookie"
 	GoTypeName string
+	Position   token.Position // Position of the parameter struct comment
 	Fields     []*ResolvedField
 }
 
@@ -109,20 +136,24 @@ type ResolvedField struct {
 	Required    bool
 	Nullable    bool
 	Deprecated  bool
+	Position    token.Position // Position of the Go struct field, for diagnostics
 
 	// Type information (resolved from Go type)
 	GoType      string // Original Go type string
 	OpenAPIType string // "string", "integer", "number", "boolean", "array", "object"
 	Format      string // "int32", "int64", "float", "double", "byte", "binary", "date", "date-time", "password", "email", "uuid", etc.
 
+	// Type is the full shape of the Go type, including nested arrays and maps (nil for parameters)
+	// Schemas of arrays and maps are generated from it. The fields below describe its top level
+	// (and first level of items) only, for parameters, validation and enums of array items.
+	Type *TypeDescriptor
+
 	// Array information
-	IsArray           bool
-	ItemsType         string           // For arrays, the OpenAPI type of items
-	ItemsInlineFields []*ResolvedField // For arrays of anonymous structs, the resolved fields
+	IsArray   bool
+	ItemsType string // For arrays, the OpenAPI type of items
 
 	// Map information
-	IsMap                bool
-	MapValueInlineFields []*ResolvedField // For maps of anonymous structs, the resolved value fields
+	IsMap bool
 
 	// Any value type (any/interface{})
 	IsAnyValue bool // True if field accepts any JSON value
@@ -134,6 +165,27 @@ type ResolvedField struct {
 	// Anonymous struct support
 	InlineFields []*ResolvedField // For anonymous structs, the resolved fields to inline
 
+	// MappedSchema is the full schema of a type mapped by configuration (used verbatim)
+	MappedSchema map[string]any
+
+	// Composition describes a polymorphic value (@oneOf, @anyOf or an interface @schema)
+	// For arrays and maps, it is set on the innermost element of Type instead
+	Composition *Composition
+
+	// XML is the XML representation of the field from its xml tag; nil for the defaults
+	XML *XMLInfo
+
+	// Encoding is the encoding of the field's part in multipart and form bodies; nil for the defaults
+	Encoding *PartEncoding
+
+	// Enum metadata derived from typed Go constants (parallel to Enum, empty for @enum)
+	EnumVarNames     []string // Constant names, emitted as x-enum-varnames
+	EnumDescriptions []string // Constant doc comments, emitted as x-enum-descriptions
+
+	// EmbeddedFrom is the package-qualified Go type of the embedded struct the field was promoted from
+	// (e.g. "github.com/acme/models.User"), empty for fields declared directly
+	EmbeddedFrom string
+
 	// Validation constraints
 	Enum        []string
 	Default     string
@@ -148,11 +200,29 @@ type ResolvedField struct {
 	Maximum     *float64
 }
 
+// XMLInfo is the XML representation of a schema or field (the OpenAPI xml object)
+type XMLInfo struct {
+	Name      string // Element or attribute name, "" for the property name
+	Namespace string
+	Prefix    string
+	NodeType  string // "attribute", "text" (chardata), "cdata" or "none" (innerxml); "" for elements
+	Wrapped   bool   // Array items are wrapped in an element named Name (xml:"items>item")
+	ItemsName string // Element name of array items, "" for the property name
+}
+
+// PartEncoding is the encoding of a property in multipart and form bodies (the OpenAPI encoding object)
+type PartEncoding struct {
+	ContentTypes []string             // Content types of the part (@contentType)
+	HeaderParams []string             // Header struct references (@header)
+	Headers      []*ResolvedParameter // Resolved header structs, in HeaderParams order
+}
+
 // ResolvedEndpoint contains an endpoint with resolved types
 type ResolvedEndpoint struct {
 	FuncName     string
 	Method       string
 	Path         string
+	Position     token.Position // Position of the @endpoint comment, for source ordering
 	OperationID  string
 	Summary      string
 	Description  string
@@ -182,32 +252,51 @@ type ResolvedInlineParams struct {
 
 // ResolvedInlineBody contains resolved inline body fields with optional binding
 type ResolvedInlineBody struct {
-	ContentType string
-	Fields      []*ResolvedField
-	Bind        *ResolvedBindTarget
-	Headers     []*ResolvedParameter // Response headers (for inline responses)
-	Description string               // Response description
+	ContentType  string   // First of ContentTypes
+	ContentTypes []string // Content types sharing the inline schema
+	Fields       []*ResolvedField
+	Bind         *ResolvedBindTarget
+	Headers      []*ResolvedParameter // Response headers (for inline responses)
+	Description  string               // Response description
 }
 
 // ResolvedRequestBody contains a request body with resolved schema
 type ResolvedRequestBody struct {
-	ContentType string
+	ContentType string // Content type of the first media type
 	Body        *ResolvedBody
 	Required    bool
+	Content     []*ResolvedContent // Every media type in order, starting with ContentType
 }
 
 // ResolvedResponse contains a response with resolved schema
 type ResolvedResponse struct {
 	StatusCode  string
 	Description string
-	ContentType string
+	ContentType string // Content type of the first media type
 	Body        *ResolvedBody
 	Headers     []*ResolvedParameter
+	Content     []*ResolvedContent // Every media type in order, starting with ContentType
+	Stream      string             // Stream format given by @stream, "" for other responses
+	Events      []*ResolvedEvent   // Named Server-Sent Events given by @event
+}
+
+// ResolvedContent is a media type of a request body or response
+type ResolvedContent struct {
+	ContentType string
+	Body        *ResolvedBody    // nil for a media type without schema; the item schema of streams
+	Stream      string           // "sse", "ndjson", "jsonl" or "json-seq" for sequential media types
+	Events      []*ResolvedEvent // Named Server-Sent Events (sse streams only)
+}
+
+// ResolvedEvent is a named Server-Sent Event and the schema of its data
+type ResolvedEvent struct {
+	Name string
+	Body *ResolvedBody // nil if the event has no data schema
 }
 
 // ResolvedBody contains the resolved body with optional binding
 type ResolvedBody struct {
-	// Schema is the schema name being referenced (e.g., "User", "[]User", "map[string]User")
+	// Schema is the schema name being referenced (e.g., "User", "[]User", "map[string]User", "User | Organization")
 	Schema string
 
 	// Bind contains the resolved wrapper binding
@@ -220,8 +309,60 @@ type ResolvedBody struct {
 	// IsMap indicates Schema is a map type (e.g., map[string]User)
 	IsMap bool
 
-	// ElementType is the element type for arrays/maps (e.g., "User")
+	// ElementType is the innermost element type for arrays/maps (e.g., "User" for [][]User), empty for alternatives
 	ElementType string
+
+	// Type is the full shape of the body type (e.g. map[string][]User, or the alternatives of User | Organization)
+	Type *TypeDescriptor
+}
+
+// TypeDescriptor describes the JSON shape of a Go type as a tree
+// Arrays and maps hold the descriptor of their elements, so nested containers such as
+// [][]string, map[string][]User or []map[string]*Item keep their full shape.
+type TypeDescriptor struct {
+	GoType      string // Go type of this node (e.g. "github.com/acme/models.User")
+	Ref         string // Component name of the referenced @schema, when resolved from an annotation
+	OpenAPIType string // "string", "integer", "number", "boolean", "array", "object" ("" for any value)
+	Format      string
+	Pattern     string
+	Nullable    bool // Pointer types
+	IsAnyValue  bool // Any JSON value (any, json.RawMessage, json.Marshaler)
+	Length      *int // Length of a fixed-size array ([N]T)
+
+	Items        *TypeDescriptor  // Element type of arrays
+	MapValue     *TypeDescriptor  // Value type of maps (additionalProperties)
+	InlineFields []*ResolvedField // Fields of anonymous structs
+	MappedSchema map[string]any   // Full schema of a type mapped by configuration
+	Composition  *Composition     // Alternatives of a polymorphic element (e.g. []PaymentMethod with @oneOf)
+
+	// OneOf holds the alternatives of a body matching one of several types (@body User | Organization)
+	OneOf []*TypeDescriptor
+}
+
+// Composition describes a polymorphic value matching one (oneOf) or any (anyOf) of several schemas
+type Composition struct {
+	Kind          string   // "oneOf" or "anyOf"
+	Schemas       []string // Component names of the alternatives
+	Discriminator *Discriminator
+
+	// pkgPath is the package the schema names were written in
+	pkgPath string
+
+	// iface is the interface whose implementing @schema types are the alternatives
+	// when no schemas are listed (an interface @schema without @oneOf or @anyOf)
+	iface *types.Interface
+}
+
+// Discriminator names the property that selects the schema of a polymorphic value
+type Discriminator struct {
+	PropertyName string
+	Mapping      []*DiscriminatorMapping // One entry per alternative, in order
+}
+
+// DiscriminatorMapping maps a discriminator value to a component name
+type DiscriminatorMapping struct {
+	Value  string
+	Schema string
 }
 
 // ResolvedBindTarget represents a resolved @bind Wrapper.Field annotation
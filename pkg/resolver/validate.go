@@ -0,0 +1,282 @@
+package resolver
+
+import (
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/wontaeyang/go-specgen/pkg/diagnostic"
+)
+
+// ValidationTags are the struct tags holding go-playground/validator rules, in the order they are applied.
+// "binding" is the tag used by Gin for the same rules.
+var ValidationTags = []string{"validate", "binding"}
+
+// validateFormats maps validator rules to OpenAPI formats
+var validateFormats = map[string]string{
+	"email":        "email",
+	"url":          "uri",
+	"uri":          "uri",
+	"http_url":     "uri",
+	"uuid":         "uuid",
+	"uuid3":        "uuid",
+	"uuid4":        "uuid",
+	"uuid5":        "uuid",
+	"uuid_rfc4122": "uuid",
+	"ipv4":         "ipv4",
+	"ipv6":         "ipv6",
+	"hostname":     "hostname",
+	"base64":       "byte",
+}
+
+// validatePatterns maps validator rules to equivalent regular expressions
+var validatePatterns = map[string]string{
+	"alpha":    "^[a-zA-Z]+$",
+	"alphanum": "^[a-zA-Z0-9]+$",
+	"numeric":  "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":   "^[0-9]+$",
+}
+
+// validateDateTimeFormats maps datetime=layout rules to OpenAPI formats
+var validateDateTimeFormats = map[string]string{
+	"2006-01-02":                "date",
+	"2006-01-02T15:04:05Z07:00": "date-time",
+}
+
+// applyValidateTags translates go-playground/validator rules from the validate and binding tags
+// into field constraints. It runs before @field annotations are applied, so annotations override
+// the translated values. Rules without an OpenAPI equivalent are reported as warnings.
+func (r *Resolver) applyValidateTags(field *ResolvedField, tag string, pos token.Pos) {
+	st := reflect.StructTag(tag)
+	for _, key := range ValidationTags {
+		rules := st.Get(key)
+		if rules == "" || rules == "-" {
+			continue
+		}
+
+		// Rules after dive apply to the elements of a slice
+		dive := false
+		for _, rule := range strings.Split(rules, ",") {
+			rule = strings.TrimSpace(rule)
+			if rule == "" {
+				continue
+			}
+			if rule == "dive" {
+				dive = true
+				continue
+			}
+
+			var ok bool
+			if dive {
+				ok = applyElementRule(field, rule)
+			} else {
+				ok = applyValidateRule(field, rule)
+			}
+			if !ok {
+				r.warnf(pos, r.subpath(field.GoName), "unsupported-validate-rule", "%s rule %q has no OpenAPI equivalent", key, rule)
+			}
+		}
+	}
+}
+
+// applyValidateRule applies a single validator rule to a field
+// Returns false if the rule cannot be translated
+func applyValidateRule(field *ResolvedField, rule string) bool {
+	// Alternatives (e.g. "email|url") cannot be expressed as constraints
+	if strings.Contains(rule, "|") {
+		return false
+	}
+
+	name, param, _ := strings.Cut(rule, "=")
+
+	switch name {
+	case "omitempty", "omitnil":
+		return true
+	case "required":
+		field.Required = true
+		return true
+	case "min", "gte":
+		return applyBound(field, param, true, 0)
+	case "max", "lte":
+		return applyBound(field, param, false, 0)
+	case "gt":
+		return applyBound(field, param, true, 1)
+	case "lt":
+		return applyBound(field, param, false, 1)
+	case "len":
+		return applyBound(field, param, true, 0) && applyBound(field, param, false, 0)
+	case "oneof":
+		return applyOneOf(field, param, field.OpenAPIType)
+	case "unique":
+		if field.OpenAPIType != "array" {
+			return false
+		}
+		field.UniqueItems = true
+		return true
+	}
+
+	// Formats and patterns only describe strings
+	if field.OpenAPIType != "string" {
+		return false
+	}
+	if format, ok := validateDateTimeFormats[param]; ok && name == "datetime" {
+		field.Format = format
+		return true
+	}
+	if format, ok := validateFormats[name]; ok && param == "" {
+		field.Format = format
+		return true
+	}
+	if pattern, ok := validatePatterns[name]; ok && param == "" {
+		field.Pattern = pattern
+		return true
+	}
+
+	return false
+}
+
+// applyElementRule applies a validator rule following dive to the items of an array field
+// Only enums can be expressed on items; other element rules are not translated
+func applyElementRule(field *ResolvedField, rule string) bool {
+	name, param, _ := strings.Cut(rule, "=")
+	switch name {
+	case "omitempty", "omitnil", "required":
+		return true
+	case "oneof":
+		return field.IsArray && applyOneOf(field, param, field.ItemsType)
+	}
+	return false
+}
+
+// applyBound applies a min/max style rule: a length for strings, an item count for arrays
+// and a value for numbers. exclusive is 1 for gt/lt, which is only exact for lengths and integers.
+func applyBound(field *ResolvedField, param string, lower bool, exclusive int) bool {
+	switch field.OpenAPIType {
+	case "string", "array":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return false
+		}
+		if lower {
+			n += exclusive
+		} else {
+			n -= exclusive
+		}
+		if field.OpenAPIType == "string" {
+			if lower {
+				field.MinLength = &n
+			} else {
+				field.MaxLength = &n
+			}
+		} else {
+			if lower {
+				field.MinItems = &n
+			} else {
+				field.MaxItems = &n
+			}
+		}
+		return true
+	case "integer", "number":
+		if exclusive != 0 && field.OpenAPIType == "number" {
+			return false
+		}
+		v, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false
+		}
+		if lower {
+			v += float64(exclusive)
+			field.Minimum = &v
+		} else {
+			v -= float64(exclusive)
+			field.Maximum = &v
+		}
+		return true
+	}
+	return false
+}
+
+// applyOneOf sets the enum of a field from a oneof rule (space-separated, values may be single-quoted)
+func applyOneOf(field *ResolvedField, param, openAPIType string) bool {
+	if openAPIType != "string" && openAPIType != "integer" {
+		return false
+	}
+
+	var values []string
+	for len(param) > 0 {
+		param = strings.TrimLeft(param, " ")
+		if param == "" {
+			break
+		}
+		if param[0] == '\'' {
+			end := strings.IndexByte(param[1:], '\'')
+			if end < 0 {
+				return false
+			}
+			values = append(values, param[1:end+1])
+			param = param[end+2:]
+			continue
+		}
+		value, rest, _ := strings.Cut(param, " ")
+		values = append(values, value)
+		param = rest
+	}
+	if len(values) == 0 {
+		return false
+	}
+
+	field.Enum = values
+	field.EnumVarNames = nil
+	field.EnumDescriptions = nil
+	return true
+}
+
+// warnf records a warning at a source position and annotation path, skipping duplicates
+func (r *Resolver) warnf(pos token.Pos, path, rule, format string, args ...any) {
+	r.warn(diagnostic.Warningf(r.position(pos), rule, path, format, args...))
+}
+
+// warn records a warning, skipping duplicates at the same position
+// (promoted fields are resolved once per embedding struct, the first path is kept)
+func (r *Resolver) warn(warning *diagnostic.Diagnostic) {
+	for _, w := range r.warnings {
+		if w.Rule == warning.Rule && w.Pos == warning.Pos && w.Message == warning.Message {
+			return
+		}
+	}
+	r.warnings = append(r.warnings, warning)
+}
+
+// at moves the annotation path of the declaration being resolved to path
+// Returns a function restoring the previous path.
+func (r *Resolver) at(path string) func() {
+	previous := r.path
+	r.path = path
+	return func() {
+		r.path = previous
+	}
+}
+
+// subpath returns the annotation path of an element of the declaration being resolved
+// (e.g. @schema[User].Email), or "" when unknown
+func (r *Resolver) subpath(name string) string {
+	if r.path == "" {
+		return ""
+	}
+	return r.path + "." + name
+}
+
+// position returns the file position of pos, or an invalid position when unknown
+func (r *Resolver) position(pos token.Pos) token.Position {
+	if !pos.IsValid() || r.pkg == nil || r.pkg.Fset == nil {
+		return token.Position{}
+	}
+	return r.pkg.Fset.Position(pos)
+}
+
+// Warnings returns the warnings collected while resolving (e.g. untranslatable validate rules,
+// unknown parameter references)
+func (r *Resolver) Warnings() []*diagnostic.Diagnostic {
+	return r.warnings
+}
@@ -0,0 +1,183 @@
+package resolver
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// xmlTag is a parsed xml struct tag (encoding/xml)
+type xmlTag struct {
+	Namespace string   // Namespace of xml:"urn:example name"
+	Name      string   // Element or attribute name, the last element of a path
+	Parents   []string // Parent elements of a path (xml:"a>b>c" has parents a and b)
+	Skip      bool     // xml:"-"
+	Attr      bool
+	CharData  bool
+	CData     bool
+	InnerXML  bool
+	Comment   bool
+	Any       bool
+}
+
+// parseXMLTag parses the xml tag of a struct tag
+// Returns false if the struct tag has no xml tag.
+func parseXMLTag(tag string) (xmlTag, bool) {
+	value, ok := reflect.StructTag(tag).Lookup("xml")
+	if !ok {
+		return xmlTag{}, false
+	}
+	if value == "-" {
+		return xmlTag{Skip: true}, true
+	}
+
+	name, options, _ := strings.Cut(value, ",")
+	var xt xmlTag
+	if namespace, local, found := strings.Cut(name, " "); found {
+		xt.Namespace = namespace
+		name = local
+	}
+	path := strings.Split(name, ">")
+	xt.Name = path[len(path)-1]
+	if len(path) > 1 {
+		xt.Parents = path[:len(path)-1]
+	}
+
+	for _, opt := range strings.Split(options, ",") {
+		switch opt {
+		case "attr":
+			xt.Attr = true
+		case "chardata":
+			xt.CharData = true
+		case "cdata":
+			xt.CData = true
+		case "innerxml":
+			xt.InnerXML = true
+		case "comment":
+			xt.Comment = true
+		case "any":
+			xt.Any = true
+		}
+	}
+	return xt, true
+}
+
+// splitXMLPrefix splits a prefixed XML name (e.g. "atom:link") into its prefix and local name
+func splitXMLPrefix(name string) (string, string) {
+	if prefix, local, found := strings.Cut(name, ":"); found {
+		return prefix, local
+	}
+	return "", name
+}
+
+// isXMLName reports whether a struct field is the XMLName field naming the element of a struct,
+// which encoding/xml does not encode as a child element
+func isXMLName(name string, t types.Type) bool {
+	if name != "XMLName" {
+		return false
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "encoding/xml" && named.Obj().Name() == "Name"
+}
+
+// xmlNameInfo returns the XML element of a struct named by its XMLName field (xml:"urn:example name"),
+// looking into embedded structs like encoding/xml
+// Returns nil if the struct has no XMLName field with a name.
+func xmlNameInfo(st *types.Struct) *XMLInfo {
+	return xmlNameInfoVisiting(st, make(map[*types.Struct]bool))
+}
+
+// xmlNameInfoVisiting returns the XML element of a struct, skipping structs already visited
+func xmlNameInfoVisiting(st *types.Struct, visiting map[*types.Struct]bool) *XMLInfo {
+	if visiting[st] {
+		return nil
+	}
+	visiting[st] = true
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !isXMLName(field.Name(), field.Type()) {
+			continue
+		}
+		xt, _ := parseXMLTag(st.Tag(i))
+		if xt.Name == "" {
+			return nil
+		}
+		info := &XMLInfo{Namespace: xt.Namespace}
+		info.Prefix, info.Name = splitXMLPrefix(xt.Name)
+		return info
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		if !st.Field(i).Embedded() {
+			continue
+		}
+		if _, embedded := embeddedStructType(st.Field(i).Type()); embedded != nil {
+			if info := xmlNameInfoVisiting(embedded, visiting); info != nil {
+				return info
+			}
+		}
+	}
+	return nil
+}
+
+// applyXMLTag sets the XML representation of a field from its xml tag: element or attribute name,
+// namespace, prefix, text content (chardata, cdata, innerxml) and wrapped arrays (xml:"items>item")
+// Names matching the property name are left empty, as they are the OpenAPI default.
+func (r *Resolver) applyXMLTag(field *ResolvedField, tag string, pos token.Pos) {
+	xt, ok := parseXMLTag(tag)
+	if !ok || xt.Skip || xt.Comment || xt.Any {
+		return
+	}
+
+	info := &XMLInfo{Namespace: xt.Namespace}
+	switch {
+	case xt.Attr:
+		info.NodeType = "attribute"
+	case xt.CharData:
+		info.NodeType = "text"
+	case xt.CData:
+		info.NodeType = "cdata"
+	case xt.InnerXML:
+		info.NodeType = "none"
+	}
+
+	name := xt.Name
+	if name == "" {
+		name = field.GoName
+	}
+	info.Prefix, name = splitXMLPrefix(name)
+
+	isArray := field.Type != nil && field.Type.Items != nil
+	switch {
+	case info.NodeType == "text" || info.NodeType == "cdata" || info.NodeType == "none":
+		// Text content has no name
+	case len(xt.Parents) == 1 && isArray:
+		info.Wrapped = true
+		info.Name = xt.Parents[0]
+		info.ItemsName = name
+	default:
+		if len(xt.Parents) > 0 {
+			r.warnf(pos, r.subpath(field.GoName), "unsupported-xml-path", "xml path %q has no OpenAPI equivalent, only the %s element is named",
+				strings.Join(append(xt.Parents, xt.Name), ">"), name)
+		}
+		if isArray && info.NodeType == "" {
+			// Unwrapped arrays repeat the item element
+			info.ItemsName = name
+		} else {
+			info.Name = name
+		}
+	}
+
+	if info.Name == field.Name {
+		info.Name = ""
+	}
+	if info.ItemsName == field.Name {
+		info.ItemsName = ""
+	}
+	if *info == (XMLInfo{}) {
+		return
+	}
+	field.XML = info
+}
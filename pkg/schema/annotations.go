@@ -30,6 +30,10 @@ var AnnotationSchema = &SchemaNode{
 					Name: "@termsOfService",
 					Type: ValueAnnotation,
 				},
+				"@self": {
+					Name: "@self",
+					Type: ValueAnnotation,
+				},
 				"@contact": {
 					Name: "@contact",
 					Type: BlockAnnotation,
@@ -140,12 +144,33 @@ var AnnotationSchema = &SchemaNode{
 							Type:              ValueAnnotation,
 							SupportsMultiline: true,
 						},
+						"@parent": {
+							Name: "@parent",
+							Type: ValueAnnotation,
+						},
+						"@kind": {
+							Name: "@kind",
+							Type: ValueAnnotation,
+						},
 					},
 				},
 				"@defaultContentType": {
 					Name: "@defaultContentType",
 					Type: ValueAnnotation,
 				},
+				"@produces": {
+					Name: "@produces",
+					Type: ValueAnnotation,
+				},
+				"@consumes": {
+					Name: "@consumes",
+					Type: ValueAnnotation,
+				},
+				"@typeMapping": {
+					Name:       "@typeMapping",
+					Type:       ValueAnnotation,
+					Repeatable: true,
+				},
 			},
 		},
 		"@endpoint": {
@@ -207,10 +232,29 @@ var AnnotationSchema = &SchemaNode{
 							Name: "@contentType",
 							Type: ValueAnnotation,
 						},
+						"@content": {
+							Name:        "@content",
+							Type:        BlockAnnotation,
+							HasMetadata: true,
+							Repeatable:  true,
+							Children: map[string]*SchemaNode{
+								"@body": {
+									Name:        "@body",
+									Type:        ValueAnnotation,
+									HasMetadata: true,
+									Repeatable:  true,
+								},
+								"@bind": {
+									Name: "@bind",
+									Type: ValueAnnotation,
+								},
+							},
+						},
 						"@body": {
 							Name:        "@body",
 							Type:        ValueAnnotation,
 							HasMetadata: true,
+							Repeatable:  true,
 						},
 						"@bind": {
 							Name: "@bind",
@@ -228,10 +272,29 @@ var AnnotationSchema = &SchemaNode{
 							Name: "@contentType",
 							Type: ValueAnnotation,
 						},
+						"@content": {
+							Name:        "@content",
+							Type:        BlockAnnotation,
+							HasMetadata: true,
+							Repeatable:  true,
+							Children: map[string]*SchemaNode{
+								"@body": {
+									Name:        "@body",
+									Type:        ValueAnnotation,
+									HasMetadata: true,
+									Repeatable:  true,
+								},
+								"@bind": {
+									Name: "@bind",
+									Type: ValueAnnotation,
+								},
+							},
+						},
 						"@body": {
 							Name:        "@body",
 							Type:        ValueAnnotation,
 							HasMetadata: true,
+							Repeatable:  true,
 						},
 						"@bind": {
 							Name: "@bind",
@@ -247,6 +310,19 @@ var AnnotationSchema = &SchemaNode{
 							Type:       ValueAnnotation,
 							Repeatable: true,
 						},
+						"@attachment": {
+							Name: "@attachment",
+							Type: ValueAnnotation,
+						},
+						"@stream": {
+							Name: "@stream",
+							Type: ValueAnnotation,
+						},
+						"@event": {
+							Name:       "@event",
+							Type:       ValueAnnotation,
+							Repeatable: true,
+						},
 					},
 				},
 			},
@@ -312,6 +388,27 @@ var AnnotationSchema = &SchemaNode{
 					Name: "@deprecated",
 					Type: FlagAnnotation,
 				},
+				"@oneOf": {
+					Name: "@oneOf",
+					Type: ValueAnnotation,
+				},
+				"@anyOf": {
+					Name: "@anyOf",
+					Type: ValueAnnotation,
+				},
+				"@discriminator": {
+					Name: "@discriminator",
+					Type: ValueAnnotation,
+				},
+				"@contentType": {
+					Name: "@contentType",
+					Type: ValueAnnotation,
+				},
+				"@header": {
+					Name:       "@header",
+					Type:       ValueAnnotation,
+					Repeatable: true,
+				},
 			},
 		},
 		"@schema": {
@@ -327,6 +424,70 @@ var AnnotationSchema = &SchemaNode{
 					Name: "@deprecated",
 					Type: FlagAnnotation,
 				},
+				"@allOf": {
+					Name: "@allOf",
+					Type: FlagAnnotation,
+				},
+				"@format": {
+					Name: "@format",
+					Type: ValueAnnotation,
+				},
+				"@example": {
+					Name: "@example",
+					Type: ValueAnnotation,
+				},
+				"@enum": {
+					Name: "@enum",
+					Type: ValueAnnotation,
+				},
+				"@default": {
+					Name: "@default",
+					Type: ValueAnnotation,
+				},
+				"@minimum": {
+					Name: "@minimum",
+					Type: ValueAnnotation,
+				},
+				"@maximum": {
+					Name: "@maximum",
+					Type: ValueAnnotation,
+				},
+				"@minLength": {
+					Name: "@minLength",
+					Type: ValueAnnotation,
+				},
+				"@maxLength": {
+					Name: "@maxLength",
+					Type: ValueAnnotation,
+				},
+				"@minItems": {
+					Name: "@minItems",
+					Type: ValueAnnotation,
+				},
+				"@maxItems": {
+					Name: "@maxItems",
+					Type: ValueAnnotation,
+				},
+				"@uniqueItems": {
+					Name: "@uniqueItems",
+					Type: FlagAnnotation,
+				},
+				"@pattern": {
+					Name: "@pattern",
+					Type: ValueAnnotation,
+				},
+				"@oneOf": {
+					Name: "@oneOf",
+					Type: ValueAnnotation,
+				},
+				"@anyOf": {
+					Name: "@anyOf",
+					Type: ValueAnnotation,
+				},
+				"@discriminator": {
+					Name: "@discriminator",
+					Type: ValueAnnotation,
+				},
 			},
 		},
 		"@path": {
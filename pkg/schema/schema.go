@@ -1,5 +1,11 @@
 package schema
 
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
 // AnnotationType defines the type of annotation
 type AnnotationType int
 
@@ -118,6 +124,92 @@ func (n *SchemaNode) GetSiblings() []string {
 	return siblings
 }
 
+// Root returns the root of the schema tree holding this node
+func (n *SchemaNode) Root() *SchemaNode {
+	for n.Parent != nil {
+		n = n.Parent
+	}
+	return n
+}
+
+// Suggest returns the child name closest to an unknown annotation name,
+// or "" when no child is close enough to be a likely typo (e.g. @descripton -> @description)
+func (n *SchemaNode) Suggest(name string) string {
+	// Allow one edit per three letters, and at least one
+	maxDistance := max(1, (len(name)-1)/3)
+
+	suggestion := ""
+	best := maxDistance + 1
+	for _, child := range sortedKeys(n.Children) {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(child))
+		if distance < best {
+			suggestion, best = child, distance
+		}
+	}
+	return suggestion
+}
+
+// FindParents returns the paths (e.g. "@endpoint > @request") of the descendants
+// of this node that accept the annotation name as a child, sorted
+func (n *SchemaNode) FindParents(name string) []string {
+	var paths []string
+	var walk func(node *SchemaNode, path []string)
+	walk = func(node *SchemaNode, path []string) {
+		for _, childName := range sortedKeys(node.Children) {
+			child := node.Children[childName]
+			childPath := append(slices.Clone(path), childName)
+			if child.HasChild(name) {
+				paths = append(paths, FormatAnnotationPath(childPath))
+			}
+			walk(child, childPath)
+		}
+	}
+	walk(n, nil)
+
+	sort.Strings(paths)
+	return slices.Compact(paths)
+}
+
+// sortedKeys returns the names of the children, sorted
+func sortedKeys(children map[string]*SchemaNode) []string {
+	names := make([]string, 0, len(children))
+	for name := range children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and
+// transpositions of adjacent letters turning a into b (optimal string alignment)
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// d[i][j] is the distance between the first i runes of a and the first j runes of b
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
 // CanBeEmpty returns true if the block can be empty.
 // A block can be empty if it has no required children.
 func (n *SchemaNode) CanBeEmpty() bool {
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -102,6 +103,78 @@ func TestSchemaNode_HasChild(t *testing.T) {
 	}
 }
 
+func TestSchemaNode_Suggest(t *testing.T) {
+	fieldNode := AnnotationSchema.GetChild("@field")
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"@descripton", "@description"},
+		{"@minLenght", "@minLength"},
+		{"@MinLength", "@minLength"},
+		{"@fromat", "@format"},
+		{"@exmaple", "@example"},
+		{"@xyzzy", ""},
+		{"@desc", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldNode.Suggest(tt.name); got != tt.want {
+				t.Errorf("Suggest(%s) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaNode_FindParents(t *testing.T) {
+	tests := []struct {
+		node string
+		name string
+		want []string
+	}{
+		{"@endpoint", "@body", []string{"@request", "@request > @content", "@response", "@response > @content"}},
+		{"@field", "@body", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.node+" "+tt.name, func(t *testing.T) {
+			got := AnnotationSchema.GetChild(tt.node).FindParents(tt.name)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("FindParents(%s) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if got := AnnotationSchema.FindParents("@format"); strings.Join(got, ", ") != "@field, @schema" {
+		t.Errorf("FindParents(@format) = %v, want [@field @schema]", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"format", "format", 0},
+		{"", "abc", 3},
+		{"descripton", "description", 1},
+		{"minlenght", "minlength", 1},
+		{"sumary", "summary", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := editDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSchemaNode_IsSibling(t *testing.T) {
 	parent := &SchemaNode{
 		Name: "parent",
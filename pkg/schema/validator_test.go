@@ -382,9 +382,10 @@ func TestGetChildrenNames(t *testing.T) {
 
 	expectedAPI := map[string]bool{
 		"@title": true, "@version": true, "@description": true,
-		"@termsOfService": true, "@contact": true, "@license": true,
+		"@termsOfService": true, "@self": true, "@contact": true, "@license": true,
 		"@server": true, "@securityScheme": true, "@security": true,
-		"@tag": true, "@defaultContentType": true,
+		"@tag": true, "@defaultContentType": true, "@produces": true, "@consumes": true,
+		"@typeMapping": true,
 	}
 
 	for _, child := range apiChildren {
@@ -395,8 +396,8 @@ func TestGetChildrenNames(t *testing.T) {
 
 	// Test @schema (block annotation with children)
 	schemaChildren := GetChildrenNames("@schema")
-	if len(schemaChildren) != 2 {
-		t.Errorf("@schema should have 2 children, got %d", len(schemaChildren))
+	if len(schemaChildren) != 18 {
+		t.Errorf("@schema should have 18 children, got %d", len(schemaChildren))
 	}
 
 	// Test marker annotation (should have no children)
@@ -1,61 +1,75 @@
 package validator
 
 import (
+	"cmp"
 	"fmt"
+	"go/token"
+	"net/url"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/wontaeyang/go-specgen/pkg/diagnostic"
 	"github.com/wontaeyang/go-specgen/pkg/resolver"
 )
 
 // Validator validates business rules for the resolved package
 type Validator struct {
-	errors []error
+	errors  []error
+	version string         // Target OpenAPI version: "3.0", "3.1" or "3.2"
+	pos     token.Position // Position of the declaration being validated
 }
 
 // ValidationError represents a validation error
-type ValidationError struct {
-	Message string
-	Path    string
-}
-
-func (e *ValidationError) Error() string {
-	if e.Path != "" {
-		return fmt.Sprintf("%s: %s", e.Path, e.Message)
-	}
-	return e.Message
-}
+type ValidationError = diagnostic.Diagnostic
 
-// NewValidator creates a new validator
+// NewValidator creates a new validator targeting OpenAPI 3.0
 func NewValidator() *Validator {
 	return &Validator{
-		errors: make([]error, 0),
+		errors:  make([]error, 0),
+		version: "3.0",
 	}
 }
 
+// SetVersion sets the target OpenAPI version ("3.0", "3.1" or "3.2")
+// Features of later versions, such as the QUERY method in 3.2, are rejected.
+func (v *Validator) SetVersion(version string) {
+	v.version = version
+}
+
 // Validate validates the resolved package
 func (v *Validator) Validate(pkg *resolver.ResolvedPackage) error {
 	v.errors = make([]error, 0)
 
 	// Validate API
+	v.pos = token.Position{}
 	if pkg.API == nil {
-		v.addError("", "missing @api annotation")
+		v.addError("", "missing-api", "missing @api annotation")
 	} else {
+		v.pos = pkg.API.Position
 		v.validateAPI(pkg.API)
 	}
 
 	// Validate schemas
-	for name, schema := range pkg.Schemas {
+	for _, name := range sortedNames(pkg.Schemas) {
+		schema := pkg.Schemas[name]
+		v.pos = schema.Position
 		v.validateSchema(name, schema)
+		v.validateCompositions(name, schema, pkg.Schemas)
+		v.validatePartEncodings(fmt.Sprintf("@schema[%s]", name), schema.Fields, pkg.Parameters)
 	}
 
 	// Validate parameters
-	for name, param := range pkg.Parameters {
+	for _, name := range sortedNames(pkg.Parameters) {
+		param := pkg.Parameters[name]
+		v.pos = param.Position
 		v.validateParameter(name, param)
 	}
 
 	// Validate endpoints
-	for _, endpoint := range pkg.Endpoints {
+	for _, endpoint := range sortedEndpoints(pkg.Endpoints) {
+		v.pos = endpoint.Position
 		v.validateEndpoint(endpoint, pkg)
 	}
 
@@ -68,38 +82,63 @@ func (v *Validator) Validate(pkg *resolver.ResolvedPackage) error {
 }
 
 // MultiError contains multiple validation errors
-type MultiError struct {
-	Errors []error
+type MultiError = diagnostic.MultiError
+
+// addError adds a validation error at the current position
+// rule identifies the check (e.g. unknown-schema), path the annotation
+func (v *Validator) addError(path, rule, message string) {
+	v.errors = append(v.errors, &ValidationError{
+		Pos:      v.pos,
+		Severity: diagnostic.SeverityError,
+		Rule:     rule,
+		Path:     path,
+		Message:  message,
+	})
 }
 
-func (e *MultiError) Error() string {
-	if len(e.Errors) == 1 {
-		return e.Errors[0].Error()
+// at moves the current position to pos when known (e.g. to a field of a schema)
+// Returns a function restoring the previous position.
+func (v *Validator) at(pos token.Position) func() {
+	previous := v.pos
+	if pos.IsValid() {
+		v.pos = pos
 	}
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("%d validation errors:\n", len(e.Errors)))
-	for i, err := range e.Errors {
-		sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, err.Error()))
+	return func() {
+		v.pos = previous
 	}
-	return sb.String()
 }
 
-// addError adds a validation error
-func (v *Validator) addError(path, message string) {
-	v.errors = append(v.errors, &ValidationError{
-		Path:    path,
-		Message: message,
+// sortedEndpoints returns the endpoints in source order, so errors are reported deterministically
+func sortedEndpoints(endpoints []*resolver.ResolvedEndpoint) []*resolver.ResolvedEndpoint {
+	sorted := slices.Clone(endpoints)
+	slices.SortStableFunc(sorted, func(a, b *resolver.ResolvedEndpoint) int {
+		return cmp.Or(
+			strings.Compare(a.Position.Filename, b.Position.Filename),
+			cmp.Compare(a.Position.Line, b.Position.Line),
+			strings.Compare(a.Method+" "+a.Path, b.Method+" "+b.Path),
+		)
 	})
+	return sorted
+}
+
+// sortedNames returns the keys of a map in sorted order, so errors are reported deterministically
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // validateAPI validates API info
 func (v *Validator) validateAPI(api *resolver.ResolvedAPI) {
 	if api.Title == "" {
-		v.addError("@api", "missing required @title")
+		v.addError("@api", "missing-title", "missing required @title")
 	}
 
 	if api.Version == "" {
-		v.addError("@api", "missing required @version")
+		v.addError("@api", "missing-version", "missing required @version")
 	}
 
 	// Validate security schemes
@@ -113,11 +152,63 @@ func (v *Validator) validateAPI(api *resolver.ResolvedAPI) {
 			if _, ok := api.SecuritySchemes[req.SchemeName]; !ok {
 				v.addError(
 					fmt.Sprintf("@api.@security[%d].@with[%d]", i, j),
+					"unknown-security-scheme",
 					fmt.Sprintf("references unknown security scheme: %s", req.SchemeName),
 				)
 			}
 		}
 	}
+
+	if api.Self != "" {
+		v.requireVersion("@api.@self", "$self", "3.2")
+		if u, err := url.Parse(api.Self); err != nil {
+			v.addError("@api.@self", "invalid-uri", fmt.Sprintf("invalid URI: %s", api.Self))
+		} else if u.Fragment != "" {
+			v.addError("@api.@self", "invalid-uri", fmt.Sprintf("URI must not have a fragment: %s", api.Self))
+		}
+	}
+
+	v.validateTags(api.Tags)
+}
+
+// validateTags validates the tag hierarchy: parents must be defined tags, without cycles
+func (v *Validator) validateTags(tags []*resolver.Tag) {
+	parents := make(map[string]string)
+	for _, tag := range tags {
+		parents[tag.Name] = tag.Parent
+	}
+
+	for _, tag := range tags {
+		path := fmt.Sprintf("@api.@tag[%s]", tag.Name)
+		if tag.Kind != "" {
+			v.requireVersion(path, "@kind", "3.2")
+		}
+		if tag.Parent == "" {
+			continue
+		}
+		v.requireVersion(path, "@parent", "3.2")
+
+		if _, ok := parents[tag.Parent]; !ok {
+			v.addError(path, "unknown-tag", fmt.Sprintf("references unknown parent tag: %s", tag.Parent))
+			continue
+		}
+		// Follow the parents until the top of the hierarchy, or back to the tag
+		seen := map[string]bool{tag.Name: true}
+		for parent := tag.Parent; parent != ""; parent = parents[parent] {
+			if seen[parent] {
+				v.addError(path, "circular-tags", fmt.Sprintf("circular parent tags: %s", tag.Parent))
+				break
+			}
+			seen[parent] = true
+		}
+	}
+}
+
+// requireVersion reports an error when a feature needs a later OpenAPI version than the target
+func (v *Validator) requireVersion(path, feature, version string) {
+	if v.version < version {
+		v.addError(path, "unsupported-version", fmt.Sprintf("%s requires OpenAPI %s (targeting %s)", feature, version, v.version))
+	}
 }
 
 // validateSecurityScheme validates a security scheme
@@ -125,26 +216,26 @@ func (v *Validator) validateSecurityScheme(name string, scheme *resolver.Securit
 	path := fmt.Sprintf("@api.@securityScheme[%s]", name)
 
 	if scheme.Type == "" {
-		v.addError(path, "missing required @type")
+		v.addError(path, "invalid-security-scheme", "missing required @type")
 		return
 	}
 
 	switch scheme.Type {
 	case "http":
 		if scheme.Scheme == "" {
-			v.addError(path, "http security scheme missing @scheme")
+			v.addError(path, "invalid-security-scheme", "http security scheme missing @scheme")
 		}
 	case "apiKey":
 		if scheme.In == "" {
-			v.addError(path, "apiKey security scheme missing @in")
+			v.addError(path, "invalid-security-scheme", "apiKey security scheme missing @in")
 		}
 		if scheme.ParameterName == "" {
-			v.addError(path, "apiKey security scheme missing @name")
+			v.addError(path, "invalid-security-scheme", "apiKey security scheme missing @name")
 		}
 	case "oauth2", "openIdConnect":
 		// These would require additional fields we haven't implemented yet
 	default:
-		v.addError(path, fmt.Sprintf("unknown security scheme type: %s", scheme.Type))
+		v.addError(path, "invalid-security-scheme", fmt.Sprintf("unknown security scheme type: %s", scheme.Type))
 	}
 }
 
@@ -152,8 +243,14 @@ func (v *Validator) validateSecurityScheme(name string, scheme *resolver.Securit
 func (v *Validator) validateSchema(name string, schema *resolver.ResolvedSchema) {
 	path := fmt.Sprintf("@schema[%s]", name)
 
+	// Named non-struct types are validated like a single field
+	if schema.Value != nil {
+		v.validateField(path, schema.Value)
+		return
+	}
+
 	if len(schema.Fields) == 0 {
-		v.addError(path, "schema has no fields")
+		v.addError(path, "empty-schema", "schema has no fields")
 	}
 
 	// Validate each field
@@ -165,18 +262,95 @@ func (v *Validator) validateSchema(name string, schema *resolver.ResolvedSchema)
 	fieldNames := make(map[string]bool)
 	for _, field := range schema.Fields {
 		if fieldNames[field.Name] {
-			v.addError(path, fmt.Sprintf("duplicate field name: %s", field.Name))
+			restore := v.at(field.Position)
+			v.addError(path, "duplicate-field", fmt.Sprintf("duplicate field name: %s", field.Name))
+			restore()
 		}
 		fieldNames[field.Name] = true
 	}
 }
 
+// validateCompositions validates the polymorphic fields of a schema
+func (v *Validator) validateCompositions(name string, schema *resolver.ResolvedSchema, schemas map[string]*resolver.ResolvedSchema) {
+	path := fmt.Sprintf("@schema[%s]", name)
+
+	if schema.Value != nil {
+		v.validateComposition(path, fieldComposition(schema.Value), schemas)
+	}
+	for _, field := range schema.Fields {
+		restore := v.at(field.Position)
+		v.validateComposition(fmt.Sprintf("%s.%s", path, field.GoName), fieldComposition(field), schemas)
+		restore()
+	}
+}
+
+// fieldComposition returns the composition of a field or, for arrays and maps, of its innermost element
+func fieldComposition(field *resolver.ResolvedField) *resolver.Composition {
+	if field.Composition == nil && field.Type != nil {
+		return field.Type.Innermost().Composition
+	}
+	return field.Composition
+}
+
+// validateComposition validates the alternatives and discriminator of a polymorphic value
+// The discriminator property must be a required property of every alternative.
+func (v *Validator) validateComposition(path string, c *resolver.Composition, schemas map[string]*resolver.ResolvedSchema) {
+	if c == nil {
+		return
+	}
+
+	if len(c.Schemas) == 0 {
+		v.addError(path, "empty-composition", fmt.Sprintf("%s has no schemas: no @schema type implements the interface", c.Kind))
+		return
+	}
+
+	alternatives := make(map[string]bool)
+	for _, name := range c.Schemas {
+		alternatives[name] = true
+		if _, ok := schemas[name]; !ok {
+			v.addError(path, "unknown-schema", fmt.Sprintf("@%s references unknown schema: %s", c.Kind, name))
+		}
+	}
+
+	d := c.Discriminator
+	if d == nil {
+		return
+	}
+
+	for _, m := range d.Mapping {
+		if m.Schema == "" {
+			v.addError(path, "invalid-discriminator", fmt.Sprintf("invalid @discriminator mapping %q, expected value=Schema", m.Value))
+		} else if !alternatives[m.Schema] {
+			v.addError(path, "invalid-discriminator", fmt.Sprintf("@discriminator maps %q to %s, which is not one of the @%s schemas", m.Value, m.Schema, c.Kind))
+		}
+	}
+
+	for _, name := range c.Schemas {
+		schema, ok := schemas[name]
+		if !ok {
+			continue
+		}
+
+		var property *resolver.ResolvedField
+		for _, field := range schema.Fields {
+			if field.Name == d.PropertyName {
+				property = field
+			}
+		}
+		if property == nil {
+			v.addError(path, "invalid-discriminator", fmt.Sprintf("discriminator property %q not found in schema %s", d.PropertyName, name))
+		} else if !property.Required {
+			v.addError(path, "invalid-discriminator", fmt.Sprintf("discriminator property %q must be required in schema %s", d.PropertyName, name))
+		}
+	}
+}
+
 // validateParameter validates a parameter struct
 func (v *Validator) validateParameter(name string, param *resolver.ResolvedParameter) {
 	path := fmt.Sprintf("@%s[%s]", param.Type, name)
 
 	if len(param.Fields) == 0 {
-		v.addError(path, "parameter has no fields")
+		v.addError(path, "empty-parameter", "parameter has no fields")
 	}
 
 	// Validate each field
@@ -188,7 +362,9 @@ func (v *Validator) validateParameter(name string, param *resolver.ResolvedParam
 	fieldNames := make(map[string]bool)
 	for _, field := range param.Fields {
 		if fieldNames[field.Name] {
-			v.addError(path, fmt.Sprintf("duplicate field name: %s", field.Name))
+			restore := v.at(field.Position)
+			v.addError(path, "duplicate-field", fmt.Sprintf("duplicate field name: %s", field.Name))
+			restore()
 		}
 		fieldNames[field.Name] = true
 	}
@@ -196,11 +372,12 @@ func (v *Validator) validateParameter(name string, param *resolver.ResolvedParam
 
 // validateField validates a schema field
 func (v *Validator) validateField(path string, field *resolver.ResolvedField) {
+	defer v.at(field.Position)()
 	fieldPath := fmt.Sprintf("%s.%s", path, field.GoName)
 
 	// Check for unresolved struct types (named structs not marked with @schema)
 	if field.IsUnresolvedStruct {
-		v.addError(fieldPath, fmt.Sprintf(
+		v.addError(fieldPath, "unresolved-struct", fmt.Sprintf(
 			"field references struct '%s' which is not a @schema. Add @schema annotation to %s or use an anonymous struct",
 			field.UnresolvedTypeName, field.UnresolvedTypeName,
 		))
@@ -213,84 +390,85 @@ func (v *Validator) validateField(path string, field *resolver.ResolvedField) {
 			// OK - enum supported for string and integer types
 		case "array":
 			if field.ItemsType != "string" && field.ItemsType != "integer" {
-				v.addError(fieldPath, "enum for arrays only supported with string or integer items")
+				v.addError(fieldPath, "invalid-enum", "enum for arrays only supported with string or integer items")
 			}
 		default:
-			v.addError(fieldPath, "enum only supported for string, integer, or array types")
+			v.addError(fieldPath, "invalid-enum", "enum only supported for string, integer, or array types")
 		}
 	}
 
 	// Validate min/max constraints
 	if field.Minimum != nil && field.Maximum != nil {
 		if *field.Minimum > *field.Maximum {
-			v.addError(fieldPath, "minimum cannot be greater than maximum")
+			v.addError(fieldPath, "invalid-range", "minimum cannot be greater than maximum")
 		}
 	}
 
 	// Validate minLength/maxLength constraints
 	if field.MinLength != nil && field.MaxLength != nil {
 		if *field.MinLength > *field.MaxLength {
-			v.addError(fieldPath, "minLength cannot be greater than maxLength")
+			v.addError(fieldPath, "invalid-range", "minLength cannot be greater than maxLength")
 		}
 	}
 
 	// Validate length constraints are for strings
 	if (field.MinLength != nil || field.MaxLength != nil) && field.OpenAPIType != "string" {
-		v.addError(fieldPath, "minLength/maxLength only valid for string types")
+		v.addError(fieldPath, "invalid-constraint", "minLength/maxLength only valid for string types")
 	}
 
 	// Validate minItems/maxItems constraints
 	if field.MinItems != nil && field.MaxItems != nil {
 		if *field.MinItems > *field.MaxItems {
-			v.addError(fieldPath, "minItems cannot be greater than maxItems")
+			v.addError(fieldPath, "invalid-range", "minItems cannot be greater than maxItems")
 		}
 	}
 
 	// Validate items constraints are for arrays
 	if (field.MinItems != nil || field.MaxItems != nil) && field.OpenAPIType != "array" {
-		v.addError(fieldPath, "minItems/maxItems only valid for array types")
+		v.addError(fieldPath, "invalid-constraint", "minItems/maxItems only valid for array types")
 	}
 
 	// Validate uniqueItems is for arrays
 	if field.UniqueItems && field.OpenAPIType != "array" {
-		v.addError(fieldPath, "uniqueItems only valid for array types")
+		v.addError(fieldPath, "invalid-constraint", "uniqueItems only valid for array types")
 	}
 
 	// Validate pattern is for strings
 	if field.Pattern != "" && field.OpenAPIType != "string" {
-		v.addError(fieldPath, "pattern only valid for string types")
+		v.addError(fieldPath, "invalid-constraint", "pattern only valid for string types")
 	}
 
 	// Validate pattern is valid regex
 	if field.Pattern != "" {
 		if _, err := regexp.Compile(field.Pattern); err != nil {
-			v.addError(fieldPath, fmt.Sprintf("invalid pattern regex: %v", err))
+			v.addError(fieldPath, "invalid-pattern", fmt.Sprintf("invalid pattern regex: %v", err))
 		}
 	}
 }
 
 // validateParameterField validates a parameter field with type-specific rules
 func (v *Validator) validateParameterField(path, paramType string, field *resolver.ResolvedField) {
+	defer v.at(field.Position)()
 	fieldPath := fmt.Sprintf("%s.%s", path, field.GoName)
 
 	// Path parameters cannot be pointers/nullable
 	if paramType == "path" && field.Nullable {
-		v.addError(fieldPath, "path parameters cannot be nullable (no pointer types)")
+		v.addError(fieldPath, "invalid-parameter", "path parameters cannot be nullable (no pointer types)")
 	}
 
 	// Path parameters cannot be arrays
 	if paramType == "path" && field.IsArray {
-		v.addError(fieldPath, "path parameters cannot be arrays")
+		v.addError(fieldPath, "invalid-parameter", "path parameters cannot be arrays")
 	}
 
 	// Header parameters cannot be arrays
 	if paramType == "header" && field.IsArray {
-		v.addError(fieldPath, "header parameters cannot be arrays")
+		v.addError(fieldPath, "invalid-parameter", "header parameters cannot be arrays")
 	}
 
 	// Cookie parameters cannot be arrays
 	if paramType == "cookie" && field.IsArray {
-		v.addError(fieldPath, "cookie parameters cannot be arrays")
+		v.addError(fieldPath, "invalid-parameter", "cookie parameters cannot be arrays")
 	}
 
 	// Query parameters can be arrays (this is allowed)
@@ -299,22 +477,33 @@ func (v *Validator) validateParameterField(path, paramType string, field *resolv
 	v.validateField(path, field)
 }
 
+// methodPattern matches HTTP methods outside the fixed ones of OpenAPI (e.g. PURGE, COPY)
+var methodPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_-]*$`)
+
+// validateMethod validates the HTTP method of an endpoint
+// OpenAPI 3.2 adds the QUERY method, and other methods as additionalOperations.
+func (v *Validator) validateMethod(path, method string) {
+	switch {
+	case slices.Contains([]string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE"}, method):
+	case method == "QUERY":
+		v.requireVersion(path, "QUERY method", "3.2")
+	case !methodPattern.MatchString(method):
+		v.addError(path, "invalid-method", fmt.Sprintf("invalid HTTP method: %s", method))
+	case v.version < "3.2":
+		v.addError(path, "invalid-method", fmt.Sprintf("invalid HTTP method: %s (custom methods require OpenAPI 3.2)", method))
+	}
+}
+
 // validateEndpoint validates an endpoint
 func (v *Validator) validateEndpoint(endpoint *resolver.ResolvedEndpoint, pkg *resolver.ResolvedPackage) {
 	path := fmt.Sprintf("@endpoint[%s %s]", endpoint.Method, endpoint.Path)
 
 	// Validate method
-	validMethods := map[string]bool{
-		"GET": true, "POST": true, "PUT": true, "PATCH": true,
-		"DELETE": true, "HEAD": true, "OPTIONS": true,
-	}
-	if !validMethods[endpoint.Method] {
-		v.addError(path, fmt.Sprintf("invalid HTTP method: %s", endpoint.Method))
-	}
+	v.validateMethod(path, endpoint.Method)
 
 	// Validate path
 	if endpoint.Path == "" {
-		v.addError(path, "missing path")
+		v.addError(path, "missing-path", "missing path")
 	} else {
 		v.validatePath(path, endpoint.Path)
 	}
@@ -329,15 +518,18 @@ func (v *Validator) validateEndpoint(endpoint *resolver.ResolvedEndpoint, pkg *r
 	if endpoint.Request != nil {
 		v.validateRequestBody(path, endpoint.Request, pkg.Schemas)
 	}
+	if endpoint.InlineRequest != nil {
+		v.validatePartEncodings(path, endpoint.InlineRequest.Fields, pkg.Parameters)
+	}
 
 	// Validate responses (including inline responses)
 	hasResponses := len(endpoint.Responses) > 0 || len(endpoint.InlineResponses) > 0
 	if !hasResponses {
-		v.addError(path, "endpoint must have at least one response")
+		v.addError(path, "missing-response", "endpoint must have at least one response")
 	}
 
-	for statusCode, response := range endpoint.Responses {
-		v.validateResponse(path, statusCode, response, pkg.Schemas)
+	for _, statusCode := range sortedNames(endpoint.Responses) {
+		v.validateResponse(path, statusCode, endpoint.Responses[statusCode], pkg.Schemas)
 	}
 	// Inline responses don't need schema validation (they have inline fields)
 
@@ -353,7 +545,7 @@ func (v *Validator) validateEndpoint(endpoint *resolver.ResolvedEndpoint, pkg *r
 // validatePath validates the path format
 func (v *Validator) validatePath(endpointPath, path string) {
 	if !strings.HasPrefix(path, "/") {
-		v.addError(endpointPath, "path must start with /")
+		v.addError(endpointPath, "invalid-path", "path must start with /")
 	}
 
 	// Validate path variables are in {var} format
@@ -362,11 +554,11 @@ func (v *Validator) validatePath(endpointPath, path string) {
 	for _, match := range matches {
 		varName := match[1]
 		if varName == "" {
-			v.addError(endpointPath, "empty path variable")
+			v.addError(endpointPath, "invalid-path", "empty path variable")
 		}
 		// Check for valid variable name (alphanumeric and underscore)
 		if !regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`).MatchString(varName) {
-			v.addError(endpointPath, fmt.Sprintf("invalid path variable name: %s", varName))
+			v.addError(endpointPath, "invalid-path", fmt.Sprintf("invalid path variable name: %s", varName))
 		}
 	}
 }
@@ -401,14 +593,14 @@ func (v *Validator) validatePathParameters(path string, pathVars []string, param
 	// Check that all path variables have corresponding parameters
 	for _, varName := range pathVars {
 		if !paramFieldMap[varName] {
-			v.addError(path, fmt.Sprintf("path variable {%s} has no corresponding @path parameter", varName))
+			v.addError(path, "missing-path-parameter", fmt.Sprintf("path variable {%s} has no corresponding @path parameter", varName))
 		}
 	}
 
 	// Check that all path parameters are used in the path
 	for paramName := range paramFieldMap {
 		if !pathVarMap[paramName] {
-			v.addError(path, fmt.Sprintf("@path parameter %s not used in path", paramName))
+			v.addError(path, "unused-path-parameter", fmt.Sprintf("@path parameter %s not used in path", paramName))
 		}
 	}
 }
@@ -438,37 +630,81 @@ func (v *Validator) validatePathParametersWithInline(path string, pathVars []str
 	// Check that all path variables have corresponding parameters
 	for _, varName := range pathVars {
 		if !paramFieldMap[varName] {
-			v.addError(path, fmt.Sprintf("path variable {%s} has no corresponding @path parameter", varName))
+			v.addError(path, "missing-path-parameter", fmt.Sprintf("path variable {%s} has no corresponding @path parameter", varName))
 		}
 	}
 
 	// Check that all path parameters are used in the path
 	for paramName := range paramFieldMap {
 		if !pathVarMap[paramName] {
-			v.addError(path, fmt.Sprintf("@path parameter %s not used in path", paramName))
+			v.addError(path, "unused-path-parameter", fmt.Sprintf("@path parameter %s not used in path", paramName))
 		}
 	}
 }
 
 // validateRequestBody validates a request body
 func (v *Validator) validateRequestBody(path string, request *resolver.ResolvedRequestBody, schemas map[string]*resolver.ResolvedSchema) {
+	requestPath := path + ".@request"
 	if request.ContentType == "" {
-		v.addError(path+".@request", "missing @contentType")
+		v.addError(requestPath, "missing-content-type", "missing @contentType")
 	}
 
-	if request.Body == nil || request.Body.Schema == "" {
-		v.addError(path+".@request", "missing @body")
-		return
+	mediaTypes := request.MediaTypes()
+	v.validateContentTypes(requestPath, mediaTypes)
+
+	validated := make(map[*resolver.ResolvedBody]bool)
+	for _, mediaType := range mediaTypes {
+		if mediaType.Body == nil || mediaType.Body.Schema == "" {
+			if len(mediaTypes) > 1 {
+				v.addError(requestPath, "missing-body", fmt.Sprintf("missing @body for %s", mediaType.ContentType))
+			} else {
+				v.addError(requestPath, "missing-body", "missing @body")
+			}
+			continue
+		}
+		v.validateBodySchemas(requestPath, mediaType.Body, schemas, validated)
+	}
+}
+
+// validatePartEncodings validates that the part headers of fields reference @header structs
+func (v *Validator) validatePartEncodings(path string, fields []*resolver.ResolvedField, parameters map[string]*resolver.ResolvedParameter) {
+	for _, field := range fields {
+		if field.Encoding == nil {
+			continue
+		}
+		for _, ref := range field.Encoding.HeaderParams {
+			if param, ok := parameters[ref]; !ok || param.Type != "header" {
+				v.addError(path, "unknown-parameter", fmt.Sprintf("field %s: part header %s is not a @header struct", field.Name, ref))
+			}
+		}
+	}
+}
+
+// validateContentTypes checks that a request body or response lists each content type once
+func (v *Validator) validateContentTypes(path string, mediaTypes []*resolver.ResolvedContent) {
+	seen := make(map[string]bool)
+	for _, mediaType := range mediaTypes {
+		if mediaType.ContentType == "" {
+			continue
+		}
+		if seen[mediaType.ContentType] {
+			v.addError(path, "duplicate-content-type", fmt.Sprintf("duplicate content type: %s", mediaType.ContentType))
+		}
+		seen[mediaType.ContentType] = true
 	}
+}
 
-	// Validate schema exists (use ElementType which extracts the base type from []T or map[string]T)
-	schemaToCheck := request.Body.ElementType
-	if schemaToCheck == "" {
-		schemaToCheck = request.Body.Schema
+// validateBodySchemas checks that the schemas a body references exist (every alternative of User | Organization)
+// Bodies shared by several content types are validated once.
+func (v *Validator) validateBodySchemas(path string, body *resolver.ResolvedBody, schemas map[string]*resolver.ResolvedSchema, validated map[*resolver.ResolvedBody]bool) {
+	if validated[body] {
+		return
 	}
-	if !isPrimitiveType(schemaToCheck) {
+	validated[body] = true
+
+	for _, schemaToCheck := range bodySchemaRefs(body) {
 		if _, ok := schemas[schemaToCheck]; !ok {
-			v.addError(path+".@request", fmt.Sprintf("references unknown schema: %s", schemaToCheck))
+			v.addError(path, "unknown-schema", fmt.Sprintf("references unknown schema: %s", schemaToCheck))
 		}
 	}
 }
@@ -479,22 +715,79 @@ func (v *Validator) validateResponse(path, statusCode string, response *resolver
 
 	// Validate status code is numeric
 	if !regexp.MustCompile(`^\d{3}$`).MatchString(statusCode) {
-		v.addError(responsePath, fmt.Sprintf("invalid status code: %s", statusCode))
+		v.addError(responsePath, "invalid-status-code", fmt.Sprintf("invalid status code: %s", statusCode))
 	}
 
+	mediaTypes := response.MediaTypes()
+	v.validateContentTypes(responsePath, mediaTypes)
+
 	// Schema is optional for responses (e.g., 204 No Content)
-	if response.Body != nil && response.Body.Schema != "" {
-		// Validate schema exists (use ElementType which extracts the base type from []T or map[string]T)
-		schemaToCheck := response.Body.ElementType
-		if schemaToCheck == "" {
-			schemaToCheck = response.Body.Schema
-		}
-		if !isPrimitiveType(schemaToCheck) {
-			if _, ok := schemas[schemaToCheck]; !ok {
-				v.addError(responsePath, fmt.Sprintf("references unknown schema: %s", schemaToCheck))
+	validated := make(map[*resolver.ResolvedBody]bool)
+	for _, mediaType := range mediaTypes {
+		if mediaType.Body != nil && mediaType.Body.Schema != "" {
+			v.validateBodySchemas(responsePath, mediaType.Body, schemas, validated)
+		}
+	}
+
+	v.validateStream(responsePath, response, schemas, validated)
+}
+
+// validateStream validates the @stream format and @event annotations of a response
+func (v *Validator) validateStream(path string, response *resolver.ResolvedResponse, schemas map[string]*resolver.ResolvedSchema, validated map[*resolver.ResolvedBody]bool) {
+	mediaTypes := response.MediaTypes()
+	if response.Stream != "" && !slices.ContainsFunc(mediaTypes, func(m *resolver.ResolvedContent) bool { return m.Stream != "" }) {
+		v.addError(path, "invalid-stream", fmt.Sprintf("unknown stream format: %s (use sse, ndjson, jsonl or json-seq)", response.Stream))
+	}
+	if len(response.Events) == 0 {
+		return
+	}
+
+	if !slices.ContainsFunc(mediaTypes, func(m *resolver.ResolvedContent) bool { return m.Stream == "sse" }) {
+		v.addError(path, "invalid-stream", "@event requires a Server-Sent Events stream (@stream sse)")
+	}
+	names := make(map[string]bool)
+	for _, event := range response.Events {
+		if names[event.Name] {
+			v.addError(path, "duplicate-event", fmt.Sprintf("duplicate event: %s", event.Name))
+		}
+		names[event.Name] = true
+
+		if event.Body == nil {
+			v.addError(path, "missing-event-schema", fmt.Sprintf("missing schema for event %s (use @event %s Schema)", event.Name, event.Name))
+			continue
+		}
+		v.validateBodySchemas(path, event.Body, schemas, validated)
+	}
+}
+
+// bodySchemaRefs returns the schemas a body references, skipping primitive and any-value bodies
+// Nested containers (e.g. map[string][]User) reference their innermost element type,
+// and alternatives (User | Organization) reference one schema each.
+func bodySchemaRefs(body *resolver.ResolvedBody) []string {
+	if body.Type != nil {
+		alternatives := body.Type.OneOf
+		if len(alternatives) == 0 {
+			alternatives = []*resolver.TypeDescriptor{body.Type}
+		}
+
+		var refs []string
+		for _, alternative := range alternatives {
+			if ref := alternative.Innermost().Ref; ref != "" {
+				refs = append(refs, ref)
 			}
 		}
+		return refs
+	}
+
+	// Bodies without a type descriptor: ElementType extracts the base type from []T or map[string]T
+	schema := body.ElementType
+	if schema == "" {
+		schema = body.Schema
+	}
+	if isPrimitiveType(schema) {
+		return nil
 	}
+	return []string{schema}
 }
 
 // isPrimitiveType checks if a type name is a Go primitive (no schema lookup needed)
@@ -515,7 +808,7 @@ func (v *Validator) validateParameterConflicts(path string, endpoint *resolver.R
 	for _, param := range endpoint.PathParams {
 		for _, field := range param.Fields {
 			if prevType, exists := allParams[field.Name]; exists {
-				v.addError(path, fmt.Sprintf("parameter name conflict: %s appears in both %s and path parameters", field.Name, prevType))
+				v.addError(path, "parameter-conflict", fmt.Sprintf("parameter name conflict: %s appears in both %s and path parameters", field.Name, prevType))
 			}
 			allParams[field.Name] = "path"
 		}
@@ -524,7 +817,7 @@ func (v *Validator) validateParameterConflicts(path string, endpoint *resolver.R
 	for _, param := range endpoint.QueryParams {
 		for _, field := range param.Fields {
 			if prevType, exists := allParams[field.Name]; exists {
-				v.addError(path, fmt.Sprintf("parameter name conflict: %s appears in both %s and query parameters", field.Name, prevType))
+				v.addError(path, "parameter-conflict", fmt.Sprintf("parameter name conflict: %s appears in both %s and query parameters", field.Name, prevType))
 			}
 			allParams[field.Name] = "query"
 		}
@@ -533,7 +826,7 @@ func (v *Validator) validateParameterConflicts(path string, endpoint *resolver.R
 	for _, param := range endpoint.HeaderParams {
 		for _, field := range param.Fields {
 			if prevType, exists := allParams[field.Name]; exists {
-				v.addError(path, fmt.Sprintf("parameter name conflict: %s appears in both %s and header parameters", field.Name, prevType))
+				v.addError(path, "parameter-conflict", fmt.Sprintf("parameter name conflict: %s appears in both %s and header parameters", field.Name, prevType))
 			}
 			allParams[field.Name] = "header"
 		}
@@ -542,7 +835,7 @@ func (v *Validator) validateParameterConflicts(path string, endpoint *resolver.R
 	for _, param := range endpoint.CookieParams {
 		for _, field := range param.Fields {
 			if prevType, exists := allParams[field.Name]; exists {
-				v.addError(path, fmt.Sprintf("parameter name conflict: %s appears in both %s and cookie parameters", field.Name, prevType))
+				v.addError(path, "parameter-conflict", fmt.Sprintf("parameter name conflict: %s appears in both %s and cookie parameters", field.Name, prevType))
 			}
 			allParams[field.Name] = "cookie"
 		}
@@ -560,7 +853,7 @@ func (v *Validator) validateEndpointTags(path string, endpointTags []string, api
 	// Check each endpoint tag
 	for _, tagName := range endpointTags {
 		if !definedTags[tagName] {
-			v.addError(path, fmt.Sprintf("endpoint uses undefined tag: %s (define it at API level with @tag)", tagName))
+			v.addError(path, "unknown-tag", fmt.Sprintf("endpoint uses undefined tag: %s (define it at API level with @tag)", tagName))
 		}
 	}
 }
@@ -1,9 +1,11 @@
 package validator
 
 import (
+	"go/token"
 	"strings"
 	"testing"
 
+	"github.com/wontaeyang/go-specgen/pkg/diagnostic"
 	"github.com/wontaeyang/go-specgen/pkg/resolver"
 )
 
@@ -273,6 +275,101 @@ func TestValidator_ValidateSchema(t *testing.T) {
 	}
 }
 
+func TestValidator_ValidateCompositions(t *testing.T) {
+	card := &resolver.ResolvedSchema{
+		Name: "Card",
+		Fields: []*resolver.ResolvedField{
+			{Name: "type", GoName: "Type", OpenAPIType: "string", Required: true},
+		},
+	}
+	wallet := &resolver.ResolvedSchema{
+		Name: "Wallet",
+		Fields: []*resolver.ResolvedField{
+			{Name: "type", GoName: "Type", OpenAPIType: "string"},
+		},
+	}
+
+	discriminator := func(mapping ...*resolver.DiscriminatorMapping) *resolver.Discriminator {
+		return &resolver.Discriminator{PropertyName: "type", Mapping: mapping}
+	}
+
+	tests := []struct {
+		name        string
+		composition *resolver.Composition
+		errMsg      string
+	}{
+		{
+			name:        "valid oneOf",
+			composition: &resolver.Composition{Kind: "oneOf", Schemas: []string{"Card"}, Discriminator: discriminator(&resolver.DiscriminatorMapping{Value: "card", Schema: "Card"})},
+		},
+		{
+			name:        "valid anyOf without discriminator",
+			composition: &resolver.Composition{Kind: "anyOf", Schemas: []string{"Card", "Wallet"}},
+		},
+		{
+			name:        "no implementations",
+			composition: &resolver.Composition{Kind: "oneOf"},
+			errMsg:      "no @schema type implements",
+		},
+		{
+			name:        "unknown schema",
+			composition: &resolver.Composition{Kind: "anyOf", Schemas: []string{"Card", "Cash"}},
+			errMsg:      "@anyOf references unknown schema: Cash",
+		},
+		{
+			name:        "malformed mapping",
+			composition: &resolver.Composition{Kind: "oneOf", Schemas: []string{"Card"}, Discriminator: discriminator(&resolver.DiscriminatorMapping{Value: "card"})},
+			errMsg:      "expected value=Schema",
+		},
+		{
+			name:        "mapping outside alternatives",
+			composition: &resolver.Composition{Kind: "oneOf", Schemas: []string{"Card"}, Discriminator: discriminator(&resolver.DiscriminatorMapping{Value: "wallet", Schema: "Wallet"})},
+			errMsg:      "not one of the @oneOf schemas",
+		},
+		{
+			name:        "optional discriminator property",
+			composition: &resolver.Composition{Kind: "oneOf", Schemas: []string{"Card", "Wallet"}, Discriminator: discriminator()},
+			errMsg:      `"type" must be required in schema Wallet`,
+		},
+		{
+			name:        "missing discriminator property",
+			composition: &resolver.Composition{Kind: "oneOf", Schemas: []string{"Card"}, Discriminator: &resolver.Discriminator{PropertyName: "kind"}},
+			errMsg:      `"kind" not found in schema Card`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &resolver.ResolvedSchema{
+				Name: "Order",
+				Fields: []*resolver.ResolvedField{
+					{Name: "payment", GoName: "Payment", IsAnyValue: true, Composition: tt.composition},
+				},
+			}
+			pkg := &resolver.ResolvedPackage{
+				API:        &resolver.ResolvedAPI{Title: "Test", Version: "1.0.0"},
+				Schemas:    map[string]*resolver.ResolvedSchema{"Card": card, "Wallet": wallet, "Order": order},
+				Parameters: map[string]*resolver.ResolvedParameter{},
+				Endpoints:  []*resolver.ResolvedEndpoint{},
+			}
+
+			err := NewValidator().Validate(pkg)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want mention of %s", err, tt.errMsg)
+			}
+			if err != nil && !strings.Contains(err.Error(), "@schema[Order].Payment") {
+				t.Errorf("error should point at the field, got: %v", err)
+			}
+		})
+	}
+}
+
 func TestValidator_ValidateField(t *testing.T) {
 	minVal := 0.0
 	maxVal := 100.0
@@ -871,6 +968,109 @@ func TestValidator_ValidateRequestBody(t *testing.T) {
 			wantErr: true,
 			errMsg:  "unknown schema",
 		},
+		{
+			name: "unknown alternative",
+			request: &resolver.ResolvedRequestBody{
+				ContentType: "application/json",
+				Body: &resolver.ResolvedBody{
+					Schema: "User | []Org | string",
+					Type: &resolver.TypeDescriptor{OneOf: []*resolver.TypeDescriptor{
+						{OpenAPIType: "object", Ref: "User"},
+						{OpenAPIType: "array", Items: &resolver.TypeDescriptor{OpenAPIType: "object", Ref: "Org"}},
+						{OpenAPIType: "string"},
+					}},
+				},
+			},
+			schemas: map[string]*resolver.ResolvedSchema{
+				"User": {Name: "User"},
+			},
+			wantErr: true,
+			errMsg:  "unknown schema: Org",
+		},
+		{
+			name: "known alternatives",
+			request: &resolver.ResolvedRequestBody{
+				ContentType: "application/json",
+				Body: &resolver.ResolvedBody{
+					Schema: "User | string",
+					Type: &resolver.TypeDescriptor{OneOf: []*resolver.TypeDescriptor{
+						{OpenAPIType: "object", Ref: "User"},
+						{OpenAPIType: "string"},
+					}},
+				},
+			},
+			schemas: map[string]*resolver.ResolvedSchema{
+				"User": {
+					Name: "User",
+					Fields: []*resolver.ResolvedField{
+						{Name: "id", GoName: "ID", OpenAPIType: "string"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "several content types",
+			request: &resolver.ResolvedRequestBody{
+				ContentType: "application/json",
+				Body:        &resolver.ResolvedBody{Schema: "User"},
+				Content: []*resolver.ResolvedContent{
+					{ContentType: "application/json", Body: &resolver.ResolvedBody{Schema: "User"}},
+					{ContentType: "text/csv", Body: &resolver.ResolvedBody{Schema: "string"}},
+				},
+			},
+			schemas: map[string]*resolver.ResolvedSchema{
+				"User": {
+					Name: "User",
+					Fields: []*resolver.ResolvedField{
+						{Name: "id", GoName: "ID", OpenAPIType: "string"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate content type",
+			request: &resolver.ResolvedRequestBody{
+				ContentType: "application/json",
+				Body:        &resolver.ResolvedBody{Schema: "string"},
+				Content: []*resolver.ResolvedContent{
+					{ContentType: "application/json", Body: &resolver.ResolvedBody{Schema: "string"}},
+					{ContentType: "application/json", Body: &resolver.ResolvedBody{Schema: "string"}},
+				},
+			},
+			schemas: map[string]*resolver.ResolvedSchema{},
+			wantErr: true,
+			errMsg:  "duplicate content type: application/json",
+		},
+		{
+			name: "content type without body",
+			request: &resolver.ResolvedRequestBody{
+				ContentType: "application/json",
+				Body:        &resolver.ResolvedBody{Schema: "string"},
+				Content: []*resolver.ResolvedContent{
+					{ContentType: "application/json", Body: &resolver.ResolvedBody{Schema: "string"}},
+					{ContentType: "application/xml"},
+				},
+			},
+			schemas: map[string]*resolver.ResolvedSchema{},
+			wantErr: true,
+			errMsg:  "missing @body for application/xml",
+		},
+		{
+			name: "unknown schema of second content type",
+			request: &resolver.ResolvedRequestBody{
+				ContentType: "application/json",
+				Body:        &resolver.ResolvedBody{Schema: "string"},
+				Content: []*resolver.ResolvedContent{
+					{ContentType: "application/json", Body: &resolver.ResolvedBody{Schema: "string"}},
+					{ContentType: "application/xml", Body: &resolver.ResolvedBody{Schema: "UserXML"}},
+				},
+			},
+			schemas: map[string]*resolver.ResolvedSchema{},
+			wantErr: true,
+			errMsg:  "unknown schema: UserXML",
+		},
 	}
 
 	for _, tt := range tests {
@@ -931,7 +1131,7 @@ func TestMultiError_Error(t *testing.T) {
 				&ValidationError{Message: "error 1"},
 				&ValidationError{Message: "error 2"},
 			},
-			want: "2 validation errors",
+			want: "2 errors",
 		},
 	}
 
@@ -946,6 +1146,61 @@ func TestMultiError_Error(t *testing.T) {
 	}
 }
 
+func TestValidator_Validate_Diagnostics(t *testing.T) {
+	schemaPos := token.Position{Filename: "users.go", Line: 10, Column: 1}
+	fieldPos := token.Position{Filename: "users.go", Line: 14, Column: 2}
+	endpointPos := token.Position{Filename: "users.go", Line: 30, Column: 1}
+
+	pkg := &resolver.ResolvedPackage{
+		API: &resolver.ResolvedAPI{
+			Title:    "Test API",
+			Version:  "1.0.0",
+			Position: token.Position{Filename: "api.go", Line: 1, Column: 1},
+		},
+		Schemas: map[string]*resolver.ResolvedSchema{
+			"User": {
+				Name:     "User",
+				Position: schemaPos,
+				Fields: []*resolver.ResolvedField{
+					{Name: "id", GoName: "ID", OpenAPIType: "string"},
+					{Name: "age", GoName: "Age", OpenAPIType: "integer", Pattern: "^[0-9]+$", Position: fieldPos},
+				},
+			},
+		},
+		Parameters: map[string]*resolver.ResolvedParameter{},
+		Endpoints: []*resolver.ResolvedEndpoint{
+			{
+				Method:   "GET",
+				Path:     "/users",
+				Position: endpointPos,
+				Responses: map[string]*resolver.ResolvedResponse{
+					"200": {StatusCode: "200", Body: &resolver.ResolvedBody{Schema: "Usr"}},
+				},
+			},
+		},
+	}
+
+	err := NewValidator().Validate(pkg)
+	diagnostics := diagnostic.List(err)
+	if len(diagnostics) != 2 {
+		t.Fatalf("Validate() error = %v, want 2 diagnostics", err)
+	}
+
+	want := []struct {
+		pos  token.Position
+		rule string
+		path string
+	}{
+		{pos: fieldPos, rule: "invalid-constraint", path: "@schema[User].Age"},
+		{pos: endpointPos, rule: "unknown-schema", path: "@endpoint[GET /users].@response[200]"},
+	}
+	for i, d := range diagnostics {
+		if d.Pos != want[i].pos || d.Rule != want[i].rule || d.Path != want[i].path || d.Severity != diagnostic.SeverityError {
+			t.Errorf("diagnostic[%d] = %s, want %s at %s in %s", i, d, want[i].rule, want[i].pos, want[i].path)
+		}
+	}
+}
+
 func TestValidator_ValidateEndpointTags(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1134,3 +1389,237 @@ func TestValidator_ValidateEndpointWithTags(t *testing.T) {
 		})
 	}
 }
+
+func TestValidator_ValidatePartEncodings(t *testing.T) {
+	parameters := map[string]*resolver.ResolvedParameter{
+		"PartHeaders": {Name: "PartHeaders", Type: "header", Fields: []*resolver.ResolvedField{{Name: "X-Checksum", OpenAPIType: "string"}}},
+		"Filter":      {Name: "Filter", Type: "query", Fields: []*resolver.ResolvedField{{Name: "q", OpenAPIType: "string"}}},
+	}
+
+	tests := []struct {
+		name    string
+		headers []string
+		wantErr string
+	}{
+		{name: "header struct", headers: []string{"PartHeaders"}},
+		{name: "unknown struct", headers: []string{"Missing"}, wantErr: "field avatar: part header Missing is not a @header struct"},
+		{name: "query struct", headers: []string{"Filter"}, wantErr: "field avatar: part header Filter is not a @header struct"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			fields := []*resolver.ResolvedField{
+				{Name: "avatar", OpenAPIType: "string", Format: "binary", Encoding: &resolver.PartEncoding{HeaderParams: tt.headers}},
+			}
+			v.validatePartEncodings("@schema[UploadForm]", fields, parameters)
+
+			if tt.wantErr == "" {
+				if len(v.errors) > 0 {
+					t.Errorf("unexpected errors: %v", v.errors)
+				}
+				return
+			}
+			if len(v.errors) != 1 || !strings.Contains(v.errors[0].Error(), tt.wantErr) {
+				t.Errorf("errors = %v, want %q", v.errors, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateStream(t *testing.T) {
+	schemas := map[string]*resolver.ResolvedSchema{
+		"Message":  {Name: "Message"},
+		"Progress": {Name: "Progress"},
+	}
+	progress := &resolver.ResolvedEvent{Name: "progress", Body: &resolver.ResolvedBody{Schema: "Progress"}}
+
+	tests := []struct {
+		name     string
+		response *resolver.ResolvedResponse
+		wantErr  string
+	}{
+		{
+			name: "sse with events",
+			response: &resolver.ResolvedResponse{
+				StatusCode: "200", Stream: "sse", Events: []*resolver.ResolvedEvent{progress},
+				Content: []*resolver.ResolvedContent{{ContentType: "text/event-stream", Stream: "sse", Events: []*resolver.ResolvedEvent{progress}}},
+			},
+		},
+		{
+			name: "unknown stream format",
+			response: &resolver.ResolvedResponse{
+				StatusCode: "200", Stream: "websocket",
+				Content: []*resolver.ResolvedContent{{ContentType: "websocket", Body: &resolver.ResolvedBody{Schema: "Message"}}},
+			},
+			wantErr: "unknown stream format: websocket",
+		},
+		{
+			name: "events without sse",
+			response: &resolver.ResolvedResponse{
+				StatusCode: "200", ContentType: "application/json", Events: []*resolver.ResolvedEvent{progress},
+			},
+			wantErr: "@event requires a Server-Sent Events stream",
+		},
+		{
+			name: "event without schema",
+			response: &resolver.ResolvedResponse{
+				StatusCode: "200", Stream: "sse", Events: []*resolver.ResolvedEvent{{Name: "done"}},
+				Content: []*resolver.ResolvedContent{{ContentType: "text/event-stream", Stream: "sse"}},
+			},
+			wantErr: "missing schema for event done",
+		},
+		{
+			name: "duplicate event",
+			response: &resolver.ResolvedResponse{
+				StatusCode: "200", Stream: "sse", Events: []*resolver.ResolvedEvent{progress, progress},
+				Content: []*resolver.ResolvedContent{{ContentType: "text/event-stream", Stream: "sse"}},
+			},
+			wantErr: "duplicate event: progress",
+		},
+		{
+			name: "unknown event schema",
+			response: &resolver.ResolvedResponse{
+				StatusCode: "200", Stream: "sse", Events: []*resolver.ResolvedEvent{{Name: "done", Body: &resolver.ResolvedBody{Schema: "Done"}}},
+				Content: []*resolver.ResolvedContent{{ContentType: "text/event-stream", Stream: "sse"}},
+			},
+			wantErr: "Done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.validateStream("@endpoint[GET /events].@response[200]", tt.response, schemas, make(map[*resolver.ResolvedBody]bool))
+
+			if tt.wantErr == "" {
+				if len(v.errors) > 0 {
+					t.Errorf("unexpected errors: %v", v.errors)
+				}
+				return
+			}
+			if len(v.errors) != 1 || !strings.Contains(v.errors[0].Error(), tt.wantErr) {
+				t.Errorf("errors = %v, want %q", v.errors, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateMethod(t *testing.T) {
+	tests := []struct {
+		method  string
+		version string
+		wantErr string
+	}{
+		{method: "GET", version: "3.0"},
+		{method: "TRACE", version: "3.0"},
+		{method: "QUERY", version: "3.2"},
+		{method: "QUERY", version: "3.1", wantErr: "QUERY method requires OpenAPI 3.2 (targeting 3.1)"},
+		{method: "PURGE", version: "3.2"},
+		{method: "PURGE", version: "3.0", wantErr: "invalid HTTP method: PURGE (custom methods require OpenAPI 3.2)"},
+		{method: "get", version: "3.2", wantErr: "invalid HTTP method: get"},
+		{method: "GET /users", version: "3.2", wantErr: "invalid HTTP method"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.version, func(t *testing.T) {
+			v := NewValidator()
+			v.SetVersion(tt.version)
+			v.validateMethod("@endpoint[X /users]", tt.method)
+
+			if tt.wantErr == "" {
+				if len(v.errors) > 0 {
+					t.Errorf("unexpected errors: %v", v.errors)
+				}
+				return
+			}
+			if len(v.errors) != 1 || !strings.Contains(v.errors[0].Error(), tt.wantErr) {
+				t.Errorf("errors = %v, want %q", v.errors, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateAPI_Version32(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		api     *resolver.ResolvedAPI
+		wantErr string
+	}{
+		{
+			name:    "tag hierarchy",
+			version: "3.2",
+			api: &resolver.ResolvedAPI{Tags: []*resolver.Tag{
+				{Name: "account", Kind: "nav"},
+				{Name: "billing", Parent: "account", Kind: "nav"},
+			}},
+		},
+		{
+			name:    "parent before 3.2",
+			version: "3.1",
+			api: &resolver.ResolvedAPI{Tags: []*resolver.Tag{
+				{Name: "account"},
+				{Name: "billing", Parent: "account"},
+			}},
+			wantErr: "@api.@tag[billing]: @parent requires OpenAPI 3.2",
+		},
+		{
+			name:    "kind before 3.2",
+			version: "3.0",
+			api:     &resolver.ResolvedAPI{Tags: []*resolver.Tag{{Name: "account", Kind: "nav"}}},
+			wantErr: "@kind requires OpenAPI 3.2",
+		},
+		{
+			name:    "unknown parent",
+			version: "3.2",
+			api:     &resolver.ResolvedAPI{Tags: []*resolver.Tag{{Name: "billing", Parent: "account"}}},
+			wantErr: "references unknown parent tag: account",
+		},
+		{
+			name:    "circular parents",
+			version: "3.2",
+			api: &resolver.ResolvedAPI{Tags: []*resolver.Tag{
+				{Name: "account", Parent: "account"},
+			}},
+			wantErr: "circular parent tags: account",
+		},
+		{
+			name:    "self",
+			version: "3.2",
+			api:     &resolver.ResolvedAPI{Self: "https://api.example.com/openapi.yaml"},
+		},
+		{
+			name:    "self before 3.2",
+			version: "3.1",
+			api:     &resolver.ResolvedAPI{Self: "https://api.example.com/openapi.yaml"},
+			wantErr: "$self requires OpenAPI 3.2",
+		},
+		{
+			name:    "self with fragment",
+			version: "3.2",
+			api:     &resolver.ResolvedAPI{Self: "https://api.example.com/openapi.yaml#top"},
+			wantErr: "URI must not have a fragment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator()
+			v.SetVersion(tt.version)
+			tt.api.Title = "Test API"
+			tt.api.Version = "1.0.0"
+			v.validateAPI(tt.api)
+
+			if tt.wantErr == "" {
+				if len(v.errors) > 0 {
+					t.Errorf("unexpected errors: %v", v.errors)
+				}
+				return
+			}
+			if len(v.errors) != 1 || !strings.Contains(v.errors[0].Error(), tt.wantErr) {
+				t.Errorf("errors = %v, want %q", v.errors, tt.wantErr)
+			}
+		})
+	}
+}